@@ -40,8 +40,59 @@ type Config struct {
 	DefaultAdminPassword  string `mapstructure:"DEFAULT_ADMIN_PASSWORD"`
 	DefaultAdminFirstName string `mapstructure:"DEFAULT_ADMIN_FIRST_NAME"`
 	DefaultAdminLastName  string `mapstructure:"DEFAULT_ADMIN_LAST_NAME"`
+	// Public base URL of this API, used to build links in emails (e.g., unsubscribe links)
+	AppBaseURL string `mapstructure:"APP_BASE_URL"`
+	// Secret used to sign one-click unsubscribe links
+	UnsubscribeSecret string `mapstructure:"UNSUBSCRIBE_SECRET"`
+	// Email provider bounce/complaint webhooks
+	EmailWebhookSecret   string `mapstructure:"EMAIL_WEBHOOK_SECRET"`
+	EmailSoftBounceLimit int    `mapstructure:"EMAIL_SOFT_BOUNCE_LIMIT"`
+
+	// Direct messaging between roles
+	MessagingRules           string `mapstructure:"MESSAGING_RULES"`
+	MessageRateLimit         int    `mapstructure:"MESSAGE_RATE_LIMIT"`
+	MessageRateWindowMinutes int    `mapstructure:"MESSAGE_RATE_WINDOW_MINUTES"`
+	// Abuse reports
+	ReportSuspendThreshold   int `mapstructure:"REPORT_SUSPEND_THRESHOLD"`
+	ReportSuspendWindowHours int `mapstructure:"REPORT_SUSPEND_WINDOW_HOURS"`
+
+	// File storage for message attachments
+	StorageBackend   string `mapstructure:"STORAGE_BACKEND"`
+	StorageLocalPath string `mapstructure:"STORAGE_LOCAL_PATH"`
+	S3Endpoint       string `mapstructure:"S3_ENDPOINT"`
+	S3AccessKey      string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey      string `mapstructure:"S3_SECRET_KEY"`
+	S3Bucket         string `mapstructure:"S3_BUCKET"`
+	S3Region         string `mapstructure:"S3_REGION"`
+	S3UseSSL         bool   `mapstructure:"S3_USE_SSL"`
+	// Message attachments
+	AttachmentMaxSizeMB     int      `mapstructure:"ATTACHMENT_MAX_SIZE_MB"`
+	AttachmentAllowedTypes  []string `mapstructure:"ATTACHMENT_ALLOWED_TYPES"`
+	AttachmentURLTTLMinutes int      `mapstructure:"ATTACHMENT_URL_TTL_MINUTES"`
+	AttachmentURLSecret     string   `mapstructure:"ATTACHMENT_URL_SECRET"`
+	ClamAVAddress           string   `mapstructure:"CLAMAV_ADDRESS"`
+	// Background imports
+	ImportMaxSizeMB int `mapstructure:"IMPORT_MAX_SIZE_MB"`
+	// School and institution images
+	MediaMaxSizeMB int    `mapstructure:"MEDIA_MAX_SIZE_MB"`
+	MediaBaseURL   string `mapstructure:"MEDIA_BASE_URL"` // Prefix of image URLs, e.g. a CDN in front of the storage bucket
+	// Geocoding of school addresses
+	GeocoderProvider   string `mapstructure:"GEOCODER_PROVIDER"`
+	GeocoderURL        string `mapstructure:"GEOCODER_URL"`
+	GeocoderUserAgent  string `mapstructure:"GEOCODER_USER_AGENT"`
+	GeocoderStaticFile string `mapstructure:"GEOCODER_STATIC_FILE"`
 }
 
+// DefaultAttachmentAllowedTypes are the attachment MIME types accepted when ATTACHMENT_ALLOWED_TYPES is not set.
+const DefaultAttachmentAllowedTypes = "application/pdf,image/jpeg,image/png,image/gif,image/webp,text/plain," +
+	"application/msword,application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// DefaultMessagingRules lets families contact each other and schools, and educators contact institutions.
+// Institutions can message educators who applied to their jobs; anyone can reply to a user who wrote to them.
+const DefaultMessagingRules = "parent>parent,parent>institution,parent>training_center," +
+	"educator>institution,educator>training_center," +
+	"institution>educator:applicants,training_center>educator:applicants,admin>*"
+
 // LoadConfig reads configuration from file or environment variables.
 func LoadConfig() (*Config, error) {
 	viper.AddConfigPath(".")
@@ -212,5 +263,194 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// Email link configuration
+	if config.AppBaseURL == "" {
+		config.AppBaseURL = os.Getenv("APP_BASE_URL")
+		if config.AppBaseURL == "" {
+			config.AppBaseURL = "http://localhost:8080" // Default base URL for local development
+			log.Println("Warning: APP_BASE_URL not set. Using default value:", config.AppBaseURL)
+		}
+	}
+	config.AppBaseURL = strings.TrimRight(config.AppBaseURL, "/")
+	if config.UnsubscribeSecret == "" {
+		config.UnsubscribeSecret = os.Getenv("UNSUBSCRIBE_SECRET")
+		if config.UnsubscribeSecret == "" {
+			config.UnsubscribeSecret = config.JWTSecret // Fall back to the JWT secret
+			log.Println("Warning: UNSUBSCRIBE_SECRET not set. Falling back to JWT_SECRET for signing unsubscribe links.")
+		}
+	}
+
+	// Email bounce/complaint webhook configuration
+	if config.EmailWebhookSecret == "" {
+		config.EmailWebhookSecret = os.Getenv("EMAIL_WEBHOOK_SECRET")
+		if config.EmailWebhookSecret == "" {
+			log.Println("Warning: EMAIL_WEBHOOK_SECRET is not set. Email bounce and complaint webhooks are disabled.")
+		}
+	}
+	if config.EmailSoftBounceLimit == 0 {
+		limitStr := os.Getenv("EMAIL_SOFT_BOUNCE_LIMIT")
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			config.EmailSoftBounceLimit = parsedLimit
+		} else {
+			config.EmailSoftBounceLimit = 3 // Suppress after three soft bounces by default
+		}
+	}
+
+	// File storage configuration
+	if config.StorageBackend == "" {
+		config.StorageBackend = os.Getenv("STORAGE_BACKEND")
+		if config.StorageBackend == "" {
+			config.StorageBackend = "local"
+		}
+	}
+	if config.StorageLocalPath == "" {
+		config.StorageLocalPath = os.Getenv("STORAGE_LOCAL_PATH")
+		if config.StorageLocalPath == "" {
+			config.StorageLocalPath = "./uploads"
+		}
+	}
+	if config.S3Endpoint == "" {
+		config.S3Endpoint = os.Getenv("S3_ENDPOINT")
+	}
+	if config.S3AccessKey == "" {
+		config.S3AccessKey = os.Getenv("S3_ACCESS_KEY")
+	}
+	if config.S3SecretKey == "" {
+		config.S3SecretKey = os.Getenv("S3_SECRET_KEY")
+	}
+	if config.S3Bucket == "" {
+		config.S3Bucket = os.Getenv("S3_BUCKET")
+	}
+	if config.S3Region == "" {
+		config.S3Region = os.Getenv("S3_REGION")
+	}
+	if s3UseSSLStr := os.Getenv("S3_USE_SSL"); s3UseSSLStr != "" {
+		config.S3UseSSL = s3UseSSLStr == "true" || s3UseSSLStr == "1"
+	}
+	if config.StorageBackend == "s3" && (config.S3Endpoint == "" || config.S3Bucket == "") {
+		log.Println("Warning: STORAGE_BACKEND is s3 but S3_ENDPOINT or S3_BUCKET is not set. Message attachments will be unavailable.")
+	}
+
+	// Message attachment configuration
+	if config.AttachmentMaxSizeMB == 0 {
+		if parsedSize, err := strconv.Atoi(os.Getenv("ATTACHMENT_MAX_SIZE_MB")); err == nil && parsedSize > 0 {
+			config.AttachmentMaxSizeMB = parsedSize
+		} else {
+			config.AttachmentMaxSizeMB = 10
+		}
+	}
+	if config.ImportMaxSizeMB == 0 {
+		if parsedSize, err := strconv.Atoi(os.Getenv("IMPORT_MAX_SIZE_MB")); err == nil && parsedSize > 0 {
+			config.ImportMaxSizeMB = parsedSize
+		} else {
+			config.ImportMaxSizeMB = 50
+		}
+	}
+	if config.MediaMaxSizeMB == 0 {
+		if parsedSize, err := strconv.Atoi(os.Getenv("MEDIA_MAX_SIZE_MB")); err == nil && parsedSize > 0 {
+			config.MediaMaxSizeMB = parsedSize
+		} else {
+			config.MediaMaxSizeMB = 15
+		}
+	}
+	if config.MediaBaseURL == "" {
+		config.MediaBaseURL = os.Getenv("MEDIA_BASE_URL")
+		if config.MediaBaseURL == "" {
+			config.MediaBaseURL = "/media" // Served by the API from the file storage
+		}
+	}
+	config.MediaBaseURL = strings.TrimSuffix(config.MediaBaseURL, "/")
+	if len(config.AttachmentAllowedTypes) == 0 {
+		allowedTypes := os.Getenv("ATTACHMENT_ALLOWED_TYPES")
+		if allowedTypes == "" {
+			allowedTypes = DefaultAttachmentAllowedTypes
+		}
+		for _, contentType := range strings.Split(allowedTypes, ",") {
+			if contentType = strings.TrimSpace(strings.ToLower(contentType)); contentType != "" {
+				config.AttachmentAllowedTypes = append(config.AttachmentAllowedTypes, contentType)
+			}
+		}
+	}
+	if config.AttachmentURLTTLMinutes == 0 {
+		if parsedTTL, err := strconv.Atoi(os.Getenv("ATTACHMENT_URL_TTL_MINUTES")); err == nil && parsedTTL > 0 {
+			config.AttachmentURLTTLMinutes = parsedTTL
+		} else {
+			config.AttachmentURLTTLMinutes = 15
+		}
+	}
+	if config.AttachmentURLSecret == "" {
+		config.AttachmentURLSecret = os.Getenv("ATTACHMENT_URL_SECRET")
+		if config.AttachmentURLSecret == "" {
+			config.AttachmentURLSecret = config.JWTSecret // Fall back to the JWT secret
+			log.Println("Warning: ATTACHMENT_URL_SECRET not set. Falling back to JWT_SECRET for signing attachment download links.")
+		}
+	}
+	if config.ClamAVAddress == "" {
+		config.ClamAVAddress = os.Getenv("CLAMAV_ADDRESS")
+		if config.ClamAVAddress == "" {
+			log.Println("Warning: CLAMAV_ADDRESS is not set. Message attachments will not be scanned for viruses.")
+		}
+	}
+
+	// Geocoding configuration
+	if config.GeocoderProvider == "" {
+		config.GeocoderProvider = os.Getenv("GEOCODER_PROVIDER")
+		if config.GeocoderProvider == "" {
+			config.GeocoderProvider = "none"
+			log.Println("Warning: GEOCODER_PROVIDER is not set. School addresses will not be geocoded.")
+		}
+	}
+	if config.GeocoderURL == "" {
+		config.GeocoderURL = os.Getenv("GEOCODER_URL")
+		if config.GeocoderURL == "" {
+			config.GeocoderURL = "https://nominatim.openstreetmap.org"
+		}
+	}
+	if config.GeocoderUserAgent == "" {
+		config.GeocoderUserAgent = os.Getenv("GEOCODER_USER_AGENT")
+		if config.GeocoderUserAgent == "" {
+			config.GeocoderUserAgent = "mwc-backend/1.0 (" + config.EmailFrom + ")"
+		}
+	}
+	if config.GeocoderStaticFile == "" {
+		config.GeocoderStaticFile = os.Getenv("GEOCODER_STATIC_FILE")
+	}
+
+	// Messaging configuration
+	if config.MessagingRules == "" {
+		config.MessagingRules = os.Getenv("MESSAGING_RULES")
+		if config.MessagingRules == "" {
+			config.MessagingRules = DefaultMessagingRules
+		}
+	}
+	if config.MessageRateLimit == 0 {
+		if parsedLimit, err := strconv.Atoi(os.Getenv("MESSAGE_RATE_LIMIT")); err == nil && parsedLimit > 0 {
+			config.MessageRateLimit = parsedLimit
+		} else {
+			config.MessageRateLimit = 20 // Messages one user may send to the same recipient per window
+		}
+	}
+	if config.MessageRateWindowMinutes == 0 {
+		if parsedWindow, err := strconv.Atoi(os.Getenv("MESSAGE_RATE_WINDOW_MINUTES")); err == nil && parsedWindow > 0 {
+			config.MessageRateWindowMinutes = parsedWindow
+		} else {
+			config.MessageRateWindowMinutes = 60
+		}
+	}
+	if config.ReportSuspendThreshold == 0 {
+		if parsedThreshold, err := strconv.Atoi(os.Getenv("REPORT_SUSPEND_THRESHOLD")); err == nil && parsedThreshold > 0 {
+			config.ReportSuspendThreshold = parsedThreshold
+		} else {
+			config.ReportSuspendThreshold = 5 // Distinct users reporting the same sender within the window
+		}
+	}
+	if config.ReportSuspendWindowHours == 0 {
+		if parsedWindow, err := strconv.Atoi(os.Getenv("REPORT_SUSPEND_WINDOW_HOURS")); err == nil && parsedWindow > 0 {
+			config.ReportSuspendWindowHours = parsedWindow
+		} else {
+			config.ReportSuspendWindowHours = 24
+		}
+	}
+
 	return &config, nil
 }
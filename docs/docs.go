@@ -440,25 +440,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/reviews/pending": {
+        "/api/v1/admin/import-jobs": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves all reviews that are currently in 'pending' status, awaiting admin moderation.",
+                "description": "Retrieves background import jobs with their progress, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin",
-                    "reviews"
+                    "schools"
+                ],
+                "summary": "List import jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status (queued, processing, completed, failed, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get pending reviews",
                 "responses": {
                     "200": {
-                        "description": "List of pending reviews",
+                        "description": "List of import jobs with pagination metadata",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -474,7 +496,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - only administrators can view pending reviews",
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -494,53 +516,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/reviews/{review_id}/moderate": {
-            "put": {
+        "/api/v1/admin/import-jobs/{job_id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Allows an admin to approve or reject a pending review.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves the status and progress of a background import. The same data is pushed over the WebSocket of the admin who uploaded the file as ` + "`" + `import_job_progress` + "`" + ` messages while the job runs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin",
-                    "reviews"
+                    "schools"
                 ],
-                "summary": "Moderate a review",
+                "summary": "Get an import job",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Review ID",
-                        "name": "review_id",
+                        "description": "Import job ID",
+                        "name": "job_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Moderation details (status and optional notes)",
-                        "name": "moderation",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ModerateReviewRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Review moderated successfully",
+                        "description": "Import job",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.ImportJob"
                         }
                     },
                     "400": {
-                        "description": "Bad request or invalid review ID/request body",
+                        "description": "Invalid import job ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -558,7 +567,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - only administrators can moderate reviews",
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -567,7 +576,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Review not found",
+                        "description": "Import job not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -587,14 +596,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/schools": {
-            "get": {
+        "/api/v1/admin/import-jobs/{job_id}/cancel": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of schools filtered by country code with pagination",
+                "description": "Cancels a queued import, or stops a running import after the current batch. Schools imported before the cancellation are kept.",
                 "produces": [
                     "application/json"
                 ],
@@ -602,40 +611,25 @@ const docTemplate = `{
                     "admin",
                     "schools"
                 ],
-                "summary": "Get schools by country",
+                "summary": "Cancel an import job",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Country code (e.g., US, UK)",
-                        "name": "country_code",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number for pagination",
-                        "name": "page",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
-                        "default": 10,
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Import job ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of schools with pagination metadata",
+                        "description": "Import job cancelled",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.ImportJob"
                         }
                     },
                     "400": {
-                        "description": "Bad request - missing country_code",
+                        "description": "Invalid import job ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -652,6 +646,33 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Import job not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Import job already finished",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -664,44 +685,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/schools/batch-upload": {
-            "post": {
+        "/api/v1/admin/import-jobs/{job_id}/errors": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Upload multiple schools from a JSON file",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Downloads a CSV with the columns row, field, value and error for every rejected row of a finished import",
                 "produces": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "tags": [
                     "admin",
                     "schools"
                 ],
-                "summary": "Batch upload schools",
+                "summary": "Download the error report of an import job",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "JSON file containing school data",
-                        "name": "schools_file",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Import job ID",
+                        "name": "job_id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Schools uploaded successfully",
+                        "description": "Error report",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid import job ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -718,6 +735,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Import job not found or without rejected rows",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -726,61 +761,70 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "File storage unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/api/v1/admin/schools/{id}": {
-            "put": {
+        "/api/v1/admin/message-reports": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates an existing school's information",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves message reports for moderation, oldest first, with the reporter, the reported user and a copy of the reported message",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin",
-                    "schools"
+                    "messages"
                 ],
-                "summary": "Update school information",
+                "summary": "List message reports",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "default": "pending",
+                        "description": "Filter by status (pending, dismissed, actioned)",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "School ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by reported user",
+                        "name": "reported_user_id",
+                        "in": "query"
                     },
                     {
-                        "description": "Updated school information",
-                        "name": "school",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.SchoolUploadData"
-                        }
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "School updated successfully",
-                        "schema": {
-                            "$ref": "#/definitions/models.School"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request or invalid school ID",
+                        "description": "List of message reports with pagination metadata",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -792,8 +836,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "School not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -811,43 +855,55 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/admin/message-reports/{report_id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a school by its ID",
+                "description": "Marks a message report as dismissed or actioned. With ` + "`" + `suspend_user` + "`" + ` the reported user is suspended (true) or reinstated after an automatic suspension (false).",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin",
-                    "schools"
+                    "messages"
                 ],
-                "summary": "Delete a school",
+                "summary": "Review a message report",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "School ID",
-                        "name": "id",
+                        "description": "Report ID",
+                        "name": "report_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Review outcome",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReviewMessageReportRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "School deleted successfully",
+                        "description": "Report reviewed",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad request or invalid school ID",
+                        "description": "Bad request or invalid status",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -864,8 +920,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "School not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -873,8 +929,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Conflict - school linked to institutions",
+                    "404": {
+                        "description": "Report not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -894,41 +950,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/users": {
+        "/api/v1/admin/reviews/pending": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all users with pagination",
+                "description": "Retrieves all reviews that are currently in 'pending' status, awaiting admin moderation.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin",
-                    "users"
-                ],
-                "summary": "Get all users",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number for pagination",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query"
-                    }
+                    "reviews"
                 ],
+                "summary": "Get pending reviews",
                 "responses": {
                     "200": {
-                        "description": "List of users with pagination metadata",
+                        "description": "List of pending reviews",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -943,6 +983,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - only administrators can view pending reviews",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -955,43 +1004,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/users/{id}": {
-            "delete": {
+        "/api/v1/admin/reviews/{review_id}/moderate": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Soft deletes a user account (admin only)",
+                "description": "Allows an admin to approve or reject a pending review.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin",
-                    "users"
+                    "reviews"
                 ],
-                "summary": "Delete a user",
+                "summary": "Moderate a review",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Review ID",
+                        "name": "review_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Moderation details (status and optional notes)",
+                        "name": "moderation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ModerateReviewRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User deleted successfully",
+                        "description": "Review moderated successfully",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad request, invalid user ID, or admin trying to delete themselves",
+                        "description": "Bad request or invalid review ID/request body",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1008,8 +1067,17 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - only administrators can moderate reviews",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
-                        "description": "User not found",
+                        "description": "Review not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1029,53 +1097,127 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/users/{id}/role": {
-            "put": {
+        "/api/v1/admin/school-claims": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Changes a user's role (admin only)",
-                "consumes": [
+                "description": "Retrieves school claims with their school, institution and documents, oldest first so claims are reviewed in order",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "admin",
+                    "schools"
+                ],
+                "summary": "List school claims",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status (pending, approved, rejected, withdrawn)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by school",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default: 20, max: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "School claims with pagination metadata",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/school-claims/{claim_id}/documents/{document_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Downloads a verification document uploaded with a school claim",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
                     "admin",
-                    "users"
+                    "schools"
                 ],
-                "summary": "Update user role",
+                "summary": "Download a school claim document",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Claim ID",
+                        "name": "claim_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "User role update information",
-                        "name": "role",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.UserRoleUpdateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User role updated successfully",
+                        "description": "Document content",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Bad request, invalid user ID, or admin trying to change own role",
+                        "description": "Invalid claim or document ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1092,8 +1234,17 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
-                        "description": "User not found",
+                        "description": "Document not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1109,18 +1260,27 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "File storage unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/api/v1/admin/users/{id}/status": {
+        "/api/v1/admin/school-claims/{claim_id}/review": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Activates or deactivates a user account",
+                "description": "Approves or rejects a pending school claim. Approving maps the institution to the school, marks the institution verified and the school verified, and rejects the other pending claims of the school. The institutions are notified of the decision.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1129,37 +1289,36 @@ const docTemplate = `{
                 ],
                 "tags": [
                     "admin",
-                    "users"
+                    "schools"
                 ],
-                "summary": "Update user active status",
+                "summary": "Review a school claim",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Claim ID",
+                        "name": "claim_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "User status update information",
-                        "name": "status",
+                        "description": "Decision and optional notes for the institution",
+                        "name": "review",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.UserStatusUpdateRequest"
+                            "$ref": "#/definitions/handlers.ReviewSchoolClaimRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User status updated successfully",
+                        "description": "Reviewed claim",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.SchoolClaim"
                         }
                     },
                     "400": {
-                        "description": "Bad request, invalid user ID, or admin trying to change own status",
+                        "description": "Invalid claim ID or request body",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1176,8 +1335,26 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
-                        "description": "User not found",
+                        "description": "Claim not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Claim already reviewed or school managed by another verified institution",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1197,44 +1374,83 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/blog": {
+        "/api/v1/admin/school-duplicates": {
             "get": {
-                "description": "Retrieves all published blog posts with optional filtering",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves pairs of schools that may be the same school, most similar first. Pairs are found in the background by comparing the normalized name, address and website of new and changed schools within a country.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "blog"
+                    "admin",
+                    "schools"
                 ],
-                "summary": "Get all published blog posts",
+                "summary": "List possible duplicate schools",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by category",
-                        "name": "category",
+                        "default": "pending",
+                        "description": "Filter by status (pending, merged, dismissed, all)",
+                        "name": "status",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Filter by tag",
-                        "name": "tag",
+                        "description": "Filter by country code",
+                        "name": "country_code",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Language for localized content",
-                        "name": "language",
+                        "type": "number",
+                        "description": "Only pairs scoring at least this, from 0 to 1",
+                        "name": "min_score",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of items per page",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of blog posts",
+                        "description": "List of school pairs with pagination metadata",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1247,90 +1463,81 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/blog/categories": {
-            "get": {
-                "description": "Retrieves all distinct categories from published blog posts",
+        "/api/v1/admin/school-duplicates/{pair_id}/dismiss": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks a pair of schools as different schools. The pair is not suggested again.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "blog"
+                    "admin",
+                    "schools"
+                ],
+                "summary": "Dismiss a possible duplicate",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duplicate pair ID",
+                        "name": "pair_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all blog categories",
                 "responses": {
                     "200": {
-                        "description": "List of blog categories",
+                        "description": "Pair dismissed",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchoolDuplicate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pair ID",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/blog/featured": {
-            "get": {
-                "description": "Retrieves all featured and published blog posts",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "blog"
-                ],
-                "summary": "Get featured blog posts",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Language for localized content",
-                        "name": "language",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "List of featured blog posts",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Pair not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/blog/tags": {
-            "get": {
-                "description": "Retrieves all unique tags from published blog posts",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "blog"
-                ],
-                "summary": "Get all blog tags",
-                "responses": {
-                    "200": {
-                        "description": "List of blog tags",
+                    },
+                    "409": {
+                        "description": "Pair already reviewed",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -1345,41 +1552,69 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/blog/{slug}": {
-            "get": {
-                "description": "Retrieves a specific blog post by its slug",
+        "/api/v1/admin/school-duplicates/{pair_id}/merge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Merges the two schools of a pair. Reviews, saved-school links and the institution mapping (with its events) move to the kept school, empty fields of the kept school are filled from the other one, and the other school's ID redirects to the kept school. Fails if both schools are mapped to an institution.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "blog"
+                    "admin",
+                    "schools"
                 ],
-                "summary": "Get a specific blog post",
+                "summary": "Merge a duplicate pair",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Blog post slug",
-                        "name": "slug",
+                        "type": "integer",
+                        "description": "Duplicate pair ID",
+                        "name": "pair_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Language for localized content",
-                        "name": "language",
-                        "in": "query"
+                        "description": "School to keep, the older school by default",
+                        "name": "merge",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MergeSchoolDuplicateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Blog post details",
+                        "description": "Kept school",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.School"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid pair ID or school to keep",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1388,7 +1623,25 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Blog post not found",
+                        "description": "Pair or school not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Pair already reviewed or both schools mapped to an institution",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1399,34 +1652,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/educator/jobs/applied": {
+        "/api/v1/admin/schools": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves all job applications submitted by the educator",
+                "description": "Retrieves a list of schools filtered by country code with pagination",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "educator",
-                    "jobs"
+                    "admin",
+                    "schools"
+                ],
+                "summary": "Get schools by country",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Country code (e.g., US, UK)",
+                        "name": "country_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get applied jobs",
                 "responses": {
                     "200": {
-                        "description": "List of job applications with job details",
+                        "description": "List of schools with pagination metadata",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.JobApplication"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad request - missing country_code",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1434,8 +1708,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Educator profile not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1455,53 +1729,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/educator/jobs/{job_id}/apply": {
+        "/api/v1/admin/schools/batch-upload": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Submit an application for a job posting",
+                "description": "Uploads schools from a JSON array, a CSV file (comma or semicolon separated) or the first worksheet of an XLSX file. The file is imported in the background: the response is the queued import job, whose progress can be polled at /api/v1/admin/import-jobs/{job_id} and is pushed over the WebSocket as ` + "`" + `import_job_progress` + "`" + ` messages. CSV and XLSX columns are matched by header (name, address, city, state, country_code, zip_code, contact_email, contact_phone, website, latitude, longitude and common aliases such as \"postal code\" or \"email\"); column_mapping maps fields to other headers, e.g. {\"name\": \"School Name\"}. Each row is validated on its own: valid rows are created and invalid rows are listed in the job's error report.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "educator",
-                    "jobs"
+                    "admin",
+                    "schools"
                 ],
-                "summary": "Apply for a job",
+                "summary": "Batch upload schools",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Job ID",
-                        "name": "job_id",
-                        "in": "path",
+                        "type": "file",
+                        "description": "JSON, CSV or XLSX file containing school data",
+                        "name": "schools_file",
+                        "in": "formData",
                         "required": true
                     },
                     {
-                        "description": "Job application details",
-                        "name": "application",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.JobApplicationRequest"
-                        }
+                        "type": "string",
+                        "description": "JSON object mapping fields to column headers",
+                        "name": "column_mapping",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Application submitted successfully",
+                    "202": {
+                        "description": "Import job queued",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.ImportJob"
                         }
                     },
                     "400": {
-                        "description": "Bad request or invalid job ID",
+                        "description": "Bad request or unsupported file type",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1518,8 +1788,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Educator profile or job not found",
+                    "413": {
+                        "description": "File too large",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1527,8 +1797,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Already applied for this job",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1536,8 +1806,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "503": {
+                        "description": "File storage unavailable",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1548,14 +1818,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/educator/profile": {
-            "post": {
+        "/api/v1/admin/schools/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new educator profile or updates an existing one",
+                "description": "Updates an existing school's information",
                 "consumes": [
                     "application/json"
                 ],
@@ -1563,30 +1833,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "educator",
-                    "profile"
+                    "admin",
+                    "schools"
                 ],
-                "summary": "Create or update educator profile",
+                "summary": "Update school information",
                 "parameters": [
                     {
-                        "description": "Educator profile information",
-                        "name": "profile",
+                        "type": "integer",
+                        "description": "School ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated school information",
+                        "name": "school",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.EducatorProfileRequest"
+                            "$ref": "#/definitions/handlers.SchoolUploadData"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile created or updated successfully",
+                        "description": "School updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.EducatorProfile"
+                            "$ref": "#/definitions/models.School"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Bad request or invalid school ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1603,6 +1880,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1613,36 +1899,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/educator/schools/save/{school_id}": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds a school to the educator's saved schools list",
+                "description": "Deletes a school by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "educator",
+                    "admin",
                     "schools"
                 ],
-                "summary": "Save a school",
+                "summary": "Delete a school",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "School ID",
-                        "name": "school_id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "School saved successfully",
+                        "description": "School deleted successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1669,7 +1953,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Educator profile or school not found",
+                        "description": "School not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1678,7 +1962,7 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "School already saved",
+                        "description": "Conflict - school linked to institutions",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1696,43 +1980,54 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/admin/schools/{id}/attributes": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Removes a school from the educator's saved schools list",
+                "description": "Replaces the programs, age range, languages of instruction, tuition band, schedules and accreditations of a school; omitted lists are cleared. Without age_min_months and age_max_months the age range is derived from the programs.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "educator",
+                    "admin",
                     "schools"
                 ],
-                "summary": "Delete a saved school",
+                "summary": "Update school programs and attributes",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "School ID",
-                        "name": "school_id",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "School attributes",
+                        "name": "attributes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SchoolAttributesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "School removed successfully",
+                        "description": "Updated school",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.School"
                         }
                     },
                     "400": {
-                        "description": "Bad request or invalid school ID",
+                        "description": "Invalid school ID or attributes",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1750,7 +2045,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Educator profile or school not found",
+                        "description": "School not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1770,29 +2065,67 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/educator/schools/saved": {
-            "get": {
+        "/api/v1/admin/schools/{id}/media": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves the list of schools saved by the educator",
+                "description": "Uploads a logo, cover or gallery image of a school, processed like images uploaded by institutions",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "educator",
+                    "admin",
                     "schools"
                 ],
-                "summary": "Get saved schools",
+                "summary": "Upload a school image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "School ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "logo, cover or gallery",
+                        "name": "kind",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Caption",
+                        "name": "caption",
+                        "in": "formData"
+                    }
+                ],
                 "responses": {
-                    "200": {
-                        "description": "List of saved schools",
+                    "201": {
+                        "description": "Stored image with its URLs",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.School"
+                            "$ref": "#/definitions/models.Media"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid school ID, missing file or invalid kind",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -1806,7 +2139,43 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Educator profile not found",
+                        "description": "School not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Gallery is full",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Not a supported image",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Image dimensions too large",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1822,66 +2191,68 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "File storage unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/api/v1/educator/schools/search": {
-            "get": {
+        "/api/v1/admin/schools/{id}/media/{media_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Search for schools with various filters and pagination",
+                "description": "Deletes an image of a school with all its sizes",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "educator",
+                    "admin",
                     "schools"
                 ],
-                "summary": "Search for schools",
+                "summary": "Delete a school image",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filter by school name",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by city",
-                        "name": "city",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by country code",
-                        "name": "country_code",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "Page number for pagination",
-                        "name": "page",
-                        "in": "query"
+                        "description": "School ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "default": 10,
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Media ID",
+                        "name": "media_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of schools with pagination metadata",
+                        "description": "Image deleted",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid school or media ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
@@ -1893,6 +2264,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "School or image not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1905,55 +2285,93 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/events": {
-            "get": {
-                "description": "Retrieves a list of all published events with optional filtering",
+        "/api/v1/admin/schools/{id}/merge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Merges the school merge_school_id into the school in the path, whether or not the pair was detected as a duplicate. Reviews, saved-school links and the institution mapping (with its events) move to the kept school, empty fields are filled from the merged school, and the merged school's ID redirects to the kept school. Fails if both schools are mapped to an institution.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "admin",
+                    "schools"
                 ],
-                "summary": "Get all published events",
+                "summary": "Merge schools",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Filter by event type",
-                        "name": "event_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by target audience",
-                        "name": "audience",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by start date (RFC3339 format)",
-                        "name": "start_date",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "ID of the school to keep",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Filter by end date (RFC3339 format)",
-                        "name": "end_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "en",
-                        "description": "Language for localized content",
-                        "name": "language",
-                        "in": "query"
+                        "description": "School to merge into it",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MergeSchoolsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of events",
+                        "description": "Kept school",
+                        "schema": {
+                            "$ref": "#/definitions/models.School"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid school ID",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Both schools mapped to an institution",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -1968,33 +2386,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/events/featured": {
+        "/api/v1/admin/users": {
             "get": {
-                "description": "Retrieves all featured and published events",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of all users with pagination",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "admin",
+                    "users"
                 ],
-                "summary": "Get featured events",
+                "summary": "Get all users",
                 "parameters": [
                     {
-                        "type": "string",
-                        "default": "en",
-                        "description": "Language for localized content",
-                        "name": "language",
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of featured events",
+                        "description": "List of users with pagination metadata",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2007,51 +2447,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/events/{event_id}": {
+        "/api/v1/admin/users/email-suppressed": {
             "get": {
-                "description": "Retrieves detailed information about a specific event",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves users whose address was suppressed after hard bounces, repeated soft bounces or spam complaints, with their recent bounce and complaint events",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "admin",
+                    "users"
                 ],
-                "summary": "Get event details",
+                "summary": "Get users with suppressed email",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Event ID",
-                        "name": "event_id",
-                        "in": "path",
-                        "required": true
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "default": "en",
-                        "description": "Language for localized content",
-                        "name": "language",
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Event details",
+                        "description": "List of users with suppressed email and pagination metadata",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad request or invalid event ID",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Event not found or not published",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2071,28 +2508,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/institution/events": {
-            "get": {
+        "/api/v1/admin/users/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves all events created by the current institution",
+                "description": "Soft deletes a user account (admin only)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "institution",
-                    "events"
+                    "admin",
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get institution events",
                 "responses": {
                     "200": {
-                        "description": "List of institution events",
+                        "description": "User deleted successfully",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid user ID, or admin trying to delete themselves",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
@@ -2104,6 +2561,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2114,46 +2580,43 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/admin/users/{id}/email-suppression": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new event for an institution or training center",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Clears the email suppression and soft bounce count of a user, e.g. after they fixed their mailbox or changed their address",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "institution",
-                    "events"
+                    "admin",
+                    "users"
                 ],
-                "summary": "Create a new event",
+                "summary": "Clear email suppression",
                 "parameters": [
                     {
-                        "description": "Event information",
-                        "name": "event",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CreateEventRequest"
-                        }
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Event created successfully",
+                    "200": {
+                        "description": "Email suppression cleared",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad request or validation error",
+                        "description": "Invalid user ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2170,8 +2633,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - only institutions and training centers can create events",
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2191,14 +2654,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/institution/events/{event_id}": {
+        "/api/v1/admin/users/{id}/role": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates an existing event's information",
+                "description": "Changes a user's role (admin only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2206,38 +2669,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "institution",
-                    "events"
+                    "admin",
+                    "users"
                 ],
-                "summary": "Update an event",
+                "summary": "Update user role",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Event ID",
-                        "name": "event_id",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated event information",
-                        "name": "event",
+                        "description": "User role update information",
+                        "name": "role",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CreateEventRequest"
+                            "$ref": "#/definitions/handlers.UserRoleUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Event updated successfully",
+                        "description": "User role updated successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad request or invalid event ID",
+                        "description": "Bad request, invalid user ID, or admin trying to change own role",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2254,17 +2717,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - can only update own events",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "404": {
-                        "description": "Event not found",
+                        "description": "User not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2282,43 +2736,55 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/admin/users/{id}/status": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes an existing event",
+                "description": "Activates or deactivates a user account",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "institution",
-                    "events"
+                    "admin",
+                    "users"
                 ],
-                "summary": "Delete an event",
+                "summary": "Update user active status",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Event ID",
-                        "name": "event_id",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "User status update information",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserStatusUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Event deleted successfully",
+                        "description": "User status updated successfully",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad request or invalid event ID",
+                        "description": "Bad request, invalid user ID, or admin trying to change own status",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2335,17 +2801,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - can only delete own events",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "404": {
-                        "description": "Event not found",
+                        "description": "User not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2365,34 +2822,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/institution/jobs": {
-            "get": {
+        "/api/v1/attachments": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves all job postings created by the institution",
+                "description": "Uploads a file as multipart form field ` + "`" + `file` + "`" + `. The file type is detected from its content and must be in ATTACHMENT_ALLOWED_TYPES; files larger than ATTACHMENT_MAX_SIZE_MB or flagged by the virus scanner are rejected. Send the returned ID in ` + "`" + `attachment_ids` + "`" + ` of a message. Attachments not sent within 24 hours are deleted.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "institution",
-                    "jobs"
+                    "messages"
+                ],
+                "summary": "Upload a message attachment",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
                 ],
-                "summary": "Get institution's jobs",
                 "responses": {
-                    "200": {
-                        "description": "List of jobs posted by the institution",
+                    "201": {
+                        "description": "Uploaded attachment with a signed download URL",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Job"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Missing file",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2400,8 +2866,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Institution profile not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2409,55 +2875,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "413": {
+                        "description": "File too large",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Creates a new job posting for an institution",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "institution",
-                    "jobs"
-                ],
-                "summary": "Post a job opening",
-                "parameters": [
-                    {
-                        "description": "Job information",
-                        "name": "job",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.JobRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Job posted successfully",
-                        "schema": {
-                            "$ref": "#/definitions/models.Job"
-                        }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "415": {
+                        "description": "File type not allowed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2465,8 +2893,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "422": {
+                        "description": "File rejected by the virus scanner",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2474,8 +2902,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Institution profile not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2483,8 +2911,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "503": {
+                        "description": "File storage unavailable",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2495,52 +2923,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/institution/jobs/{job_id}": {
-            "put": {
+        "/api/v1/attachments/{attachment_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates an existing job posting",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Deletes an uploaded attachment of the current user that has not been sent with a message",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "institution",
-                    "jobs"
+                    "messages"
                 ],
-                "summary": "Update a job posting",
+                "summary": "Delete an unsent attachment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Job ID",
-                        "name": "job_id",
+                        "description": "Attachment ID",
+                        "name": "attachment_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated job information",
-                        "name": "job",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.JobRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Job updated successfully",
-                        "schema": {
-                            "$ref": "#/definitions/models.Job"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request or invalid job ID",
+                        "description": "Attachment deleted",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2548,8 +2957,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Invalid attachment ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2557,8 +2966,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - can only update own jobs",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2567,7 +2976,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Job not found",
+                        "description": "Attachment not found or already sent",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2585,43 +2994,57 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Deletes an existing job posting",
+            }
+        },
+        "/api/v1/attachments/{attachment_id}/download": {
+            "get": {
+                "description": "Downloads an attachment using a signed URL from the upload or URL endpoint. No bearer token is needed, so the URL can be opened directly by a browser; access is re-checked against the conversation on every download.",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "institution",
-                    "jobs"
+                    "messages"
                 ],
-                "summary": "Delete a job posting",
+                "summary": "Download an attachment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Job ID",
-                        "name": "job_id",
+                        "description": "Attachment ID",
+                        "name": "attachment_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User the URL was issued to",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expiry as a Unix timestamp",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "URL signature",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Job deleted successfully",
+                        "description": "Attachment content",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Bad request or invalid job ID",
+                        "description": "Invalid attachment ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2629,8 +3052,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Invalid or expired signature",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2638,8 +3061,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - can only delete own jobs",
+                    "404": {
+                        "description": "Attachment not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2647,8 +3070,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Job not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2656,8 +3079,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "503": {
+                        "description": "File storage unavailable",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2668,43 +3091,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/institution/jobs/{job_id}/applicants": {
+        "/api/v1/attachments/{attachment_id}/url": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves all applicants for a specific job posting",
+                "description": "Returns a signed download URL for an attachment of a message the current user sent or received. The URL expires after ATTACHMENT_URL_TTL_MINUTES and only works for the current user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "institution",
-                    "jobs"
+                    "messages"
                 ],
-                "summary": "Get job applicants",
+                "summary": "Get an attachment download URL",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Job ID",
-                        "name": "job_id",
+                        "description": "Attachment ID",
+                        "name": "attachment_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of job applications with applicant details",
+                        "description": "Signed download URL and its expiry",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.JobApplication"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad request or invalid job ID",
+                        "description": "Invalid attachment ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2721,26 +3141,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - can only view applicants for own jobs",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "404": {
-                        "description": "Job not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "Attachment not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2751,60 +3153,111 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/institution/profile": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Creates a new institution profile or updates an existing one",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/blog": {
+            "get": {
+                "description": "Retrieves all published blog posts with optional filtering",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "institution",
-                    "profile"
+                    "blog"
                 ],
-                "summary": "Create or update institution profile",
+                "summary": "Get all published blog posts",
                 "parameters": [
                     {
-                        "description": "Institution profile information",
-                        "name": "profile",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.InstitutionProfileRequest"
-                        }
+                        "type": "string",
+                        "description": "Filter by category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language for localized content",
+                        "name": "language",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile created or updated successfully",
+                        "description": "List of blog posts",
                         "schema": {
-                            "$ref": "#/definitions/models.InstitutionProfile"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/api/v1/blog/categories": {
+            "get": {
+                "description": "Retrieves all distinct categories from published blog posts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "blog"
+                ],
+                "summary": "Get all blog categories",
+                "responses": {
+                    "200": {
+                        "description": "List of blog categories",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/api/v1/blog/featured": {
+            "get": {
+                "description": "Retrieves all featured and published blog posts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "blog"
+                ],
+                "summary": "Get featured blog posts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language for localized content",
+                        "name": "language",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of featured blog posts",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
                     "500": {
                         "description": "Internal server error",
@@ -2818,41 +3271,67 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/institution/schools": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Creates a new school entry for an institution when not found in admin list",
-                "consumes": [
+        "/api/v1/blog/tags": {
+            "get": {
+                "description": "Retrieves all unique tags from published blog posts",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "blog"
+                ],
+                "summary": "Get all blog tags",
+                "responses": {
+                    "200": {
+                        "description": "List of blog tags",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/blog/{slug}": {
+            "get": {
+                "description": "Retrieves a specific blog post by its slug",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "institution",
-                    "schools"
+                    "blog"
                 ],
-                "summary": "Create a new school",
+                "summary": "Get a specific blog post",
                 "parameters": [
                     {
-                        "description": "School information",
-                        "name": "school",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.SchoolUploadData"
-                        }
+                        "type": "string",
+                        "description": "Blog post slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language for localized content",
+                        "name": "language",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "School created successfully",
+                    "200": {
+                        "description": "Blog post details",
                         "schema": {
-                            "$ref": "#/definitions/models.School"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -2864,26 +3343,66 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Blog post not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the current user's conversations, most recent activity first, with the other participants, the last message and the number of unread messages",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "List conversations",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "List archived conversations instead of the inbox",
+                        "name": "archived",
+                        "in": "query"
                     },
-                    "404": {
-                        "description": "Institution profile not found",
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of conversations with pagination metadata",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
-                    "409": {
-                        "description": "Institution already has a school",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2903,41 +3422,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/institution/schools/select/{school_id}": {
+        "/api/v1/conversations/{conversation_id}/archive": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Associates an institution with an existing school from the admin-uploaded list",
+                "description": "Moves a conversation out of or back into the current user's inbox. An archived conversation returns to the inbox when a new message arrives.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "institution",
-                    "schools"
+                    "messages"
                 ],
-                "summary": "Select a school",
+                "summary": "Archive or unarchive a conversation",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "School ID",
-                        "name": "school_id",
+                        "description": "Conversation ID",
+                        "name": "conversation_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Archived state",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ArchiveConversationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "School selected successfully",
+                        "description": "Conversation updated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad request or invalid school ID",
+                        "description": "Invalid conversation ID or request body",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2955,16 +3485,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Institution profile or school not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "409": {
-                        "description": "Institution already has a school or school already taken",
+                        "description": "Conversation not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2984,23 +3505,75 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/jobs": {
+        "/api/v1/conversations/{conversation_id}/messages": {
             "get": {
-                "description": "Retrieves all active job postings in the system",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the messages of a conversation, newest first. Pass the returned next_cursor as cursor to load older messages.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "jobs"
+                    "messages"
+                ],
+                "summary": "Get conversation messages",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Conversation ID",
+                        "name": "conversation_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Return messages older than this message ID",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 30,
+                        "description": "Number of messages to return",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all jobs",
                 "responses": {
                     "200": {
-                        "description": "List of all active jobs",
+                        "description": "Messages with the cursor for the next page",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Job"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid conversation ID or cursor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Conversation not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -3016,9 +3589,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/login": {
-            "post": {
-                "description": "Authenticate a user and return a JWT token",
+        "/api/v1/conversations/{conversation_id}/mute": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Muted conversations still receive messages but send no in-app, WebSocket or email notifications to the current user",
                 "consumes": [
                     "application/json"
                 ],
@@ -3026,30 +3604,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "messages"
                 ],
-                "summary": "User login",
+                "summary": "Mute or unmute a conversation",
                 "parameters": [
                     {
-                        "description": "User login credentials",
+                        "type": "integer",
+                        "description": "Conversation ID",
+                        "name": "conversation_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Muted state",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.LoginRequest"
+                            "$ref": "#/definitions/handlers.MuteConversationRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful with token",
+                        "description": "Conversation updated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid conversation ID or request body",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3058,7 +3643,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Invalid credentials",
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3066,8 +3651,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "User account is inactive",
+                    "404": {
+                        "description": "Conversation not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3087,38 +3672,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/parent/messages": {
-            "get": {
+        "/api/v1/conversations/{conversation_id}/read": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves all messages sent to or by the current user",
+                "description": "Marks all unread messages of a conversation addressed to the current user as read and sends read receipts to the senders",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "parent",
                     "messages"
                 ],
-                "summary": "Get user messages",
+                "summary": "Mark conversation as read",
                 "parameters": [
                     {
-                        "type": "boolean",
-                        "default": false,
-                        "description": "Filter to show only unread messages",
-                        "name": "unread_only",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Conversation ID",
+                        "name": "conversation_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of messages",
+                        "description": "Number of messages marked as read",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Message"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid conversation ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -3131,6 +3722,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Conversation not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -3143,56 +3743,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/parent/messages/send/{recipient_id}": {
-            "post": {
+        "/api/v1/educator/jobs/applied": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Sends a message to an institution or educator",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves all job applications submitted by the educator",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "parent",
-                    "messages"
-                ],
-                "summary": "Send a message",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Recipient User ID",
-                        "name": "recipient_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Message content",
-                        "name": "message",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.MessageRequest"
-                        }
-                    }
+                    "educator",
+                    "jobs"
                 ],
+                "summary": "Get applied jobs",
                 "responses": {
-                    "201": {
-                        "description": "Message sent successfully",
-                        "schema": {
-                            "$ref": "#/definitions/models.Message"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request or invalid recipient ID",
+                    "200": {
+                        "description": "List of job applications with job details",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.JobApplication"
                             }
                         }
                     },
@@ -3206,7 +3779,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Recipient not found",
+                        "description": "Educator profile not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3226,43 +3799,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/parent/messages/{message_id}/read": {
+        "/api/v1/educator/jobs/{job_id}/apply": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Marks a specific message as read by the recipient",
+                "description": "Submit an application for a job posting",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "parent",
-                    "messages"
+                    "educator",
+                    "jobs"
                 ],
-                "summary": "Mark message as read",
+                "summary": "Apply for a job",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Message ID",
-                        "name": "message_id",
+                        "description": "Job ID",
+                        "name": "job_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Job application details",
+                        "name": "application",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobApplicationRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Message marked as read successfully",
+                    "201": {
+                        "description": "Application submitted successfully",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad request or invalid message ID",
+                        "description": "Bad request or invalid job ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3279,8 +3862,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - can only mark messages addressed to you",
+                    "404": {
+                        "description": "Educator profile or job not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3288,8 +3871,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Message not found",
+                    "409": {
+                        "description": "Already applied for this job",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3309,14 +3892,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/parent/profile": {
+        "/api/v1/educator/profile": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new parent profile or updates an existing one",
+                "description": "Creates a new educator profile or updates an existing one",
                 "consumes": [
                     "application/json"
                 ],
@@ -3324,18 +3907,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "parent",
+                    "educator",
                     "profile"
                 ],
-                "summary": "Create or update parent profile",
+                "summary": "Create or update educator profile",
                 "parameters": [
                     {
-                        "description": "Parent profile information",
+                        "description": "Educator profile information",
                         "name": "profile",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.ParentProfileRequest"
+                            "$ref": "#/definitions/handlers.EducatorProfileRequest"
                         }
                     }
                 ],
@@ -3343,7 +3926,7 @@ const docTemplate = `{
                     "200": {
                         "description": "Profile created or updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.ParentProfile"
+                            "$ref": "#/definitions/models.EducatorProfile"
                         }
                     },
                     "400": {
@@ -3376,48 +3959,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/parent/schools/save/{school_id}": {
-            "post": {
+        "/api/v1/educator/recommendations": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds a school to the parent's saved schools list",
+                "description": "Lists up to 20 active jobs recommended to the educator, best first, each with a score and the reasons for it. Jobs are scored on the distance of the posting school from saved schools, saved searches and schools applied to, their similarity to jobs applied for (title, employment type and location) or being at a saved school, and their popularity (applications and verified institution). Recommendations are recomputed in the background when the educator applies, saves or removes schools or changes saved searches, and daily.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "parent",
-                    "schools"
-                ],
-                "summary": "Save a school",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "School ID",
-                        "name": "school_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "educator",
+                    "jobs"
                 ],
+                "summary": "Get job recommendations",
                 "responses": {
                     "200": {
-                        "description": "School saved successfully",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request or invalid school ID",
+                        "description": "Recommended jobs, with meta.computed_at",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -3430,7 +3993,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Parent profile or school not found",
+                        "description": "Educator profile not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3438,8 +4001,82 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "School already saved",
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/educator/schools/save/{school_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a school to the educator's saved schools list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "educator",
+                    "schools"
+                ],
+                "summary": "Save a school",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "School ID",
+                        "name": "school_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "School saved successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid school ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Educator profile or school not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "School already saved",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3464,12 +4101,12 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Removes a school from the parent's saved schools list",
+                "description": "Removes a school from the educator's saved schools list",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "parent",
+                    "educator",
                     "schools"
                 ],
                 "summary": "Delete a saved school",
@@ -3511,7 +4148,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Parent profile or school not found",
+                        "description": "Educator profile or school not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3531,19 +4168,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/parent/schools/saved": {
+        "/api/v1/educator/schools/saved": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves the list of schools saved by the parent",
+                "description": "Retrieves the list of schools saved by the educator",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "parent",
+                    "educator",
                     "schools"
                 ],
                 "summary": "Get saved schools",
@@ -3567,7 +4204,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Parent profile not found",
+                        "description": "Educator profile not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3587,7 +4224,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/parent/schools/search": {
+        "/api/v1/educator/schools/search": {
             "get": {
                 "security": [
                     {
@@ -3599,7 +4236,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "parent",
+                    "educator",
                     "schools"
                 ],
                 "summary": "Search for schools",
@@ -3666,56 +4303,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/register": {
-            "post": {
-                "description": "Register a new user with the specified role",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/events": {
+            "get": {
+                "description": "Retrieves a list of all published events with optional filtering",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "events"
                 ],
-                "summary": "Register a new user",
+                "summary": "Get all published events",
                 "parameters": [
                     {
-                        "description": "User registration information",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.RegisterRequest"
-                        }
+                        "type": "string",
+                        "description": "Filter by event type",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by target audience",
+                        "name": "audience",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by start date (RFC3339 format)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by end date (RFC3339 format)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language for localized content",
+                        "name": "language",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User registered successfully",
+                    "200": {
+                        "description": "List of events",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "409": {
-                        "description": "Email already exists",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -3728,63 +4366,81 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/reviews": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Creates a new review for a school (parents and educators only). The review will be pending approval by an admin.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/events/featured": {
+            "get": {
+                "description": "Retrieves all featured and published events",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reviews"
+                    "events"
                 ],
-                "summary": "Create a new review",
+                "summary": "Get featured events",
                 "parameters": [
                     {
-                        "description": "Review information",
-                        "name": "review",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CreateReviewRequest"
-                        }
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language for localized content",
+                        "name": "language",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Review created successfully and pending approval",
+                    "200": {
+                        "description": "List of featured events",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad request or validation error",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{event_id}": {
+            "get": {
+                "description": "Retrieves detailed information about a specific event",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get event details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "event_id",
+                        "in": "path",
+                        "required": true
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language for localized content",
+                        "name": "language",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event details",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - only parents and educators can create reviews",
+                    "400": {
+                        "description": "Bad request or invalid event ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3793,16 +4449,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "School not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "409": {
-                        "description": "User has already reviewed this school",
+                        "description": "Event not found or not published",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3822,28 +4469,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/reviews/me": {
+        "/api/v1/institution/claims": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves all reviews submitted by the authenticated user.",
+                "description": "Retrieves the institution's school claims with their review status, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reviews",
-                    "users"
+                    "institution",
+                    "schools"
                 ],
-                "summary": "Get current user's reviews",
+                "summary": "List my school claims",
                 "responses": {
                     "200": {
-                        "description": "List of reviews by the current user",
+                        "description": "School claims",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SchoolClaim"
+                            }
                         }
                     },
                     "401": {
@@ -3855,6 +4504,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Institution profile not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -3867,52 +4525,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/reviews/{review_id}": {
-            "put": {
+        "/api/v1/institution/claims/{claim_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Allows an authenticated user to update their own review. The updated review will be reset to pending status.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Withdraws a claim of the logged-in institution that has not been reviewed yet",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reviews"
+                    "institution",
+                    "schools"
                 ],
-                "summary": "Update a review",
+                "summary": "Withdraw a school claim",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Review ID",
-                        "name": "review_id",
+                        "description": "Claim ID",
+                        "name": "claim_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated review information",
-                        "name": "review",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CreateReviewRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Review updated successfully and pending approval",
+                        "description": "Withdrawn claim",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.SchoolClaim"
                         }
                     },
                     "400": {
-                        "description": "Bad request or invalid review ID/request body",
+                        "description": "Invalid claim ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3929,8 +4575,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - user can only update their own reviews",
+                    "404": {
+                        "description": "Institution profile or claim not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3938,8 +4584,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Review not found",
+                    "409": {
+                        "description": "Claim already reviewed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3957,47 +4603,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/institution/events": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Allows an authenticated user to delete their own review.",
+                "description": "Retrieves all events created by the current institution",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reviews"
-                ],
-                "summary": "Delete a review",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Review ID",
-                        "name": "review_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "institution",
+                    "events"
                 ],
+                "summary": "Get institution events",
                 "responses": {
                     "200": {
-                        "description": "Review deleted successfully",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request or invalid review ID",
+                        "description": "List of institution events",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -4009,24 +4638,6 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - user can only delete their own reviews",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Review not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -4037,38 +4648,46 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/schools/{school_id}/reviews": {
-            "get": {
-                "description": "Retrieves all approved reviews for a specific school, along with the average rating and total review count.",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new event for an institution or training center",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reviews",
-                    "schools"
+                    "institution",
+                    "events"
                 ],
-                "summary": "Get school reviews",
+                "summary": "Create a new event",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "School ID",
-                        "name": "school_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Event information",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateEventRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "List of reviews with average rating",
+                    "201": {
+                        "description": "Event created successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad request or invalid school ID",
+                        "description": "Bad request or validation error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4076,8 +4695,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "School not found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only institutions and training centers can create events",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4097,14 +4725,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/subscription/cancel": {
-            "post": {
+        "/api/v1/institution/events/{event_id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cancels the current user's active subscription",
+                "description": "Updates an existing event's information",
                 "consumes": [
                     "application/json"
                 ],
@@ -4112,22 +4740,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "institution",
+                    "events"
                 ],
-                "summary": "Cancel user subscription",
+                "summary": "Update an event",
                 "parameters": [
                     {
-                        "description": "Cancellation reason",
-                        "name": "request",
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "event_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated event information",
+                        "name": "event",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CancelRequest"
+                            "$ref": "#/definitions/handlers.CreateEventRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Subscription canceled successfully",
+                        "description": "Event updated successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid event ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4136,7 +4780,16 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "User not authenticated",
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - can only update own events",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4145,7 +4798,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "No active subscription found",
+                        "description": "Event not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4163,48 +4816,43 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/subscription/checkout": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a Stripe checkout session for a user to subscribe to a plan",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Deletes an existing event",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "institution",
+                    "events"
                 ],
-                "summary": "Create a checkout session for subscription",
+                "summary": "Delete an event",
                 "parameters": [
                     {
-                        "enum": [
-                            "monthly",
-                            "annual"
-                        ],
-                        "type": "string",
-                        "description": "Subscription plan (monthly or annual)",
-                        "name": "plan",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "event_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Checkout session created successfully",
+                        "description": "Event deleted successfully",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Bad request or invalid event ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4213,7 +4861,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "User not authenticated",
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4221,8 +4869,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "User already has an active subscription",
+                    "403": {
+                        "description": "Forbidden - can only delete own events",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4230,43 +4878,55 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Event not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
             }
         },
-        "/api/v1/subscription/status": {
+        "/api/v1/institution/jobs": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves the current user's subscription details",
+                "description": "Retrieves all job postings created by the institution",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "institution",
+                    "jobs"
                 ],
-                "summary": "Get user subscription",
+                "summary": "Get institution's jobs",
                 "responses": {
                     "200": {
-                        "description": "Subscription details",
+                        "description": "List of jobs posted by the institution",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Job"
+                            }
                         }
                     },
                     "401": {
-                        "description": "User not authenticated",
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4275,7 +4935,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "No subscription found",
+                        "description": "Institution profile not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4293,11 +4953,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/webhooks/notify-unread-message": {
+            },
             "post": {
-                "description": "Receives a payload from the message queue system to process and send email notifications for unread messages. This endpoint is intended for internal system use and should be secured.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new job posting for an institution",
                 "consumes": [
                     "application/json"
                 ],
@@ -4305,24 +4968,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "webhooks",
-                    "notifications"
+                    "institution",
+                    "jobs"
                 ],
-                "summary": "Webhook for Unread Message Notification",
+                "summary": "Post a job opening",
                 "parameters": [
                     {
-                        "description": "Details of the unread message to be processed for notification",
-                        "name": "payload",
+                        "description": "Job information",
+                        "name": "job",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.UnreadMessagePayload"
+                            "$ref": "#/definitions/handlers.JobRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Notification processed successfully (email sent or message already read)",
+                    "201": {
+                        "description": "Job posted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4330,8 +4999,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad request or invalid payload",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4339,8 +5008,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized access (if webhook security is implemented)",
+                    "404": {
+                        "description": "Institution profile not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4349,7 +5018,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal server error (e.g., database error, email sending failure)",
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4360,9 +5029,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/webhooks/stripe": {
-            "post": {
-                "description": "Processes webhook events from Stripe for subscription management",
+        "/api/v1/institution/jobs/{job_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing job posting",
                 "consumes": [
                     "application/json"
                 ],
@@ -4370,30 +5044,64 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "webhooks"
+                    "institution",
+                    "jobs"
                 ],
-                "summary": "Handle Stripe webhook events",
+                "summary": "Update a job posting",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Stripe signature for webhook verification",
-                        "name": "Stripe-Signature",
-                        "in": "header",
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "job_id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Updated job information",
+                        "name": "job",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Webhook event processed successfully",
+                        "description": "Job updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid job ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "boolean"
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - can only update own jobs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4411,29 +5119,52 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/ws": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Upgrades HTTP connection to WebSocket protocol for real-time communication",
+                "description": "Deletes an existing job posting",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "websocket"
+                    "institution",
+                    "jobs"
+                ],
+                "summary": "Delete a job posting",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "WebSocket connection upgrade",
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols",
+                    "200": {
+                        "description": "Job deleted successfully",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid job ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
-                        "description": "User not authenticated",
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -4441,419 +5172,5956 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "426": {
-                        "description": "Upgrade Required - Client must request WebSocket upgrade",
+                    "403": {
+                        "description": "Forbidden - can only delete own jobs",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "handlers.CancelRequest": {
-            "type": "object",
-            "properties": {
-                "reason": {
-                    "type": "string"
-                }
-            }
-        },
-        "handlers.CreateBlogPostRequest": {
-            "type": "object",
-            "required": [
-                "category",
-                "content",
-                "title"
-            ],
-            "properties": {
-                "category": {
-                    "type": "string"
-                },
-                "content": {
-                    "type": "string"
-                },
-                "excerpt": {
-                    "type": "string"
-                },
-                "is_featured": {
-                    "type": "boolean"
-                },
-                "is_published": {
-                    "type": "boolean"
-                },
-                "localizations": {
-                    "description": "Map of language code to localized fields",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "object",
-                        "additionalProperties": {
-                            "type": "string"
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
-                },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "title": {
-                    "type": "string"
+                }
+            }
+        },
+        "/api/v1/institution/jobs/{job_id}/applicants": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all applicants for a specific job posting",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "institution",
+                    "jobs"
+                ],
+                "summary": "Get job applicants",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of job applications with applicant details",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.JobApplication"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid job ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - can only view applicants for own jobs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/institution/jobs/{job_id}/applicants/{application_id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the status of an application (pending, viewed, shortlisted, rejected or hired) and notifies the applicant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "institution",
+                    "jobs"
+                ],
+                "summary": "Update job application status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Application ID",
+                        "name": "application_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New application status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ApplicationStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated job application",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobApplication"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid ID or unknown status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Job or application not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/institution/media": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uploads a logo, cover or gallery image of the institution itself, processed like school images",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "institution",
+                    "profile"
+                ],
+                "summary": "Upload an institution image",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Image",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "logo, cover or gallery",
+                        "name": "kind",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Caption",
+                        "name": "caption",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Stored image with its URLs",
+                        "schema": {
+                            "$ref": "#/definitions/models.Media"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing file or invalid kind",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Institution profile not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Gallery is full",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Not a supported image",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Image dimensions too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "File storage unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/institution/media/{media_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes an image of the institution with all its sizes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "institution",
+                    "profile"
+                ],
+                "summary": "Delete an institution image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Media ID",
+                        "name": "media_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid media ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Institution profile or image not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/institution/profile": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new institution profile or updates an existing one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "institution",
+                    "profile"
+                ],
+                "summary": "Create or update institution profile",
+                "parameters": [
+                    {
+                        "description": "Institution profile information",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.InstitutionProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile created or updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.InstitutionProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/institution/school/attributes": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the programs, age range, languages of instruction, tuition band, schedules and accreditations of the institution's school; omitted lists are cleared. Without age_min_months and age_max_months the age range is derived from the programs. Only institutions whose school claim was approved can edit their school.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "institution",
+                    "schools"
+                ],
+                "summary": "Update school programs and attributes",
+                "parameters": [
+                    {
+                        "description": "School attributes",
+                        "name": "attributes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SchoolAttributesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated school",
+                        "schema": {
+                            "$ref": "#/definitions/models.School"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid attributes",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Institution is not verified",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Institution profile or school not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/institution/school/media": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uploads a logo, cover or gallery image of the institution's school as multipart form field ` + "`" + `file` + "`" + ` (JPEG, PNG, GIF or WebP of at most MEDIA_MAX_SIZE_MB). The image is turned upright, stripped of EXIF data and stored in large, medium and thumb sizes, returned as URLs. A new logo or cover replaces the previous one; a gallery holds at most 30 images. Only institutions whose school claim was approved can upload images.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "institution",
+                    "schools"
+                ],
+                "summary": "Upload a school image",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Image",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "logo, cover or gallery",
+                        "name": "kind",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Caption",
+                        "name": "caption",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Stored image with its URLs",
+                        "schema": {
+                            "$ref": "#/definitions/models.Media"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing file or invalid kind",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Institution is not verified",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Institution profile or school not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Gallery is full",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Not a supported image",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Image dimensions too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "File storage unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/institution/school/media/{media_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the caption of an image of the institution's school, or its position in the gallery",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "institution",
+                    "schools"
+                ],
+                "summary": "Update a school image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Media ID",
+                        "name": "media_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New caption or position",
+                        "name": "media",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateMediaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated image",
+                        "schema": {
+                            "$ref": "#/definitions/models.Media"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid media ID or request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Institution is not verified",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Image not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes an image of the institution's school with all its sizes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "institution",
+                    "schools"
+                ],
+                "summary": "Delete a school image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Media ID",
+                        "name": "media_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid media ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Institution is not verified",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Image not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/institution/schools": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new school entry for an institution when not found in admin list. The institution manages the school once it claims the school with verification documents and an admin approves the claim.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "institution",
+                    "schools"
+                ],
+                "summary": "Create a new school",
+                "parameters": [
+                    {
+                        "description": "School information",
+                        "name": "school",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SchoolUploadData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "School created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.School"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Institution profile not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Institution already has a school",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/institution/schools/{school_id}/claims": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Submits a claim to manage a school, with verification documents as multipart form fields ` + "`" + `documents` + "`" + ` (1 to 5 PDF, JPEG or PNG files of at most ATTACHMENT_MAX_SIZE_MB each) and an optional ` + "`" + `message` + "`" + ` to the reviewer. The school is pending until an admin approves or rejects the claim; on approval the institution is mapped to the school and marked verified. An institution can have one pending claim at a time.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "institution",
+                    "schools"
+                ],
+                "summary": "Claim a school",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "School ID",
+                        "name": "school_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Verification documents",
+                        "name": "documents",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message to the reviewer",
+                        "name": "message",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Claim submitted",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchoolClaim"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid school ID or missing documents",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Institution profile or school not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Institution already has a school or a pending claim, or the school is already verified",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Document too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Document type not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Document rejected by the virus scanner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "File storage unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs": {
+            "get": {
+                "description": "Retrieves all active job postings in the system with the name and verified badge of the posting institution",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get all jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Text in the title or description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Part of the location",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Employment type, e.g. Full-time",
+                        "name": "employment_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of all active jobs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Job"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/login": {
+            "post": {
+                "description": "Authenticate a user and return a JWT token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "User login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful with token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "User account is inactive",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/messages/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Full-text search over the messages of conversations the current user is part of. The query supports web search syntax: quoted phrases, ` + "`" + `or` + "`" + ` and ` + "`" + `-` + "`" + ` to exclude words. Words are matched exactly, without stemming. Results are ordered by relevance, or by date with sort=recent. ` + "`" + `headline` + "`" + ` holds HTML-escaped excerpts with matches wrapped in ` + "`" + `\u003cmark\u003e` + "`" + ` tags.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Search messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only messages exchanged with this user",
+                        "name": "participant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only messages of this conversation",
+                        "name": "conversation_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only messages sent on or after this date (YYYY-MM-DD or RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only messages sent on or before this date (YYYY-MM-DD or RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "relevance",
+                        "description": "Order by relevance or recent",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching messages with highlights and pagination metadata",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing query or invalid filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/messages/send/{recipient_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sends a direct message to another user. Which roles may start a conversation with which is configured with MESSAGING_RULES (e.g. parents to schools, educators to institutions, institutions to their applicants); replying to a user who wrote first is always allowed. Messages to the same recipient are rate limited. Files uploaded with the attachment endpoint are sent by listing their IDs in attachment_ids; the content may then be empty.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Send a message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipient User ID",
+                        "name": "recipient_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message content",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Message sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid recipient ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Messaging this user is not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Recipient not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many messages to this recipient",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/messages/{message_id}/report": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reports a message the current user received as spam, harassment, inappropriate, a scam or other abuse. The report opens a moderation case for admins; with ` + "`" + `block` + "`" + ` the sender is blocked as well. A sender reported by REPORT_SUSPEND_THRESHOLD different users within REPORT_SUSPEND_WINDOW_HOURS is suspended automatically until an admin reviews the reports.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Report a message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Report reason and details",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReportMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Report created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid reason",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Message already reported",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the current user's in-app notifications, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List notifications",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Only return unread notifications",
+                        "name": "unread_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of notifications with pagination metadata and unread count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the current user's notification preferences per category and channel, and how often notification emails are batched into a digest. Channels without a stored preference are enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Get notification preferences",
+                "responses": {
+                    "200": {
+                        "description": "Notification preferences by category and channel",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enables or disables notifications for the given category and channel combinations and optionally changes the digest frequency (immediate, hourly, daily or weekly)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Update notification preferences",
+                "parameters": [
+                    {
+                        "description": "Preferences to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateNotificationPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated notification preferences",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or unknown category/channel",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications/read-all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks every unread notification of the current user as read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark all notifications as read",
+                "responses": {
+                    "200": {
+                        "description": "Number of notifications marked as read",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications/unread-count": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the number of unread in-app notifications of the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Get unread notification count",
+                "responses": {
+                    "200": {
+                        "description": "Unread notification count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications/{notification_id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks one of the current user's notifications as read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark notification as read",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Notification ID",
+                        "name": "notification_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification marked as read",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid notification ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/parent/messages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all messages sent to or by the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "parent",
+                    "messages"
+                ],
+                "summary": "Get user messages",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Filter to show only unread messages",
+                        "name": "unread_only",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of messages",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Message"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/parent/messages/send/{recipient_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sends a message to another parent or to a school, subject to MESSAGING_RULES and the per-recipient rate limit. Same as /api/v1/messages/send/{recipient_id}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "parent",
+                    "messages"
+                ],
+                "summary": "Send a message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipient User ID",
+                        "name": "recipient_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message content",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Message sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid recipient ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Messaging this user is not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Recipient not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many messages to this recipient",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/parent/messages/{message_id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks a specific message as read by the recipient",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "parent",
+                    "messages"
+                ],
+                "summary": "Mark message as read",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message marked as read successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid message ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - can only mark messages addressed to you",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/parent/profile": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new parent profile or updates an existing one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "parent",
+                    "profile"
+                ],
+                "summary": "Create or update parent profile",
+                "parameters": [
+                    {
+                        "description": "Parent profile information",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ParentProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile created or updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.ParentProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/parent/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists up to 20 schools recommended to the parent, best first, each with a score and the reasons for it. Schools are scored on their distance from saved schools and saved searches, their similarity to saved schools (programs, languages, accreditations, schedules and tuition) and their popularity (saves and ratings). Recommendations are recomputed in the background when the parent saves or removes schools or changes saved searches, and daily.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "parent",
+                    "schools"
+                ],
+                "summary": "Get school recommendations",
+                "responses": {
+                    "200": {
+                        "description": "Recommended schools, with meta.computed_at",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Parent profile not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/parent/schools/save/{school_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a school to the parent's saved schools list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "parent",
+                    "schools"
+                ],
+                "summary": "Save a school",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "School ID",
+                        "name": "school_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "School saved successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid school ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Parent profile or school not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "School already saved",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a school from the parent's saved schools list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "parent",
+                    "schools"
+                ],
+                "summary": "Delete a saved school",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "School ID",
+                        "name": "school_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "School removed successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid school ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Parent profile or school not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/parent/schools/saved": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the list of schools saved by the parent",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "parent",
+                    "schools"
+                ],
+                "summary": "Get saved schools",
+                "responses": {
+                    "200": {
+                        "description": "List of saved schools",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.School"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Parent profile not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/parent/schools/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search for schools with various filters and pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "parent",
+                    "schools"
+                ],
+                "summary": "Search for schools",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by school name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by city",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by country code",
+                        "name": "country_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of schools with pagination metadata",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/presence/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns whether a user currently has open WebSocket connections and how many devices are connected. Only available for users the caller shares a conversation with or may message, and not between users who blocked each other.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "Get user presence",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Presence of the user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Database error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/register": {
+            "post": {
+                "description": "Register a new user with the specified role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration information",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User registered successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Email already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reviews": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new review for a school (parents and educators only). The review will be pending approval by an admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Create a new review",
+                "parameters": [
+                    {
+                        "description": "Review information",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Review created successfully and pending approval",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only parents and educators can create reviews",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "User has already reviewed this school",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reviews/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all reviews submitted by the authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews",
+                    "users"
+                ],
+                "summary": "Get current user's reviews",
+                "responses": {
+                    "200": {
+                        "description": "List of reviews by the current user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reviews/{review_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Allows an authenticated user to update their own review. The updated review will be reset to pending status.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Update a review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated review information",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review updated successfully and pending approval",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid review ID/request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - user can only update their own reviews",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Allows an authenticated user to delete their own review.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Delete a review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "review_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid review ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - user can only delete their own reviews",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schools/compare": {
+            "get": {
+                "description": "Compares 2 to 5 schools, e.g. the schools a parent saved. Each school comes with its profile and Montessori attributes, the average and distribution of its approved review ratings, the number of upcoming events and active job postings of its institution, and its distance from lat/lng when given. Schools are returned in the order of ids; IDs of merged schools are replaced by the surviving school.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schools"
+                ],
+                "summary": "Compare schools",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated IDs of 2 to 5 schools",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude to measure distances from",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude to measure distances from",
+                        "name": "lng",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Compared schools",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/handlers.SchoolComparison"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid school IDs or location",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schools/public": {
+            "get": {
+                "description": "Public school search. ` + "`" + `q` + "`" + ` matches name, city, state and address as word prefixes and tolerates typos in the name and city; results are ranked by relevance, or by name with sort=name or without a query. With lat and lng results are sorted by distance, include DistanceKm and can be limited with radius_km; bbox limits them to a map area. Schools are located by geocoding their address. Schools can be filtered by their Montessori attributes; each of program, language, tuition, schedule and accreditation takes comma-separated values and matches schools with any of them, and age_months matches schools whose age range includes the child's age. The response includes facets with the number of matching schools per country, state and program; each facet ignores its own filter so other values can be offered.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schools"
+                ],
+                "summary": "Search schools",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search text (name is accepted as an alias)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by city",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by state",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by country code",
+                        "name": "country_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude of the location to search around",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the location to search around",
+                        "name": "lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only schools within this distance of lat/lng, at most 500",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only schools within the map area west,south,east,north",
+                        "name": "bbox",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "distance, relevance or name; defaults to distance with lat/lng and relevance with q",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Programs: toddler, primary, elementary, adolescent",
+                        "name": "program",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Age of the child in months",
+                        "name": "age_months",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Languages of instruction as ISO 639-1 codes, e.g. en,it",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tuition bands: free, low, medium, high",
+                        "name": "tuition",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedules: half_day, full_day",
+                        "name": "schedule",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Accreditations: ami, ams, imc, msa, other",
+                        "name": "accreditation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching schools with pagination metadata and facets",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid location or attribute filters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schools/{school_id}": {
+            "get": {
+                "description": "Retrieves a school by ID with its logo, cover and gallery images. The ID of a school that was merged into another one redirects to the surviving school.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schools"
+                ],
+                "summary": "Get a school",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "School ID",
+                        "name": "school_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "School",
+                        "schema": {
+                            "$ref": "#/definitions/models.School"
+                        }
+                    },
+                    "301": {
+                        "description": "The school was merged, Location names the surviving school"
+                    },
+                    "400": {
+                        "description": "Invalid school ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schools/{school_id}/reviews": {
+            "get": {
+                "description": "Retrieves all approved reviews for a specific school, along with the average rating and total review count.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews",
+                    "schools"
+                ],
+                "summary": "Get school reviews",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "School ID",
+                        "name": "school_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of reviews with average rating",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid school ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "School not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/searches": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the saved school and job searches of the logged-in user, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "searches"
+                ],
+                "summary": "List saved searches",
+                "responses": {
+                    "200": {
+                        "description": "Saved searches",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.SavedSearch"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Saves a named school or job search with the filters of GET /api/v1/schools/public or GET /api/v1/jobs. Searches with a daily or weekly frequency are run on that schedule and the user is alerted in-app and by email about schools and jobs added since the search was saved that match it. Alerts follow the saved_searches notification preferences. A user can save up to 20 searches.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "searches"
+                ],
+                "summary": "Save a search",
+                "parameters": [
+                    {
+                        "description": "Name, kind, filters and alert frequency",
+                        "name": "search",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SavedSearchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Saved search",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearch"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or filters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Too many saved searches",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/searches/{search_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the name, kind, filters and alert frequency of a saved search of the logged-in user. Schools and jobs already alerted about are not alerted about again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "searches"
+                ],
+                "summary": "Update a saved search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "search_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Name, kind, filters and alert frequency",
+                        "name": "search",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SavedSearchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated saved search",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearch"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid search ID, request body or filters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Saved search not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a saved search of the logged-in user and stops its alerts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "searches"
+                ],
+                "summary": "Delete a saved search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "search_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Saved search deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid search ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Saved search not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/searches/{search_id}/results": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Runs a saved search of the logged-in user and returns a page of results shaped like GET /api/v1/schools/public or GET /api/v1/jobs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "searches"
+                ],
+                "summary": "Run a saved search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "search_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching schools or jobs with pagination metadata",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid search ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Saved search not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscription/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels the current user's active subscription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Cancel user subscription",
+                "parameters": [
+                    {
+                        "description": "Cancellation reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CancelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription canceled successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "User not authenticated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No active subscription found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscription/checkout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a Stripe checkout session for a user to subscribe to a plan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Create a checkout session for subscription",
+                "parameters": [
+                    {
+                        "enum": [
+                            "monthly",
+                            "annual"
+                        ],
+                        "type": "string",
+                        "description": "Subscription plan (monthly or annual)",
+                        "name": "plan",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Checkout session created successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "User not authenticated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "User already has an active subscription",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscription/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the current user's subscription details",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Get user subscription",
+                "responses": {
+                    "200": {
+                        "description": "Subscription details",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "User not authenticated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No subscription found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/unsubscribe": {
+            "get": {
+                "description": "Renders a confirmation page for a signed unsubscribe link. The actual opt-out happens on POST so that link scanners cannot unsubscribe users.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Unsubscribe confirmation page",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signed unsubscribe token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Confirmation page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or missing token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Disables email notifications for the category encoded in a signed unsubscribe token. Supports RFC 8058 one-click unsubscribe (List-Unsubscribe-Post).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "One-click unsubscribe",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signed unsubscribe token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unsubscribed successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or missing token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/blocked": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the users the current user has blocked, most recent first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "List blocked users",
+                "responses": {
+                    "200": {
+                        "description": "Blocked users",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{user_id}/block": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Blocks a user. Messages and typing indicators between the two users are rejected in both directions until the block is removed. Existing conversations stay in the inbox.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Block a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID to block",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User blocked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or blocking yourself",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a block so the two users can message each other again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Unblock a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID to unblock",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User unblocked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User is not blocked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/media/{path}": {
+            "get": {
+                "description": "Serves the images whose URLs are returned with schools and institutions when MEDIA_BASE_URL is not set to a CDN",
+                "produces": [
+                    "image/jpeg",
+                    "image/png"
+                ],
+                "tags": [
+                    "schools"
+                ],
+                "summary": "Get an image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Image path",
+                        "name": "path",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Image not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "File storage unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/email/postmark": {
+            "post": {
+                "description": "Receives Postmark bounce and spam complaint webhooks. Requires the EMAIL_WEBHOOK_SECRET in the ` + "`" + `X-Webhook-Secret` + "`" + ` header or as the HTTP basic authentication password, e.g. in the URL configured at the provider. Only available when EMAIL_WEBHOOK_SECRET is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Postmark bounce and complaint webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email webhook secret, unless sent with basic authentication",
+                        "name": "X-Webhook-Secret",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event processed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid webhook secret",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/email/sendgrid": {
+            "post": {
+                "description": "Receives SendGrid Event Webhook batches. Bounce and spam report events are recorded, all other events are ignored. Requires the EMAIL_WEBHOOK_SECRET in the ` + "`" + `X-Webhook-Secret` + "`" + ` header or as the HTTP basic authentication password, e.g. in the URL configured at the provider. Only available when EMAIL_WEBHOOK_SECRET is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "SendGrid bounce and complaint webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email webhook secret, unless sent with basic authentication",
+                        "name": "X-Webhook-Secret",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Events processed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid webhook secret",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/email/ses": {
+            "post": {
+                "description": "Receives SES bounce and complaint notifications delivered by an SNS HTTPS subscription. SNS message signatures are verified and subscription confirmations are confirmed automatically. Requires the EMAIL_WEBHOOK_SECRET in the ` + "`" + `X-Webhook-Secret` + "`" + ` header or as the HTTP basic authentication password, e.g. in the URL configured at the provider. Only available when EMAIL_WEBHOOK_SECRET is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Amazon SES bounce and complaint webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email webhook secret, unless sent with basic authentication",
+                        "name": "X-Webhook-Secret",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification processed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid webhook secret",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/notify-unread-message": {
+            "post": {
+                "description": "Receives a payload from the message queue system to process and send email notifications for unread messages. Recipients with a digest frequency other than immediate get the message in their next digest instead. This endpoint is intended for internal system use and should be secured.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks",
+                    "notifications"
+                ],
+                "summary": "Webhook for Unread Message Notification",
+                "parameters": [
+                    {
+                        "description": "Details of the unread message to be processed for notification",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UnreadMessagePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification processed successfully (email sent, queued for digest, message already read, or recipient opted out)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access (if webhook security is implemented)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error (e.g., database error, email sending failure)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/stripe": {
+            "post": {
+                "description": "Processes webhook events from Stripe for subscription management",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Handle Stripe webhook events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stripe signature for webhook verification",
+                        "name": "Stripe-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook event processed successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "boolean"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/ws": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upgrades HTTP connection to WebSocket protocol for real-time communication. Clients send ` + "`" + `message` + "`" + ` and ` + "`" + `typing` + "`" + ` with a ` + "`" + `recipient_id` + "`" + ` (messages follow the same MESSAGING_RULES and rate limit as the HTTP endpoint and are acknowledged with ` + "`" + `message_sent` + "`" + `; ` + "`" + `attachment_ids` + "`" + ` sends uploaded attachments), acknowledge received messages with ` + "`" + `delivered` + "`" + ` and mark them read with ` + "`" + `read` + "`" + ` (payload ` + "`" + `message_id` + "`" + ` or ` + "`" + `message_ids` + "`" + `); senders receive ` + "`" + `typing` + "`" + `, ` + "`" + `delivered` + "`" + ` and ` + "`" + `read` + "`" + ` events. Clients can also send ` + "`" + `subscribe` + "`" + ` and ` + "`" + `unsubscribe` + "`" + ` messages with a ` + "`" + `topic` + "`" + ` of ` + "`" + `events` + "`" + `, ` + "`" + `event:{id}` + "`" + `, ` + "`" + `institution:{id}:applicants` + "`" + `, ` + "`" + `school:{id}:reviews` + "`" + ` or ` + "`" + `blog:featured` + "`" + ` to receive live updates.",
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "WebSocket connection upgrade",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "User not authenticated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "426": {
+                        "description": "Upgrade Required - Client must request WebSocket upgrade",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.ApplicationStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "viewed",
+                        "shortlisted",
+                        "rejected",
+                        "hired"
+                    ]
+                }
+            }
+        },
+        "handlers.ArchiveConversationRequest": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.CancelRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateBlogPostRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "content",
+                "title"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "excerpt": {
+                    "type": "string"
+                },
+                "is_featured": {
+                    "type": "boolean"
+                },
+                "is_published": {
+                    "type": "boolean"
+                },
+                "localizations": {
+                    "description": "Map of language code to localized fields",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
         "handlers.CreateEventRequest": {
             "type": "object",
             "required": [
-                "audience",
-                "description",
-                "end_date",
-                "event_type",
-                "start_date",
-                "title"
+                "audience",
+                "description",
+                "end_date",
+                "event_type",
+                "start_date",
+                "title"
+            ],
+            "properties": {
+                "audience": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "is_published": {
+                    "type": "boolean"
+                },
+                "localizations": {
+                    "description": "Map of language code to localized title/description",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "location": {
+                    "type": "string"
+                },
+                "max_attendees": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "virtual_event": {
+                    "type": "boolean"
+                },
+                "virtual_event_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateReviewRequest": {
+            "type": "object",
+            "required": [
+                "comment",
+                "rating",
+                "school_id"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "minLength": 10
+                },
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1
+                },
+                "school_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.EducatorProfileRequest": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "experience": {
+                    "type": "string"
+                },
+                "qualifications": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.FeatureRequest": {
+            "description": "Request to update the featured status of an event",
+            "type": "object",
+            "properties": {
+                "featured": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.InstitutionProfileRequest": {
+            "type": "object",
+            "required": [
+                "institution_name"
+            ],
+            "properties": {
+                "institution_name": {
+                    "type": "string"
+                },
+                "verification_docs": {
+                    "description": "URL or path",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.JobApplicationRequest": {
+            "type": "object",
+            "properties": {
+                "cover_letter": {
+                    "type": "string"
+                },
+                "resume_url": {
+                    "description": "Optional, but if provided, must be URL",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.JobRequest": {
+            "type": "object",
+            "required": [
+                "description",
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "employment_type": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "e.g., \"2024-12-31T23:59:59Z\"",
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "salary_range": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.MergeSchoolDuplicateRequest": {
+            "type": "object",
+            "properties": {
+                "keep_school_id": {
+                    "description": "Defaults to the older school of the pair",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.MergeSchoolsRequest": {
+            "type": "object",
+            "properties": {
+                "merge_school_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.MessageRequest": {
+            "type": "object",
+            "properties": {
+                "attachment_ids": {
+                    "description": "IDs returned by the attachment upload endpoint",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "content": {
+                    "description": "Required unless attachments are sent",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ModerateReviewRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "notes": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "approved",
+                        "rejected"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ReviewStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.MuteConversationRequest": {
+            "type": "object",
+            "properties": {
+                "muted": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.NotificationPreferenceItem": {
+            "type": "object",
+            "required": [
+                "category",
+                "channel"
             ],
             "properties": {
-                "audience": {
+                "category": {
+                    "enum": [
+                        "messages",
+                        "job_applications",
+                        "events",
+                        "reviews",
+                        "newsletters",
+                        "school_claims",
+                        "saved_searches"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NotificationCategory"
+                        }
+                    ]
+                },
+                "channel": {
+                    "enum": [
+                        "email",
+                        "in_app",
+                        "websocket"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NotificationChannel"
+                        }
+                    ]
+                },
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ParentProfileRequest": {
+            "type": "object",
+            "properties": {
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "first_name",
+                "last_name",
+                "password",
+                "role"
+            ],
+            "properties": {
+                "email": {
                     "type": "string"
                 },
-                "description": {
+                "first_name": {
                     "type": "string"
                 },
-                "end_date": {
+                "institution_name": {
+                    "description": "Role-specific fields",
                     "type": "string"
                 },
-                "event_type": {
+                "last_name": {
                     "type": "string"
                 },
-                "is_published": {
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "role": {
+                    "description": "Added admin for potential setup",
+                    "enum": [
+                        "institution",
+                        "educator",
+                        "parent",
+                        "training_center",
+                        "admin"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.UserRole"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.ReportMessageRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "block": {
+                    "description": "Also block the sender",
                     "type": "boolean"
                 },
-                "localizations": {
-                    "description": "Map of language code to localized title/description",
+                "details": {
+                    "type": "string"
+                },
+                "reason": {
+                    "enum": [
+                        "spam",
+                        "harassment",
+                        "inappropriate",
+                        "scam",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MessageReportReason"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.ReviewMessageReportRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "notes": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "dismissed",
+                        "actioned"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MessageReportStatus"
+                        }
+                    ]
+                },
+                "suspend_user": {
+                    "description": "true suspends the reported user, false reinstates them",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ReviewSchoolClaimRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "notes": {
+                    "description": "Shown to the institution",
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "approved",
+                        "rejected"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SchoolClaimStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.SavedSearchRequest": {
+            "type": "object",
+            "required": [
+                "kind",
+                "name"
+            ],
+            "properties": {
+                "filters": {
                     "type": "object",
-                    "additionalProperties": {
+                    "additionalProperties": true
+                },
+                "frequency": {
+                    "description": "Defaults to daily",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "never"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SavedSearchFrequency"
+                        }
+                    ]
+                },
+                "kind": {
+                    "enum": [
+                        "schools",
+                        "jobs"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SavedSearchKind"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "handlers.SchoolAttributesRequest": {
+            "type": "object",
+            "properties": {
+                "accreditations": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/models.SchoolAccreditation"
+                    }
+                },
+                "age_max_months": {
+                    "type": "integer",
+                    "maximum": 240,
+                    "minimum": 0
+                },
+                "age_min_months": {
+                    "type": "integer",
+                    "maximum": 240,
+                    "minimum": 0
+                },
+                "languages": {
+                    "description": "ISO 639-1 codes, e.g. \"en\"",
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
                         "type": "string"
                     }
                 },
-                "location": {
+                "programs": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/models.SchoolProgram"
+                    }
+                },
+                "schedules": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/models.SchoolSchedule"
+                    }
+                },
+                "tuition_band": {
+                    "enum": [
+                        "free",
+                        "low",
+                        "medium",
+                        "high"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SchoolTuitionBand"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.SchoolComparison": {
+            "type": "object",
+            "properties": {
+                "active_jobs": {
+                    "description": "Open job postings of the school's institution, a proxy for staff turnover",
+                    "type": "integer"
+                },
+                "distance_km": {
+                    "description": "From the given location, null without one or without coordinates",
+                    "type": "number"
+                },
+                "rating": {
+                    "$ref": "#/definitions/handlers.SchoolRatingSummary"
+                },
+                "school": {
+                    "description": "Profile and Montessori attributes, with logo and cover",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.School"
+                        }
+                    ]
+                },
+                "upcoming_events": {
+                    "description": "Published events of the school's institution that have not started yet",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.SchoolRatingSummary": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "description": "Rounded to two decimals, null without reviews",
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "distribution": {
+                    "description": "Number of reviews per rating, \"1\" to \"5\", always all five",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.SchoolUploadData": {
+            "type": "object",
+            "required": [
+                "country_code",
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "city": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "contact_email": {
+                    "type": "string"
+                },
+                "contact_phone": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "country_code": {
                     "type": "string"
                 },
-                "max_attendees": {
-                    "type": "integer"
+                "latitude": {
+                    "description": "Optional coordinates. When omitted the address is geocoded.",
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
                 },
-                "start_date": {
-                    "type": "string"
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
                 },
-                "title": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
                 },
-                "virtual_event": {
-                    "type": "boolean"
+                "state": {
+                    "type": "string",
+                    "maxLength": 100
                 },
-                "virtual_event_url": {
+                "website": {
                     "type": "string"
+                },
+                "zip_code": {
+                    "type": "string",
+                    "maxLength": 20
                 }
             }
         },
-        "handlers.CreateReviewRequest": {
+        "handlers.UnreadMessagePayload": {
             "type": "object",
-            "required": [
-                "comment",
-                "rating",
-                "school_id"
-            ],
             "properties": {
-                "comment": {
+                "message_id": {
+                    "type": "integer"
+                },
+                "recipient_id": {
+                    "type": "integer"
+                },
+                "sender_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.UpdateMediaRequest": {
+            "type": "object",
+            "properties": {
+                "caption": {
                     "type": "string",
-                    "maxLength": 1000,
-                    "minLength": 10
+                    "maxLength": 255
                 },
-                "rating": {
+                "position": {
                     "type": "integer",
-                    "maximum": 5,
-                    "minimum": 1
+                    "minimum": 0
+                }
+            }
+        },
+        "handlers.UpdateNotificationPreferencesRequest": {
+            "type": "object",
+            "properties": {
+                "digest_frequency": {
+                    "enum": [
+                        "immediate",
+                        "hourly",
+                        "daily",
+                        "weekly"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DigestFrequency"
+                        }
+                    ]
                 },
-                "school_id": {
-                    "type": "integer"
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.NotificationPreferenceItem"
+                    }
                 }
             }
         },
-        "handlers.EducatorProfileRequest": {
+        "handlers.UserRoleUpdateRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "enum": [
+                        "institution",
+                        "educator",
+                        "parent",
+                        "training_center",
+                        "admin"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.UserRole"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.UserStatusUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.DigestFrequency": {
+            "type": "string",
+            "enum": [
+                "immediate",
+                "hourly",
+                "daily",
+                "weekly"
+            ],
+            "x-enum-comments": {
+                "DigestImmediate": "One email per notification"
+            },
+            "x-enum-varnames": [
+                "DigestImmediate",
+                "DigestHourly",
+                "DigestDaily",
+                "DigestWeekly"
+            ]
+        },
+        "models.EducatorProfile": {
+            "description": "Educator profile information",
             "type": "object",
             "properties": {
+                "applications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JobApplication"
+                    }
+                },
                 "bio": {
                     "type": "string"
                 },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-01-01T00:00:00Z"
+                },
                 "experience": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "qualifications": {
                     "type": "string"
+                },
+                "recommendationsComputedAt": {
+                    "description": "Nil until job recommendations are first requested",
+                    "type": "string"
+                },
+                "savedSchools": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.School"
+                    }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "user": {
+                    "description": "Eager load user details",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                },
+                "userID": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.FeatureRequest": {
-            "description": "Request to update the featured status of an event",
+        "models.ImportJob": {
+            "description": "Import job information",
             "type": "object",
             "properties": {
-                "featured": {
-                    "type": "boolean"
+                "columnMapping": {
+                    "description": "JSON object of field to column header",
+                    "type": "string"
+                },
+                "createdByUserID": {
+                    "description": "Admin who uploaded the file and receives progress updates",
+                    "type": "integer"
+                },
+                "createdCount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "error": {
+                    "description": "Why the job failed",
+                    "type": "string"
+                },
+                "errorFileKey": {
+                    "description": "Key of the CSV report of rejected rows, if any",
+                    "type": "string"
+                },
+                "failedCount": {
+                    "type": "integer"
+                },
+                "fileKey": {
+                    "description": "Key of the uploaded file in the file storage backend, cleared once processed",
+                    "type": "string"
+                },
+                "fileName": {
+                    "description": "Original file name",
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "processedRows": {
+                    "type": "integer"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ImportJobStatus"
+                },
+                "totalRows": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
                 }
             }
         },
-        "handlers.InstitutionProfileRequest": {
-            "type": "object",
-            "required": [
-                "institution_name"
+        "models.ImportJobStatus": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "processing",
+                "completed",
+                "failed",
+                "cancelled"
             ],
+            "x-enum-varnames": [
+                "ImportJobQueued",
+                "ImportJobProcessing",
+                "ImportJobCompleted",
+                "ImportJobFailed",
+                "ImportJobCancelled"
+            ]
+        },
+        "models.InstitutionProfile": {
+            "description": "Institution or Training Center profile information",
+            "type": "object",
             "properties": {
-                "institution_name": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "institutionName": {
                     "type": "string"
                 },
-                "verification_docs": {
-                    "description": "URL or path",
-                    "type": "string"
-                }
-            }
-        },
-        "handlers.JobApplicationRequest": {
-            "type": "object",
-            "properties": {
-                "cover_letter": {
+                "isVerified": {
+                    "description": "Set when an admin approves the institution's school claim, shown as a public badge",
+                    "type": "boolean"
+                },
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Job"
+                    }
+                },
+                "media": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Media"
+                    }
+                },
+                "school": {
+                    "$ref": "#/definitions/models.School"
+                },
+                "schoolID": {
+                    "description": "A school can be mapped to only one institution/training center",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "user": {
+                    "description": "Eager load user details if needed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                },
+                "userID": {
+                    "description": "Foreign key to User table",
+                    "type": "integer"
+                },
+                "verificationDocs": {
+                    "description": "Path to verification documents",
                     "type": "string"
                 },
-                "resume_url": {
-                    "description": "Optional, but if provided, must be URL",
+                "verifiedAt": {
                     "type": "string"
                 }
             }
         },
-        "handlers.JobRequest": {
+        "models.Job": {
+            "description": "Job posting information",
             "type": "object",
-            "required": [
-                "description",
-                "title"
-            ],
             "properties": {
+                "applications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JobApplication"
+                    }
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-01-01T00:00:00Z"
+                },
                 "description": {
                     "type": "string"
                 },
-                "employment_type": {
+                "employmentType": {
+                    "description": "e.g., Full-time, Part-time",
                     "type": "string"
                 },
-                "expires_at": {
-                    "description": "e.g., \"2024-12-31T23:59:59Z\"",
+                "expiresAt": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "institutionProfile": {
+                    "description": "Eager load institution profile",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.InstitutionProfile"
+                        }
+                    ]
+                },
+                "institutionProfileID": {
+                    "description": "Links to InstitutionProfile",
+                    "type": "integer"
+                },
+                "isActive": {
+                    "type": "boolean"
+                },
                 "location": {
                     "type": "string"
                 },
-                "salary_range": {
+                "postedAt": {
+                    "type": "string"
+                },
+                "salaryRange": {
                     "type": "string"
                 },
                 "title": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
                 }
             }
         },
-        "handlers.LoginRequest": {
+        "models.JobApplication": {
+            "description": "Job application information",
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "appliedAt": {
                     "type": "string"
                 },
-                "password": {
+                "coverLetter": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "educator": {
+                    "$ref": "#/definitions/models.EducatorProfile"
+                },
+                "educatorProfileID": {
+                    "description": "Links to EducatorProfile",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "job": {
+                    "$ref": "#/definitions/models.Job"
+                },
+                "jobID": {
+                    "type": "integer"
+                },
+                "resumeURL": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "e.g., pending, viewed, shortlisted, rejected, hired",
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
                 }
             }
         },
-        "handlers.MessageRequest": {
+        "models.Media": {
+            "description": "School or institution image",
             "type": "object",
-            "required": [
-                "content"
-            ],
             "properties": {
-                "content": {
+                "caption": {
                     "type": "string"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "institutionProfileID": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.MediaKind"
+                },
+                "position": {
+                    "description": "Order within the gallery",
+                    "type": "integer"
+                },
+                "schoolID": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "uploadedByUserID": {
+                    "type": "integer"
+                },
+                "urls": {
+                    "description": "Public URLs of the sizes by name: thumb, medium and large",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.MediaURL"
+                    }
                 }
             }
         },
-        "handlers.ModerateReviewRequest": {
-            "type": "object",
-            "required": [
-                "status"
+        "models.MediaKind": {
+            "type": "string",
+            "enum": [
+                "logo",
+                "cover",
+                "gallery"
             ],
+            "x-enum-comments": {
+                "MediaCover": "One per school or institution",
+                "MediaGallery": "Ordered by Position",
+                "MediaLogo": "One per school or institution"
+            },
+            "x-enum-varnames": [
+                "MediaLogo",
+                "MediaCover",
+                "MediaGallery"
+            ]
+        },
+        "models.MediaURL": {
+            "description": "Image URL and dimensions",
+            "type": "object",
             "properties": {
-                "notes": {
+                "height": {
+                    "type": "integer"
+                },
+                "url": {
                     "type": "string"
                 },
-                "status": {
-                    "enum": [
-                        "approved",
-                        "rejected"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.ReviewStatus"
-                        }
-                    ]
+                "width": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.ParentProfileRequest": {
+        "models.Message": {
+            "description": "Message information",
             "type": "object",
             "properties": {
-                "phone_number": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MessageAttachment"
+                    }
+                },
+                "content": {
+                    "type": "string"
+                },
+                "conversationID": {
+                    "description": "Thread the message belongs to",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "deliveredAt": {
+                    "description": "Set when a client of the recipient acknowledges the message",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "isRead": {
+                    "description": "Index for faster querying of unread messages",
+                    "type": "boolean"
+                },
+                "readAt": {
+                    "type": "string"
+                },
+                "recipient": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "recipientID": {
+                    "type": "integer"
+                },
+                "sender": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "senderID": {
+                    "type": "integer"
+                },
+                "sentAt": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
                 }
             }
         },
-        "handlers.RegisterRequest": {
+        "models.MessageAttachment": {
+            "description": "Message attachment information",
             "type": "object",
-            "required": [
-                "email",
-                "first_name",
-                "last_name",
-                "password",
-                "role"
-            ],
             "properties": {
-                "email": {
+                "checksum": {
+                    "description": "Hex-encoded SHA-256 of the content",
+                    "type": "string"
+                },
+                "contentType": {
+                    "description": "Detected MIME type",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "fileName": {
+                    "description": "Original file name, shown to the recipient",
                     "type": "string"
                 },
-                "first_name": {
-                    "type": "string"
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "messageID": {
+                    "description": "Nil until the attachment is sent with a message",
+                    "type": "integer"
                 },
-                "institution_name": {
-                    "description": "Role-specific fields",
-                    "type": "string"
+                "size": {
+                    "description": "Bytes",
+                    "type": "integer"
                 },
-                "last_name": {
+                "storageKey": {
+                    "description": "Key in the file storage backend",
                     "type": "string"
                 },
-                "password": {
+                "updated_at": {
                     "type": "string",
-                    "minLength": 8
+                    "example": "2023-01-01T00:00:00Z"
                 },
-                "role": {
-                    "description": "Added admin for potential setup",
-                    "enum": [
-                        "institution",
-                        "educator",
-                        "parent",
-                        "training_center",
-                        "admin"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.UserRole"
-                        }
-                    ]
+                "uploaderID": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.SchoolUploadData": {
+        "models.MessageReport": {
+            "description": "Message report information",
             "type": "object",
-            "required": [
-                "country_code",
-                "name"
-            ],
             "properties": {
-                "address": {
-                    "type": "string"
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
                 },
-                "city": {
-                    "type": "string"
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-01-01T00:00:00Z"
                 },
-                "contact_email": {
+                "details": {
                     "type": "string"
                 },
-                "contact_phone": {
-                    "type": "string"
+                "id": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "country_code": {
-                    "type": "string"
+                "message": {
+                    "$ref": "#/definitions/models.Message"
                 },
-                "name": {
+                "messageContent": {
+                    "description": "Copy of the message as reported, kept if the message is deleted",
                     "type": "string"
                 },
-                "state": {
-                    "type": "string"
+                "messageID": {
+                    "type": "integer"
                 },
-                "website": {
+                "moderatorNotes": {
                     "type": "string"
                 },
-                "zip_code": {
-                    "type": "string"
-                }
-            }
-        },
-        "handlers.UnreadMessagePayload": {
-            "type": "object",
-            "properties": {
-                "message_id": {
+                "reason": {
+                    "$ref": "#/definitions/models.MessageReportReason"
+                },
+                "reportedUser": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "reportedUserID": {
+                    "description": "Sender of the message",
                     "type": "integer"
                 },
-                "recipient_id": {
+                "reporter": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "reporterID": {
                     "type": "integer"
                 },
-                "sender_id": {
+                "reviewedAt": {
+                    "type": "string"
+                },
+                "reviewedBy": {
+                    "description": "Admin who reviewed the report",
                     "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.MessageReportStatus"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
                 }
             }
         },
-        "handlers.UserRoleUpdateRequest": {
-            "type": "object",
-            "required": [
-                "role"
+        "models.MessageReportReason": {
+            "type": "string",
+            "enum": [
+                "spam",
+                "harassment",
+                "inappropriate",
+                "scam",
+                "other"
             ],
-            "properties": {
-                "role": {
-                    "enum": [
-                        "institution",
-                        "educator",
-                        "parent",
-                        "training_center",
-                        "admin"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.UserRole"
-                        }
-                    ]
-                }
-            }
+            "x-enum-varnames": [
+                "ReportReasonSpam",
+                "ReportReasonHarassment",
+                "ReportReasonInappropriate",
+                "ReportReasonScam",
+                "ReportReasonOther"
+            ]
         },
-        "handlers.UserStatusUpdateRequest": {
-            "type": "object",
-            "properties": {
-                "is_active": {
-                    "type": "boolean"
-                }
-            }
+        "models.MessageReportStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "dismissed",
+                "actioned"
+            ],
+            "x-enum-varnames": [
+                "MessageReportPending",
+                "MessageReportDismissed",
+                "MessageReportActioned"
+            ]
         },
-        "models.EducatorProfile": {
-            "description": "Educator profile information",
+        "models.NotificationCategory": {
+            "type": "string",
+            "enum": [
+                "messages",
+                "job_applications",
+                "events",
+                "reviews",
+                "newsletters",
+                "school_claims",
+                "saved_searches"
+            ],
+            "x-enum-varnames": [
+                "NotificationCategoryMessages",
+                "NotificationCategoryJobApplications",
+                "NotificationCategoryEvents",
+                "NotificationCategoryReviews",
+                "NotificationCategoryNewsletters",
+                "NotificationCategorySchoolClaims",
+                "NotificationCategorySavedSearches"
+            ]
+        },
+        "models.NotificationChannel": {
+            "type": "string",
+            "enum": [
+                "email",
+                "in_app",
+                "websocket"
+            ],
+            "x-enum-varnames": [
+                "NotificationChannelEmail",
+                "NotificationChannelInApp",
+                "NotificationChannelWebSocket"
+            ]
+        },
+        "models.ParentProfile": {
+            "description": "Parent profile information",
             "type": "object",
             "properties": {
-                "applications": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.JobApplication"
-                    }
-                },
-                "bio": {
-                    "type": "string"
-                },
                 "created_at": {
                     "type": "string",
                     "example": "2023-01-01T00:00:00Z"
@@ -4863,14 +11131,12 @@ const docTemplate = `{
                     "format": "date-time",
                     "example": "2023-01-01T00:00:00Z"
                 },
-                "experience": {
-                    "type": "string"
-                },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
-                "qualifications": {
+                "recommendationsComputedAt": {
+                    "description": "Nil until school recommendations are first requested",
                     "type": "string"
                 },
                 "savedSchools": {
@@ -4896,8 +11162,21 @@ const docTemplate = `{
                 }
             }
         },
-        "models.InstitutionProfile": {
-            "description": "Institution or Training Center profile information",
+        "models.ReviewStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "ReviewPending",
+                "ReviewApproved",
+                "ReviewRejected"
+            ]
+        },
+        "models.SavedSearch": {
+            "description": "Saved school or job search",
             "type": "object",
             "properties": {
                 "created_at": {
@@ -4909,127 +11188,110 @@ const docTemplate = `{
                     "format": "date-time",
                     "example": "2023-01-01T00:00:00Z"
                 },
+                "filters": {
+                    "description": "Same filters as the school or job search, e.g. {\"programs\": [\"primary\"], \"lat\": 41.9, \"lng\": 12.5, \"radius_km\": 10}",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "frequency": {
+                    "$ref": "#/definitions/models.SavedSearchFrequency"
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
-                "institutionName": {
-                    "type": "string"
-                },
-                "isVerified": {
-                    "type": "boolean"
+                "kind": {
+                    "$ref": "#/definitions/models.SavedSearchKind"
                 },
-                "jobs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Job"
-                    }
+                "lastMatchedAt": {
+                    "description": "When the last alert about new matches was sent",
+                    "type": "string"
                 },
-                "school": {
-                    "$ref": "#/definitions/models.School"
+                "lastRunAt": {
+                    "description": "When the search was last run for alerts",
+                    "type": "string"
                 },
-                "schoolID": {
-                    "description": "A school can be mapped to only one institution/training center",
-                    "type": "integer"
+                "name": {
+                    "type": "string"
                 },
                 "updated_at": {
                     "type": "string",
                     "example": "2023-01-01T00:00:00Z"
                 },
-                "user": {
-                    "description": "Eager load user details if needed",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.User"
-                        }
-                    ]
-                },
                 "userID": {
-                    "description": "Foreign key to User table",
                     "type": "integer"
-                },
-                "verificationDocs": {
-                    "description": "Path to verification documents",
-                    "type": "string"
                 }
             }
         },
-        "models.Job": {
-            "description": "Job posting information",
+        "models.SavedSearchFrequency": {
+            "type": "string",
+            "enum": [
+                "daily",
+                "weekly",
+                "never"
+            ],
+            "x-enum-comments": {
+                "SavedSearchNever": "Saved for running by hand, without alerts"
+            },
+            "x-enum-varnames": [
+                "SavedSearchDaily",
+                "SavedSearchWeekly",
+                "SavedSearchNever"
+            ]
+        },
+        "models.SavedSearchKind": {
+            "type": "string",
+            "enum": [
+                "schools",
+                "jobs"
+            ],
+            "x-enum-comments": {
+                "SavedSearchJobs": "Filters of the job search",
+                "SavedSearchSchools": "Filters of the public school search"
+            },
+            "x-enum-varnames": [
+                "SavedSearchSchools",
+                "SavedSearchJobs"
+            ]
+        },
+        "models.School": {
+            "description": "School information",
             "type": "object",
             "properties": {
-                "applications": {
+                "accreditations": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.JobApplication"
+                        "$ref": "#/definitions/models.SchoolAccreditation"
                     }
                 },
-                "created_at": {
-                    "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
-                },
-                "deleted_at": {
-                    "type": "string",
-                    "format": "date-time",
-                    "example": "2023-01-01T00:00:00Z"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "employmentType": {
-                    "description": "e.g., Full-time, Part-time",
-                    "type": "string"
-                },
-                "expiresAt": {
+                "address": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "institutionProfile": {
-                    "description": "Eager load institution profile",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.InstitutionProfile"
-                        }
-                    ]
-                },
-                "institutionProfileID": {
-                    "description": "Links to InstitutionProfile",
+                "ageMaxMonths": {
+                    "description": "Oldest accepted age in months",
                     "type": "integer"
                 },
-                "isActive": {
-                    "type": "boolean"
-                },
-                "location": {
-                    "type": "string"
-                },
-                "postedAt": {
-                    "type": "string"
+                "ageMinMonths": {
+                    "description": "Youngest accepted age in months",
+                    "type": "integer"
                 },
-                "salaryRange": {
+                "city": {
                     "type": "string"
                 },
-                "title": {
+                "contactEmail": {
                     "type": "string"
                 },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
-                }
-            }
-        },
-        "models.JobApplication": {
-            "description": "Job application information",
-            "type": "object",
-            "properties": {
-                "appliedAt": {
+                "contactPhone": {
                     "type": "string"
                 },
-                "coverLetter": {
+                "countryCode": {
+                    "description": "For searching by country",
                     "type": "string"
                 },
+                "createdByUserID": {
+                    "description": "Pointer to allow NULL if uploaded by admin initially",
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string",
                     "example": "2023-01-01T00:00:00Z"
@@ -5039,43 +11301,125 @@ const docTemplate = `{
                     "format": "date-time",
                     "example": "2023-01-01T00:00:00Z"
                 },
-                "educator": {
-                    "$ref": "#/definitions/models.EducatorProfile"
+                "distanceKm": {
+                    "description": "Distance from the searched location, only set by location searches",
+                    "type": "number"
                 },
-                "educatorProfileID": {
-                    "description": "Links to EducatorProfile",
-                    "type": "integer"
+                "duplicatesCheckedAt": {
+                    "description": "Last duplicate detection run, cleared when the name, address or website changes",
+                    "type": "string"
+                },
+                "geocodedAt": {
+                    "description": "Last geocoding attempt, set even if the address was not found",
+                    "type": "string"
                 },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
-                "job": {
-                    "$ref": "#/definitions/models.Job"
+                "languages": {
+                    "description": "ISO 639-1 codes of the languages of instruction",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "jobID": {
-                    "type": "integer"
+                "latitude": {
+                    "description": "Nil until the address is geocoded",
+                    "type": "number"
                 },
-                "resumeURL": {
+                "longitude": {
+                    "type": "number"
+                },
+                "media": {
+                    "description": "Logo, cover and gallery; search results include only the logo and cover",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Media"
+                    }
+                },
+                "name": {
                     "type": "string"
                 },
-                "status": {
-                    "description": "e.g., pending, viewed, shortlisted, rejected",
+                "ownershipStatus": {
+                    "description": "Shown as a verified badge once a claim is approved",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SchoolOwnershipStatus"
+                        }
+                    ]
+                },
+                "programs": {
+                    "description": "Montessori profile, edited by the verified institution. Lists are stored as JSON arrays.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SchoolProgram"
+                    }
+                },
+                "schedules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SchoolSchedule"
+                    }
+                },
+                "state": {
                     "type": "string"
                 },
+                "tuitionBand": {
+                    "description": "Empty if unknown",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SchoolTuitionBand"
+                        }
+                    ]
+                },
                 "updated_at": {
                     "type": "string",
                     "example": "2023-01-01T00:00:00Z"
+                },
+                "uploadedByAdmin": {
+                    "description": "True if uploaded by admin batch",
+                    "type": "boolean"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "website": {
+                    "type": "string"
+                },
+                "zipCode": {
+                    "type": "string"
                 }
             }
         },
-        "models.Message": {
-            "description": "Message information",
+        "models.SchoolAccreditation": {
+            "type": "string",
+            "enum": [
+                "ami",
+                "ams",
+                "imc",
+                "msa",
+                "other"
+            ],
+            "x-enum-comments": {
+                "AccreditationAMI": "Association Montessori Internationale",
+                "AccreditationAMS": "American Montessori Society",
+                "AccreditationIMC": "International Montessori Council",
+                "AccreditationMSA": "Montessori Schools Association",
+                "AccreditationOther": "Another Montessori or national accreditation"
+            },
+            "x-enum-varnames": [
+                "AccreditationAMI",
+                "AccreditationAMS",
+                "AccreditationIMC",
+                "AccreditationMSA",
+                "AccreditationOther"
+            ]
+        },
+        "models.SchoolClaim": {
+            "description": "School ownership claim",
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string"
-                },
                 "created_at": {
                     "type": "string",
                     "example": "2023-01-01T00:00:00Z"
@@ -5085,31 +11429,46 @@ const docTemplate = `{
                     "format": "date-time",
                     "example": "2023-01-01T00:00:00Z"
                 },
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SchoolClaimDocument"
+                    }
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
-                "isRead": {
-                    "description": "Index for faster querying of unread messages",
-                    "type": "boolean"
+                "institutionProfile": {
+                    "$ref": "#/definitions/models.InstitutionProfile"
                 },
-                "readAt": {
+                "institutionProfileID": {
+                    "description": "One pending claim per institution",
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "The institution's note to the reviewer",
                     "type": "string"
                 },
-                "recipient": {
-                    "$ref": "#/definitions/models.User"
+                "reviewNotes": {
+                    "description": "Shown to the institution",
+                    "type": "string"
                 },
-                "recipientID": {
+                "reviewedAt": {
+                    "type": "string"
+                },
+                "reviewedBy": {
+                    "description": "Admin who approved or rejected the claim",
                     "type": "integer"
                 },
-                "sender": {
-                    "$ref": "#/definitions/models.User"
+                "school": {
+                    "$ref": "#/definitions/models.School"
                 },
-                "senderID": {
+                "schoolID": {
                     "type": "integer"
                 },
-                "sentAt": {
-                    "type": "string"
+                "status": {
+                    "$ref": "#/definitions/models.SchoolClaimStatus"
                 },
                 "updated_at": {
                     "type": "string",
@@ -5117,10 +11476,17 @@ const docTemplate = `{
                 }
             }
         },
-        "models.ParentProfile": {
-            "description": "Parent profile information",
+        "models.SchoolClaimDocument": {
+            "description": "School claim verification document",
             "type": "object",
             "properties": {
+                "claimID": {
+                    "type": "integer"
+                },
+                "contentType": {
+                    "description": "Detected MIME type",
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string",
                     "example": "2023-01-01T00:00:00Z"
@@ -5130,69 +11496,53 @@ const docTemplate = `{
                     "format": "date-time",
                     "example": "2023-01-01T00:00:00Z"
                 },
+                "fileName": {
+                    "description": "Original file name",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
-                "savedSchools": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.School"
-                    }
+                "size": {
+                    "description": "Bytes",
+                    "type": "integer"
+                },
+                "storageKey": {
+                    "description": "Key in the file storage backend",
+                    "type": "string"
                 },
                 "updated_at": {
                     "type": "string",
                     "example": "2023-01-01T00:00:00Z"
-                },
-                "user": {
-                    "description": "Eager load user details",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.User"
-                        }
-                    ]
-                },
-                "userID": {
-                    "type": "integer"
                 }
             }
         },
-        "models.ReviewStatus": {
+        "models.SchoolClaimStatus": {
             "type": "string",
             "enum": [
                 "pending",
                 "approved",
-                "rejected"
+                "rejected",
+                "withdrawn"
             ],
+            "x-enum-comments": {
+                "SchoolClaimWithdrawn": "Withdrawn by the institution before review"
+            },
             "x-enum-varnames": [
-                "ReviewPending",
-                "ReviewApproved",
-                "ReviewRejected"
+                "SchoolClaimPending",
+                "SchoolClaimApproved",
+                "SchoolClaimRejected",
+                "SchoolClaimWithdrawn"
             ]
         },
-        "models.School": {
-            "description": "School information",
+        "models.SchoolDuplicate": {
+            "description": "Possible duplicate school pair",
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "city": {
-                    "type": "string"
-                },
-                "contactEmail": {
-                    "type": "string"
-                },
-                "contactPhone": {
-                    "type": "string"
-                },
-                "countryCode": {
-                    "description": "For searching by country",
-                    "type": "string"
-                },
-                "createdByUserID": {
-                    "description": "Pointer to allow NULL if uploaded by admin initially",
-                    "type": "integer"
+                "addressSimilarity": {
+                    "description": "Trigram similarity of the normalized addresses, nil if either has none",
+                    "type": "number"
                 },
                 "created_at": {
                     "type": "string",
@@ -5203,35 +11553,130 @@ const docTemplate = `{
                     "format": "date-time",
                     "example": "2023-01-01T00:00:00Z"
                 },
+                "duplicate": {
+                    "$ref": "#/definitions/models.School"
+                },
+                "duplicateID": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
-                "name": {
-                    "type": "string"
+                "nameSimilarity": {
+                    "description": "Trigram similarity of the normalized names",
+                    "type": "number"
                 },
-                "state": {
+                "reviewedAt": {
                     "type": "string"
                 },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
+                "reviewedBy": {
+                    "description": "Admin who merged or dismissed the pair",
+                    "type": "integer"
                 },
-                "uploadedByAdmin": {
-                    "description": "True if uploaded by admin batch",
+                "sameWebsite": {
+                    "description": "Both websites have the same host",
                     "type": "boolean"
                 },
-                "user": {
-                    "$ref": "#/definitions/models.User"
+                "school": {
+                    "$ref": "#/definitions/models.School"
                 },
-                "website": {
-                    "type": "string"
+                "schoolID": {
+                    "type": "integer"
                 },
-                "zipCode": {
-                    "type": "string"
+                "score": {
+                    "description": "Overall similarity, 0 to 1",
+                    "type": "number"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.SchoolDuplicateStatus"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
                 }
             }
         },
+        "models.SchoolDuplicateStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "merged",
+                "dismissed"
+            ],
+            "x-enum-comments": {
+                "SchoolDuplicateDismissed": "Reviewed as different schools"
+            },
+            "x-enum-varnames": [
+                "SchoolDuplicatePending",
+                "SchoolDuplicateMerged",
+                "SchoolDuplicateDismissed"
+            ]
+        },
+        "models.SchoolOwnershipStatus": {
+            "type": "string",
+            "enum": [
+                "unclaimed",
+                "pending",
+                "verified"
+            ],
+            "x-enum-comments": {
+                "SchoolOwnershipPending": "Claimed by at least one institution, awaiting admin review",
+                "SchoolOwnershipVerified": "Managed by the institution whose claim was approved"
+            },
+            "x-enum-varnames": [
+                "SchoolOwnershipUnclaimed",
+                "SchoolOwnershipPending",
+                "SchoolOwnershipVerified"
+            ]
+        },
+        "models.SchoolProgram": {
+            "type": "string",
+            "enum": [
+                "toddler",
+                "primary",
+                "elementary",
+                "adolescent"
+            ],
+            "x-enum-comments": {
+                "ProgramAdolescent": "12 to 18 years",
+                "ProgramElementary": "6 to 12 years",
+                "ProgramPrimary": "3 to 6 years",
+                "ProgramToddler": "18 months to 3 years"
+            },
+            "x-enum-varnames": [
+                "ProgramToddler",
+                "ProgramPrimary",
+                "ProgramElementary",
+                "ProgramAdolescent"
+            ]
+        },
+        "models.SchoolSchedule": {
+            "type": "string",
+            "enum": [
+                "half_day",
+                "full_day"
+            ],
+            "x-enum-varnames": [
+                "ScheduleHalfDay",
+                "ScheduleFullDay"
+            ]
+        },
+        "models.SchoolTuitionBand": {
+            "type": "string",
+            "enum": [
+                "free",
+                "low",
+                "medium",
+                "high"
+            ],
+            "x-enum-varnames": [
+                "TuitionFree",
+                "TuitionLow",
+                "TuitionMedium",
+                "TuitionHigh"
+            ]
+        },
         "models.User": {
             "description": "User information",
             "type": "object",
@@ -5245,6 +11690,14 @@ const docTemplate = `{
                     "format": "date-time",
                     "example": "2023-01-01T00:00:00Z"
                 },
+                "digestFrequency": {
+                    "description": "Notification email batching",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DigestFrequency"
+                        }
+                    ]
+                },
                 "educatorProfile": {
                     "description": "For Educator",
                     "allOf": [
@@ -5256,6 +11709,20 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "emailBounceCount": {
+                    "description": "Soft bounces since the address was last cleared",
+                    "type": "integer"
+                },
+                "emailSuppressed": {
+                    "description": "Email deliverability, updated from provider bounce and complaint webhooks",
+                    "type": "boolean"
+                },
+                "emailSuppressedAt": {
+                    "type": "string"
+                },
+                "emailSuppressionReason": {
+                    "type": "string"
+                },
                 "firstName": {
                     "type": "string"
                 },
@@ -5274,6 +11741,12 @@ const docTemplate = `{
                 "isActive": {
                     "type": "boolean"
                 },
+                "lastDigestSentAt": {
+                    "type": "string"
+                },
+                "lastEmailBounceAt": {
+                    "type": "string"
+                },
                 "lastLogin": {
                     "type": "string"
                 },
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt" // For LogUserAction details
+	"io"
 	"log"
 	"mime/multipart"
 	"mwc_backend/internal/models"
@@ -15,39 +17,47 @@ import (
 
 // AdminHandler handles admin-specific requests.
 type AdminHandler struct {
-	db        *gorm.DB
-	mqService queue.MessageQueueService
+	db         *gorm.DB
+	mqService  queue.MessageQueueService
+	geocoder   *SchoolGeocoder
+	importJobs *ImportJobHandler
 }
 
 // NewAdminHandler creates a new AdminHandler.
-func NewAdminHandler(db *gorm.DB, mq queue.MessageQueueService) *AdminHandler {
-	return &AdminHandler{db: db, mqService: mq}
+func NewAdminHandler(db *gorm.DB, mq queue.MessageQueueService, geocoder *SchoolGeocoder, importJobs *ImportJobHandler) *AdminHandler {
+	return &AdminHandler{db: db, mqService: mq, geocoder: geocoder, importJobs: importJobs}
 }
 
 // SchoolUploadData represents the structure of a school in the JSON file.
 type SchoolUploadData struct {
-	Name         string `json:"name" validate:"required"`
-	Address      string `json:"address"`
-	City         string `json:"city"`
-	State        string `json:"state"`
-	CountryCode  string `json:"country_code" validate:"required"`
-	ZipCode      string `json:"zip_code"`
-	ContactEmail string `json:"contact_email" validate:"email"`
-	ContactPhone string `json:"contact_phone"`
-	Website      string `json:"website" validate:"url"`
+	Name         string `json:"name" validate:"required,max=255"`
+	Address      string `json:"address" validate:"max=255"`
+	City         string `json:"city" validate:"max=100"`
+	State        string `json:"state" validate:"max=100"`
+	CountryCode  string `json:"country_code" validate:"required,len=2,alpha"`
+	ZipCode      string `json:"zip_code" validate:"max=20"`
+	ContactEmail string `json:"contact_email" validate:"omitempty,email"`
+	ContactPhone string `json:"contact_phone" validate:"max=50"`
+	Website      string `json:"website" validate:"omitempty,url"`
+	// Optional coordinates. When omitted the address is geocoded.
+	Latitude  *float64 `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
 }
 
-// BatchUploadSchools handles batch uploading of schools from a JSON file.
+// BatchUploadSchools queues an import of schools from a JSON, CSV or XLSX file.
 // @Summary Batch upload schools
-// @Description Upload multiple schools from a JSON file
+// @Description Uploads schools from a JSON array, a CSV file (comma or semicolon separated) or the first worksheet of an XLSX file. The file is imported in the background: the response is the queued import job, whose progress can be polled at /api/v1/admin/import-jobs/{job_id} and is pushed over the WebSocket as `import_job_progress` messages. CSV and XLSX columns are matched by header (name, address, city, state, country_code, zip_code, contact_email, contact_phone, website, latitude, longitude and common aliases such as "postal code" or "email"); column_mapping maps fields to other headers, e.g. {"name": "School Name"}. Each row is validated on its own: valid rows are created and invalid rows are listed in the job's error report.
 // @Tags admin,schools
 // @Accept multipart/form-data
 // @Produce json
-// @Param schools_file formData file true "JSON file containing school data"
-// @Success 200 {object} map[string]interface{} "Schools uploaded successfully"
-// @Failure 400 {object} map[string]string "Bad request"
+// @Param schools_file formData file true "JSON, CSV or XLSX file containing school data"
+// @Param column_mapping formData string false "JSON object mapping fields to column headers"
+// @Success 202 {object} models.ImportJob "Import job queued"
+// @Failure 400 {object} map[string]string "Bad request or unsupported file type"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 413 {object} map[string]string "File too large"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "File storage unavailable"
 // @Security BearerAuth
 // @Router /api/v1/admin/schools/batch-upload [post]
 func (h *AdminHandler) BatchUploadSchools(c *fiber.Ctx) error {
@@ -61,10 +71,18 @@ func (h *AdminHandler) BatchUploadSchools(c *fiber.Ctx) error {
 		LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_BATCH_UPLOAD_FAIL_FILE", 0, "System", "Failed to get file: "+err.Error(), c)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to get file: " + err.Error()})
 	}
+	if file.Size > h.importJobs.maxFileSize() {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": fmt.Sprintf("File is larger than %d MB", h.importJobs.cfg.ImportMaxSizeMB)})
+	}
 
-	if file.Header.Get("Content-Type") != "application/json" {
+	format, err := schoolImportFormat(file.Filename, file.Header.Get("Content-Type"))
+	if err != nil {
 		LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_BATCH_UPLOAD_FAIL_TYPE", 0, "System", "Invalid file type", c)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid file type. Only JSON is accepted."})
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	mapping, err := parseColumnMapping(c.FormValue("column_mapping"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	openedFile, err := file.Open()
@@ -78,73 +96,29 @@ func (h *AdminHandler) BatchUploadSchools(c *fiber.Ctx) error {
 			log.Printf("Error closing uploaded file: %v", err)
 		}
 	}(openedFile)
-
-	var schoolsData []SchoolUploadData
-	if err := json.NewDecoder(openedFile).Decode(&schoolsData); err != nil {
-		LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_BATCH_UPLOAD_FAIL_PARSE", 0, "System", "Failed to parse JSON: "+err.Error(), c)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to parse JSON file: " + err.Error()})
-	}
-
-	if len(schoolsData) == 0 {
-		LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_BATCH_UPLOAD_FAIL_EMPTY", 0, "System", "No school data in file", c)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "No school data found in the file."})
-	}
-
-	var schoolsToCreate []models.School
-	// var createdCount int // Not needed if using GORM's return
-	var operationErrors []string
-
-	for _, data := range schoolsData {
-		// TODO: Add validation for each SchoolUploadData item
-		// validate := validator.New()
-		// if err := validate.Struct(data); err != nil { ... }
-		school := models.School{
-			Name:            data.Name,
-			Address:         data.Address,
-			City:            data.City,
-			State:           data.State,
-			CountryCode:     data.CountryCode,
-			ZipCode:         data.ZipCode,
-			ContactEmail:    data.ContactEmail,
-			ContactPhone:    data.ContactPhone,
-			Website:         data.Website,
-			UploadedByAdmin: true,
-			CreatedByUserID: &adminUserID, // Link to the admin who uploaded
-		}
-		schoolsToCreate = append(schoolsToCreate, school)
+	data, err := io.ReadAll(openedFile)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read file: " + err.Error()})
 	}
 
-	var createdCount int64 = 0
-	if len(schoolsToCreate) > 0 {
-		result := h.db.Create(&schoolsToCreate) // GORM creates records and populates their IDs
-		if result.Error != nil {
-			operationErrors = append(operationErrors, "Failed to batch insert schools: "+result.Error.Error())
-			log.Printf("Error batch inserting schools: %v", result.Error)
+	job, err := h.importJobs.EnqueueSchoolImport(c.Context(), adminUserID, file.Filename, format, data, mapping)
+	if err != nil {
+		LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_BATCH_UPLOAD_FAIL_QUEUE", 0, "System", err.Error(), c)
+		if errors.Is(err, errImportStorageUnavailable) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "File storage is not available"})
 		}
-		createdCount = result.RowsAffected
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	actionDetail := map[string]interface{}{
-		"file_name":       file.Filename,
-		"attempted_count": len(schoolsData),
-		"created_count":   createdCount,
-		"errors":          operationErrors,
+		"import_job_id": job.ID,
+		"file_name":     file.Filename,
+		"format":        format,
+		"size":          file.Size,
 	}
 	detailJson, _ := json.Marshal(actionDetail)
-	LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_BATCH_UPLOAD_COMPLETE", 0, "System", string(detailJson), c)
-
-	if len(operationErrors) > 0 {
-		return c.Status(fiber.StatusMultiStatus).JSON(fiber.Map{
-			"message":       "Batch upload partially completed with errors.",
-			"created_count": createdCount,
-			"errors":        operationErrors,
-		})
-	}
-
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message":       "Schools batch uploaded successfully.",
-		"created_count": createdCount,
-	})
+	LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_BATCH_UPLOAD_QUEUED", job.ID, "ImportJob", string(detailJson), c)
+	return c.Status(fiber.StatusAccepted).JSON(job)
 }
 
 // UpdateSchool updates an existing school.
@@ -182,8 +156,16 @@ func (h *AdminHandler) UpdateSchool(c *fiber.Ctx) error {
 	if err := c.BodyParser(&updateData); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
 	}
-	// TODO: Validate updateData
+	if fieldErrors := validationErrors(updateData); len(fieldErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": validationErrorsMessage(fieldErrors), "fields": fieldErrors})
+	}
 
+	// Schools without coordinates are left to the scheduled geocoding run unless their address changes
+	addressChanged := school.Address != updateData.Address || school.City != updateData.City || school.State != updateData.State ||
+		school.ZipCode != updateData.ZipCode || school.CountryCode != updateData.CountryCode
+	if addressChanged || school.Name != updateData.Name || school.Website != updateData.Website {
+		school.DuplicatesCheckedAt = nil // Check for duplicates again
+	}
 	school.Name = updateData.Name
 	school.Address = updateData.Address
 	school.City = updateData.City
@@ -193,6 +175,9 @@ func (h *AdminHandler) UpdateSchool(c *fiber.Ctx) error {
 	school.ContactEmail = updateData.ContactEmail
 	school.ContactPhone = updateData.ContactPhone
 	school.Website = updateData.Website
+	if err := h.geocoder.setSchoolLocation(c.Context(), &school, updateData.Latitude, updateData.Longitude, addressChanged); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
 	// school.UploadedByAdmin remains true, or could be updatable
 	// school.CreatedByUserID should ideally not change, or track updater
 
@@ -205,6 +190,50 @@ func (h *AdminHandler) UpdateSchool(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(school)
 }
 
+// UpdateSchoolAttributes updates the Montessori profile of a school.
+// @Summary Update school programs and attributes
+// @Description Replaces the programs, age range, languages of instruction, tuition band, schedules and accreditations of a school; omitted lists are cleared. Without age_min_months and age_max_months the age range is derived from the programs.
+// @Tags admin,schools
+// @Accept json
+// @Produce json
+// @Param id path int true "School ID"
+// @Param attributes body SchoolAttributesRequest true "School attributes"
+// @Success 200 {object} models.School "Updated school"
+// @Failure 400 {object} map[string]string "Invalid school ID or attributes"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "School not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/schools/{id}/attributes [put]
+func (h *AdminHandler) UpdateSchoolAttributes(c *fiber.Ctx) error {
+	adminUserID, _ := c.Locals("user_id").(uint)
+	schoolID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid school ID format"})
+	}
+
+	var school models.School
+	if err := h.db.First(&school, uint(schoolID)).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "School not found"})
+	}
+
+	var req SchoolAttributesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+	}
+	fieldErrors, err := updateSchoolAttributes(h.db, &school, req)
+	if len(fieldErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": validationErrorsMessage(fieldErrors), "fields": fieldErrors})
+	}
+	if err != nil {
+		LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_ATTRIBUTES_FAIL", school.ID, "School", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update school: " + err.Error()})
+	}
+
+	LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_ATTRIBUTES_SUCCESS", school.ID, "School", "School attributes updated", c)
+	return c.Status(fiber.StatusOK).JSON(school)
+}
+
 // GetSchoolsByCountry retrieves schools filtered by country code.
 // @Summary Get schools by country
 // @Description Retrieves a list of schools filtered by country code with pagination
@@ -335,6 +364,94 @@ func (h *AdminHandler) GetAllUsers(c *fiber.Ctx) error {
 	})
 }
 
+// GetEmailSuppressedUsers retrieves users whose email address is suppressed (admin only).
+// @Summary Get users with suppressed email
+// @Description Retrieves users whose address was suppressed after hard bounces, repeated soft bounces or spam complaints, with their recent bounce and complaint events
+// @Tags admin,users
+// @Produce json
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} map[string]interface{} "List of users with suppressed email and pagination metadata"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/users/email-suppressed [get]
+func (h *AdminHandler) GetEmailSuppressedUsers(c *fiber.Ctx) error {
+	var users []models.User
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := h.db.Model(&models.User{}).Where("email_suppressed = ?", true)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to count users: " + err.Error()})
+	}
+	if err := query.Offset(offset).Limit(limit).Order("email_suppressed_at desc").Find(&users).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve users: " + err.Error()})
+	}
+
+	data := make([]fiber.Map, 0, len(users))
+	for _, user := range users {
+		var events []models.EmailEvent
+		h.db.Where("user_id = ?", user.ID).Order("occurred_at desc").Limit(5).Find(&events)
+		data = append(data, fiber.Map{"user": user, "recent_email_events": events})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": data,
+		"meta": fiber.Map{
+			"total":     total,
+			"page":      page,
+			"limit":     limit,
+			"last_page": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// ClearEmailSuppression allows admin to resume sending email to a suppressed user.
+// @Summary Clear email suppression
+// @Description Clears the email suppression and soft bounce count of a user, e.g. after they fixed their mailbox or changed their address
+// @Tags admin,users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "Email suppression cleared"
+// @Failure 400 {object} map[string]string "Invalid user ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/users/{id}/email-suppression [delete]
+func (h *AdminHandler) ClearEmailSuppression(c *fiber.Ctx) error {
+	adminUserID, _ := c.Locals("user_id").(uint)
+	targetUserID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID format"})
+	}
+
+	var user models.User
+	if err := h.db.First(&user, uint(targetUserID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error: " + err.Error()})
+	}
+
+	updates := map[string]interface{}{
+		"email_suppressed":         false,
+		"email_suppressed_at":      nil,
+		"email_suppression_reason": "",
+		"email_bounce_count":       0,
+	}
+	if err := h.db.Model(&user).Updates(updates).Error; err != nil {
+		LogUserAction(h.db, adminUserID, "ADMIN_EMAIL_SUPPRESSION_CLEAR_FAIL", user.ID, "User", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to clear email suppression: " + err.Error()})
+	}
+
+	LogUserAction(h.db, adminUserID, "ADMIN_EMAIL_SUPPRESSION_CLEAR_SUCCESS", user.ID, "User", fmt.Sprintf("Email suppression cleared for %s", user.Email), c)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Email suppression cleared successfully.", "user": user})
+}
+
 // UserStatusUpdateRequest for updating user's active status
 type UserStatusUpdateRequest struct {
 	IsActive bool `json:"is_active"`
@@ -0,0 +1,411 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mwc_backend/config"
+	"mwc_backend/internal/models"
+	"mwc_backend/internal/storage"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AttachmentHandler handles uploads and downloads of message attachments
+type AttachmentHandler struct {
+	db      *gorm.DB
+	cfg     *config.Config
+	store   storage.Storage // Nil when the storage backend could not be initialized
+	scanner storage.Scanner
+}
+
+// NewAttachmentHandler creates a new AttachmentHandler. store may be nil, in which case attachments are unavailable.
+func NewAttachmentHandler(db *gorm.DB, cfg *config.Config, store storage.Storage, scanner storage.Scanner) *AttachmentHandler {
+	if scanner == nil {
+		scanner = storage.NoopScanner{}
+	}
+	return &AttachmentHandler{db: db, cfg: cfg, store: store, scanner: scanner}
+}
+
+// UploadAttachment uploads a file to be sent with a message
+// @Summary Upload a message attachment
+// @Description Uploads a file as multipart form field `file`. The file type is detected from its content and must be in ATTACHMENT_ALLOWED_TYPES; files larger than ATTACHMENT_MAX_SIZE_MB or flagged by the virus scanner are rejected. Send the returned ID in `attachment_ids` of a message. Attachments not sent within 24 hours are deleted.
+// @Tags messages
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "File to attach"
+// @Success 201 {object} map[string]interface{} "Uploaded attachment with a signed download URL"
+// @Failure 400 {object} map[string]string "Missing file"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 415 {object} map[string]string "File type not allowed"
+// @Failure 422 {object} map[string]string "File rejected by the virus scanner"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "File storage unavailable"
+// @Security BearerAuth
+// @Router /api/v1/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+	if h.store == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "File storage is not available"})
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "A file must be uploaded in the 'file' form field"})
+	}
+	maxSize := int64(h.cfg.AttachmentMaxSizeMB) * 1024 * 1024
+	if fileHeader.Size > maxSize {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": fmt.Sprintf("File is larger than %d MB", h.cfg.AttachmentMaxSizeMB)})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot read uploaded file: " + err.Error()})
+	}
+	defer file.Close()
+	// Read at most one byte more than allowed so a wrong size header cannot bypass the limit
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot read uploaded file: " + err.Error()})
+	}
+	if int64(len(data)) > maxSize {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": fmt.Sprintf("File is larger than %d MB", h.cfg.AttachmentMaxSizeMB)})
+	}
+	if len(data) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "File is empty"})
+	}
+
+	fileName := sanitizeFileName(fileHeader.Filename)
+	contentType := detectContentType(data, fileName)
+	if !h.allowedType(contentType) {
+		LogUserAction(h.db, userID, "ATTACHMENT_UPLOAD_FAIL_TYPE", 0, "MessageAttachment", contentType, c)
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{"error": fmt.Sprintf("Files of type %s are not allowed", contentType)})
+	}
+
+	if err := h.scanner.Scan(c.Context(), fileName, bytes.NewReader(data)); err != nil {
+		if errors.Is(err, storage.ErrInfected) {
+			LogUserAction(h.db, userID, "ATTACHMENT_UPLOAD_FAIL_INFECTED", 0, "MessageAttachment", err.Error(), c)
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "File was rejected by the virus scanner"})
+		}
+		log.Printf("Error scanning attachment from user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to scan file"})
+	}
+
+	checksum := sha256.Sum256(data)
+	attachment := models.MessageAttachment{
+		UploaderID:  userID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  fmt.Sprintf("attachments/%s/%s%s", time.Now().UTC().Format("2006/01"), uuid.NewString(), strings.ToLower(filepath.Ext(fileName))),
+		Checksum:    hex.EncodeToString(checksum[:]),
+	}
+	if err := h.store.Put(c.Context(), attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		log.Printf("Error storing attachment from user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to store file"})
+	}
+	if err := h.db.Create(&attachment).Error; err != nil {
+		h.store.Delete(context.Background(), attachment.StorageKey)
+		LogUserAction(h.db, userID, "ATTACHMENT_UPLOAD_FAIL_DB", 0, "MessageAttachment", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save attachment: " + err.Error()})
+	}
+
+	LogUserAction(h.db, userID, "ATTACHMENT_UPLOAD_SUCCESS", attachment.ID, "MessageAttachment", fmt.Sprintf("%s (%s, %d bytes)", fileName, contentType, attachment.Size), c)
+	downloadURL, expiresAt := h.signedURL(attachment.ID, userID)
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"attachment":   attachment,
+		"download_url": downloadURL,
+		"expires_at":   expiresAt,
+	})
+}
+
+// GetAttachmentURL returns a signed, time-limited download URL
+// @Summary Get an attachment download URL
+// @Description Returns a signed download URL for an attachment of a message the current user sent or received. The URL expires after ATTACHMENT_URL_TTL_MINUTES and only works for the current user.
+// @Tags messages
+// @Produce json
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {object} map[string]interface{} "Signed download URL and its expiry"
+// @Failure 400 {object} map[string]string "Invalid attachment ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Attachment not found"
+// @Security BearerAuth
+// @Router /api/v1/attachments/{attachment_id}/url [get]
+func (h *AttachmentHandler) GetAttachmentURL(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+	attachmentID, err := strconv.ParseUint(c.Params("attachment_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid attachment ID format"})
+	}
+
+	attachment, allowed := h.accessibleAttachment(uint(attachmentID), userID)
+	if !allowed {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Attachment not found"})
+	}
+	downloadURL, expiresAt := h.signedURL(attachment.ID, userID)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"attachment_id": attachment.ID,
+		"file_name":     attachment.FileName,
+		"download_url":  downloadURL,
+		"expires_at":    expiresAt,
+	})
+}
+
+// DownloadAttachment streams an attachment to the holder of a signed URL
+// @Summary Download an attachment
+// @Description Downloads an attachment using a signed URL from the upload or URL endpoint. No bearer token is needed, so the URL can be opened directly by a browser; access is re-checked against the conversation on every download.
+// @Tags messages
+// @Produce octet-stream
+// @Param attachment_id path int true "Attachment ID"
+// @Param user_id query int true "User the URL was issued to"
+// @Param expires query int true "Expiry as a Unix timestamp"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file "Attachment content"
+// @Failure 400 {object} map[string]string "Invalid attachment ID"
+// @Failure 403 {object} map[string]string "Invalid or expired signature"
+// @Failure 404 {object} map[string]string "Attachment not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "File storage unavailable"
+// @Router /api/v1/attachments/{attachment_id}/download [get]
+func (h *AttachmentHandler) DownloadAttachment(c *fiber.Ctx) error {
+	if h.store == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "File storage is not available"})
+	}
+	attachmentID, err := strconv.ParseUint(c.Params("attachment_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid attachment ID format"})
+	}
+	userID, err := strconv.ParseUint(c.Query("user_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Invalid download link"})
+	}
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Invalid download link"})
+	}
+	if !h.validSignature(uint(attachmentID), uint(userID), expires, c.Query("signature")) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Invalid download link"})
+	}
+	if time.Now().Unix() > expires {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Download link has expired"})
+	}
+
+	// The user may have lost access since the link was issued
+	attachment, allowed := h.accessibleAttachment(uint(attachmentID), uint(userID))
+	if !allowed {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Attachment not found"})
+	}
+
+	reader, err := h.store.Get(c.Context(), attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Attachment not found"})
+		}
+		log.Printf("Error reading attachment %d from storage: %v", attachment.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read attachment"})
+	}
+
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	c.Set("X-Content-Type-Options", "nosniff")
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	// fasthttp closes the reader once the body has been sent
+	return c.Status(fiber.StatusOK).SendStream(reader, int(attachment.Size))
+}
+
+// DeleteAttachment deletes an attachment that has not been sent yet
+// @Summary Delete an unsent attachment
+// @Description Deletes an uploaded attachment of the current user that has not been sent with a message
+// @Tags messages
+// @Produce json
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {object} map[string]string "Attachment deleted"
+// @Failure 400 {object} map[string]string "Invalid attachment ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Attachment not found or already sent"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/attachments/{attachment_id} [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+	attachmentID, err := strconv.ParseUint(c.Params("attachment_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid attachment ID format"})
+	}
+
+	var attachment models.MessageAttachment
+	if err := h.db.Where("id = ? AND uploader_id = ? AND message_id IS NULL", uint(attachmentID), userID).First(&attachment).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Attachment not found or already sent"})
+	}
+	if err := h.deleteAttachment(c.Context(), attachment); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete attachment: " + err.Error()})
+	}
+
+	LogUserAction(h.db, userID, "ATTACHMENT_DELETE_SUCCESS", attachment.ID, "MessageAttachment", attachment.FileName, c)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Attachment deleted successfully"})
+}
+
+// DeleteUnsentAttachments removes attachments that were uploaded but never sent. It runs as a scheduled task.
+func (h *AttachmentHandler) DeleteUnsentAttachments(ctx context.Context) error {
+	if h.store == nil {
+		return nil
+	}
+	var attachments []models.MessageAttachment
+	if err := h.db.Where("message_id IS NULL AND created_at < ?", time.Now().Add(-unsentAttachmentTTL)).
+		Limit(500).Find(&attachments).Error; err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		if err := h.deleteAttachment(ctx, attachment); err != nil {
+			log.Printf("[Attachments] Failed to delete unsent attachment %d: %v", attachment.ID, err)
+		}
+	}
+	if len(attachments) > 0 {
+		log.Printf("[Attachments] Deleted %d unsent attachment(s)", len(attachments))
+	}
+	return nil
+}
+
+// unsentAttachmentTTL is how long an uploaded attachment is kept without being sent
+const unsentAttachmentTTL = 24 * time.Hour
+
+func (h *AttachmentHandler) deleteAttachment(ctx context.Context, attachment models.MessageAttachment) error {
+	if err := h.store.Delete(ctx, attachment.StorageKey); err != nil {
+		return err
+	}
+	return h.db.Unscoped().Delete(&attachment).Error
+}
+
+// accessibleAttachment loads the attachment if the user may download it: the uploader before it is sent,
+// afterwards the sender and recipient of its message.
+func (h *AttachmentHandler) accessibleAttachment(attachmentID, userID uint) (models.MessageAttachment, bool) {
+	var attachment models.MessageAttachment
+	if err := h.db.First(&attachment, attachmentID).Error; err != nil {
+		return attachment, false
+	}
+	if attachment.MessageID == nil {
+		return attachment, attachment.UploaderID == userID
+	}
+	var count int64
+	h.db.Model(&models.Message{}).
+		Where("id = ? AND (sender_id = ? OR recipient_id = ?)", *attachment.MessageID, userID, userID).
+		Count(&count)
+	return attachment, count > 0
+}
+
+func (h *AttachmentHandler) allowedType(contentType string) bool {
+	for _, allowed := range h.cfg.AttachmentAllowedTypes {
+		if allowed == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// signedURL returns a download URL for the attachment that only works for userID until it expires.
+func (h *AttachmentHandler) signedURL(attachmentID, userID uint) (string, time.Time) {
+	expiresAt := time.Now().Add(time.Duration(h.cfg.AttachmentURLTTLMinutes) * time.Minute).Truncate(time.Second)
+	query := url.Values{}
+	query.Set("user_id", strconv.FormatUint(uint64(userID), 10))
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", h.signature(attachmentID, userID, expiresAt.Unix()))
+	return fmt.Sprintf("%s/api/v1/attachments/%d/download?%s", h.cfg.AppBaseURL, attachmentID, query.Encode()), expiresAt
+}
+
+func (h *AttachmentHandler) signature(attachmentID, userID uint, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(h.cfg.AttachmentURLSecret))
+	fmt.Fprintf(mac, "attachment:%d:%d:%d", attachmentID, userID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (h *AttachmentHandler) validSignature(attachmentID, userID uint, expires int64, signature string) bool {
+	expected := h.signature(attachmentID, userID, expires)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// Office documents are ZIP or OLE containers that sniff as application/zip or application/octet-stream.
+// Only for these is the file extension trusted, and only when the content is such a container.
+var (
+	officeZipTypes = map[string]string{
+		".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	}
+	officeOLETypes = map[string]string{
+		".doc": "application/msword",
+		".xls": "application/vnd.ms-excel",
+		".ppt": "application/vnd.ms-powerpoint",
+	}
+	oleMagic = []byte{0xD0, 0xCF, 0x11, 0xE0}
+)
+
+// detectContentType sniffs the MIME type from the content. The file extension only refines ZIP and OLE
+// content into the matching Office type; any other content keeps its sniffed type whatever its name.
+func detectContentType(data []byte, fileName string) string {
+	sniffed := sniffContentType(data)
+	ext := strings.ToLower(filepath.Ext(fileName))
+	switch {
+	case sniffed == "application/zip" && officeZipTypes[ext] != "":
+		return officeZipTypes[ext]
+	case sniffed == "application/octet-stream" && bytes.HasPrefix(data, oleMagic) && officeOLETypes[ext] != "":
+		return officeOLETypes[ext]
+	}
+	return sniffed
+}
+
+// sniffContentType returns the MIME type detected from the content alone, without parameters.
+func sniffContentType(data []byte) string {
+	sniffed := http.DetectContentType(data)
+	if mediaType, _, err := mime.ParseMediaType(sniffed); err == nil {
+		return mediaType
+	}
+	return sniffed
+}
+
+// sanitizeFileName keeps only the base name and drops characters that are unsafe in headers and file systems.
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`"/\:*?<>|`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." {
+		return "attachment"
+	}
+	if len(name) > 200 {
+		ext := filepath.Ext(name)
+		if len(ext) > 20 {
+			ext = ""
+		}
+		name = truncateUTF8(name, 200-len(ext)) + ext
+	}
+	return name
+}
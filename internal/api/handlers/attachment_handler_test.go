@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeFileNameTruncatesLongNamesOnRuneBoundary(t *testing.T) {
+	name := sanitizeFileName("a" + strings.Repeat("я", 150) + ".pdf")
+	if !utf8.ValidString(name) {
+		t.Fatalf("sanitizeFileName returned invalid UTF-8: %q", name)
+	}
+	if len(name) > 200 {
+		t.Errorf("sanitizeFileName returned %d bytes, want at most 200", len(name))
+	}
+	if !strings.HasSuffix(name, ".pdf") {
+		t.Errorf("sanitizeFileName(%q) dropped the extension", name)
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n1 0 obj\n")
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	zip := []byte("PK\x03\x04\x14\x00\x06\x00")
+	ole := []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1\x00\x00")
+	exe := []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00")
+
+	cases := []struct {
+		name     string
+		data     []byte
+		fileName string
+		want     string
+	}{
+		{"pdf", pdf, "report.pdf", "application/pdf"},
+		{"png named pdf", png, "photo.pdf", "image/png"},
+		{"executable named pdf", exe, "x.pdf", "application/octet-stream"},
+		{"executable named png", exe, "x.png", "application/octet-stream"},
+		{"executable named doc", exe, "x.doc", "application/octet-stream"},
+		{"docx", zip, "letter.DOCX", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{"xlsx", zip, "sheet.xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+		{"pptx", zip, "slides.pptx", "application/vnd.openxmlformats-officedocument.presentationml.presentation"},
+		{"zip named pdf", zip, "x.pdf", "application/zip"},
+		{"zip named doc", zip, "x.doc", "application/zip"},
+		{"doc", ole, "letter.doc", "application/msword"},
+		{"xls", ole, "sheet.xls", "application/vnd.ms-excel"},
+		{"ppt", ole, "slides.ppt", "application/vnd.ms-powerpoint"},
+		{"ole named docx", ole, "x.docx", "application/octet-stream"},
+		{"ole named pdf", ole, "x.pdf", "application/octet-stream"},
+		{"text", []byte("hello"), "notes.txt", "text/plain"},
+	}
+	for _, tc := range cases {
+		if got := detectContentType(tc.data, tc.fileName); got != tc.want {
+			t.Errorf("%s: detectContentType = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
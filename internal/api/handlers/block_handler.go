@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"fmt"
+	"mwc_backend/internal/models"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BlockHandler handles users blocking other users from messaging them
+type BlockHandler struct {
+	db *gorm.DB
+}
+
+// NewBlockHandler creates a new BlockHandler
+func NewBlockHandler(db *gorm.DB) *BlockHandler {
+	return &BlockHandler{db: db}
+}
+
+// usersBlocked reports whether either user blocked the other
+func usersBlocked(db *gorm.DB, userA, userB uint) (bool, error) {
+	var count int64
+	err := db.Model(&models.UserBlock{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userA, userB, userB, userA).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// blockUser blocks the user for the blocker. Blocking a user twice is not an error.
+func blockUser(db *gorm.DB, blockerID, blockedID uint) error {
+	block := models.UserBlock{BlockerID: blockerID, BlockedID: blockedID}
+	return db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "blocker_id"}, {Name: "blocked_id"}}, DoNothing: true}).
+		Create(&block).Error
+}
+
+// BlockUser blocks another user
+// @Summary Block a user
+// @Description Blocks a user. Messages and typing indicators between the two users are rejected in both directions until the block is removed. Existing conversations stay in the inbox.
+// @Tags messages
+// @Produce json
+// @Param user_id path int true "User ID to block"
+// @Success 200 {object} map[string]string "User blocked"
+// @Failure 400 {object} map[string]string "Invalid user ID or blocking yourself"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/users/{user_id}/block [post]
+func (h *BlockHandler) BlockUser(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+	blockedID, err := strconv.ParseUint(c.Params("user_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID format"})
+	}
+	if uint(blockedID) == userID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "You cannot block yourself"})
+	}
+
+	var count int64
+	if err := h.db.Model(&models.User{}).Where("id = ?", uint(blockedID)).Count(&count).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error: " + err.Error()})
+	}
+	if count == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+
+	if err := blockUser(h.db, userID, uint(blockedID)); err != nil {
+		LogUserAction(h.db, userID, "USER_BLOCK_FAIL", uint(blockedID), "User", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to block user: " + err.Error()})
+	}
+
+	LogUserAction(h.db, userID, "USER_BLOCK_SUCCESS", uint(blockedID), "User", fmt.Sprintf("Blocked user %d", blockedID), c)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "User blocked successfully"})
+}
+
+// UnblockUser removes a block
+// @Summary Unblock a user
+// @Description Removes a block so the two users can message each other again
+// @Tags messages
+// @Produce json
+// @Param user_id path int true "User ID to unblock"
+// @Success 200 {object} map[string]string "User unblocked"
+// @Failure 400 {object} map[string]string "Invalid user ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User is not blocked"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/users/{user_id}/block [delete]
+func (h *BlockHandler) UnblockUser(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+	blockedID, err := strconv.ParseUint(c.Params("user_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID format"})
+	}
+
+	result := h.db.Unscoped().Where("blocker_id = ? AND blocked_id = ?", userID, uint(blockedID)).Delete(&models.UserBlock{})
+	if result.Error != nil {
+		LogUserAction(h.db, userID, "USER_UNBLOCK_FAIL", uint(blockedID), "User", result.Error.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to unblock user: " + result.Error.Error()})
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User is not blocked"})
+	}
+
+	LogUserAction(h.db, userID, "USER_UNBLOCK_SUCCESS", uint(blockedID), "User", fmt.Sprintf("Unblocked user %d", blockedID), c)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "User unblocked successfully"})
+}
+
+// GetBlockedUsers lists the users the current user blocked
+// @Summary List blocked users
+// @Description Retrieves the users the current user has blocked, most recent first
+// @Tags messages
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Blocked users"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/users/blocked [get]
+func (h *BlockHandler) GetBlockedUsers(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+
+	var blocks []models.UserBlock
+	if err := h.db.Preload("Blocked", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id, first_name, last_name, role")
+	}).Where("blocker_id = ?", userID).Order("created_at desc").Find(&blocks).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve blocked users: " + err.Error()})
+	}
+
+	users := make([]fiber.Map, 0, len(blocks))
+	for _, block := range blocks {
+		users = append(users, fiber.Map{
+			"id":         block.Blocked.ID,
+			"first_name": block.Blocked.FirstName,
+			"last_name":  block.Blocked.LastName,
+			"role":       block.Blocked.Role,
+			"blocked_at": block.CreatedAt,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": users})
+}
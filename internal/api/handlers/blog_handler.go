@@ -19,11 +19,12 @@ type BlogHandler struct {
 	db        *gorm.DB
 	cfg       *config.Config
 	mqService queue.MessageQueueService
+	notifier  *Notifier
 }
 
 // NewBlogHandler creates a new BlogHandler
-func NewBlogHandler(db *gorm.DB, cfg *config.Config, mqService queue.MessageQueueService) *BlogHandler {
-	return &BlogHandler{db: db, cfg: cfg, mqService: mqService}
+func NewBlogHandler(db *gorm.DB, cfg *config.Config, mqService queue.MessageQueueService, notifier *Notifier) *BlogHandler {
+	return &BlogHandler{db: db, cfg: cfg, mqService: mqService, notifier: notifier}
 }
 
 // CreateBlogPostRequest is the request body for creating a blog post
@@ -146,6 +147,9 @@ func (h *BlogHandler) CreateBlogPost(c *fiber.Ctx) error {
 	}
 
 	LogUserAction(h.db, userID, "BLOG_POST_CREATED", blogPost.ID, "BlogPost", fmt.Sprintf("Blog post created: %s", req.Title), c)
+	if blogPost.IsPublished && blogPost.IsFeatured {
+		h.publishFeaturedPost(blogPost)
+	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"message": "Blog post created successfully",
@@ -445,6 +449,7 @@ func (h *BlogHandler) UpdateBlogPost(c *fiber.Ctx) error {
 	blogPost.LocalizedTitles = localizedTitles
 	blogPost.LocalizedContents = localizedContents
 	blogPost.LocalizedExcerpts = localizedExcerpts
+	wasFeatured := blogPost.IsPublished && blogPost.IsFeatured
 	blogPost.IsFeatured = req.IsFeatured
 
 	// Update published status if changed
@@ -462,6 +467,9 @@ func (h *BlogHandler) UpdateBlogPost(c *fiber.Ctx) error {
 	}
 
 	LogUserAction(h.db, userID, "BLOG_POST_UPDATED", blogPost.ID, "BlogPost", fmt.Sprintf("Blog post updated: %s", req.Title), c)
+	if !wasFeatured && blogPost.IsPublished && blogPost.IsFeatured {
+		h.publishFeaturedPost(blogPost)
+	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Blog post updated successfully",
@@ -662,6 +670,18 @@ func (h *BlogHandler) GetBlogTags(c *fiber.Ctx) error {
 	})
 }
 
+// publishFeaturedPost announces a newly featured post to the WebSocket subscribers of the featured feed
+func (h *BlogHandler) publishFeaturedPost(blogPost models.BlogPost) {
+	h.notifier.Publish(FeaturedBlogTopic, "featured_blog_post", map[string]interface{}{
+		"id":           blogPost.ID,
+		"title":        blogPost.Title,
+		"slug":         blogPost.Slug,
+		"excerpt":      blogPost.Excerpt,
+		"category":     blogPost.Category,
+		"published_at": blogPost.PublishedAt,
+	})
+}
+
 // Helper function to generate an excerpt from content
 func generateExcerpt(content string, maxLength int) string {
 	// Strip HTML tags (simplified approach)
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"mwc_backend/internal/models"
 	"strconv"
+	"unicode/utf8"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -27,8 +30,10 @@ func LogUserAction(db *gorm.DB, actorUserID uint, actionType string, targetID ui
 		TargetID:   targetID,
 		TargetType: targetType,
 		Details:    details,
-		IPAddress:  c.IP(),
-		UserAgent:  string(c.Request().Header.UserAgent()),
+	}
+	if c != nil { // System actions run outside of a request
+		logEntry.IPAddress = c.IP()
+		logEntry.UserAgent = string(c.Request().Header.UserAgent())
 	}
 
 	if err := db.Create(&logEntry).Error; err != nil {
@@ -37,53 +42,111 @@ func LogUserAction(db *gorm.DB, actorUserID uint, actionType string, targetID ui
 }
 
 // GetPublicSchools allows anyone to search for schools.
+// @Summary Search schools
+// @Description Public school search. `q` matches name, city, state and address as word prefixes and tolerates typos in the name and city; results are ranked by relevance, or by name with sort=name or without a query. With lat and lng results are sorted by distance, include DistanceKm and can be limited with radius_km; bbox limits them to a map area. Schools are located by geocoding their address. Schools can be filtered by their Montessori attributes; each of program, language, tuition, schedule and accreditation takes comma-separated values and matches schools with any of them, and age_months matches schools whose age range includes the child's age. The response includes facets with the number of matching schools per country, state and program; each facet ignores its own filter so other values can be offered.
+// @Tags schools
+// @Produce json
+// @Param q query string false "Search text (name is accepted as an alias)"
+// @Param city query string false "Filter by city"
+// @Param state query string false "Filter by state"
+// @Param country_code query string false "Filter by country code"
+// @Param lat query number false "Latitude of the location to search around"
+// @Param lng query number false "Longitude of the location to search around"
+// @Param radius_km query number false "Only schools within this distance of lat/lng, at most 500"
+// @Param bbox query string false "Only schools within the map area west,south,east,north"
+// @Param sort query string false "distance, relevance or name; defaults to distance with lat/lng and relevance with q"
+// @Param program query string false "Programs: toddler, primary, elementary, adolescent"
+// @Param age_months query int false "Age of the child in months"
+// @Param language query string false "Languages of instruction as ISO 639-1 codes, e.g. en,it"
+// @Param tuition query string false "Tuition bands: free, low, medium, high"
+// @Param schedule query string false "Schedules: half_day, full_day"
+// @Param accreditation query string false "Accreditations: ami, ams, imc, msa, other"
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} map[string]interface{} "Matching schools with pagination metadata and facets"
+// @Failure 400 {object} map[string]string "Invalid location or attribute filters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/schools/public [get]
 func GetPublicSchools(db *gorm.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		query := db.Model(&models.School{})
-
-		// Example filters (can be expanded)
-		if name := c.Query("name"); name != "" {
-			query = query.Where("LOWER(name) LIKE LOWER(?)", "%"+name+"%")
-		}
-		if city := c.Query("city"); city != "" {
-			query = query.Where("LOWER(city) LIKE LOWER(?)", "%"+city+"%")
+		params, err := schoolSearchParamsFromQuery(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
-		if countryCode := c.Query("country_code"); countryCode != "" {
-			query = query.Where("country_code = ?", countryCode)
-		}
-		// Add pagination
-		page, _ := strconv.Atoi(c.Query("page", "1"))
-		limit, _ := strconv.Atoi(c.Query("limit", "10"))
-		offset := (page - 1) * limit
-		query = query.Offset(offset).Limit(limit)
-
-		var schools []models.School
-		if err := query.Find(&schools).Error; err != nil {
+		result, err := searchSchools(db, params)
+		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error while fetching schools: " + err.Error()})
 		}
 
-		var total int64
-		db.Model(&models.School{}).Count(&total) // Get total count for pagination metadata
-
+		page, limit := params.Page, params.Limit
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{
-			"data": schools,
+			"data":   result.Schools,
+			"facets": result.Facets,
 			"meta": fiber.Map{
-				"total":     total,
+				"total":     result.Total,
 				"page":      page,
 				"limit":     limit,
-				"last_page": (total + int64(limit) - 1) / int64(limit),
+				"last_page": (result.Total + int64(limit) - 1) / int64(limit),
 			},
 		})
 	}
 }
 
-// truncateMessage shortens a message string to a max length, adding ellipsis.
+// GetPublicSchool allows anyone to view a school.
+// @Summary Get a school
+// @Description Retrieves a school by ID with its logo, cover and gallery images. The ID of a school that was merged into another one redirects to the surviving school.
+// @Tags schools
+// @Produce json
+// @Param school_id path int true "School ID"
+// @Success 200 {object} models.School "School"
+// @Success 301 "The school was merged, Location names the surviving school"
+// @Failure 400 {object} map[string]string "Invalid school ID"
+// @Failure 404 {object} map[string]string "School not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/schools/{school_id} [get]
+func GetPublicSchool(db *gorm.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		schoolID, err := strconv.ParseUint(c.Params("school_id"), 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid school ID format"})
+		}
+		if targetID := resolveSchoolID(db, uint(schoolID)); targetID != uint(schoolID) {
+			return c.Redirect(fmt.Sprintf("/api/v1/schools/%d", targetID), fiber.StatusMovedPermanently)
+		}
+
+		var school models.School
+		err = db.Preload("Media", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("kind, position, id")
+		}).First(&school, uint(schoolID)).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "School not found"})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error: " + err.Error()})
+		}
+		return c.Status(fiber.StatusOK).JSON(school)
+	}
+}
+
+// truncateMessage shortens a message string to at most maxLength bytes, adding ellipsis.
+// The result is stored in the database, so it never cuts a multibyte character in half.
 func truncateMessage(msg string, maxLength int) string {
 	if len(msg) <= maxLength {
 		return msg
 	}
 	if maxLength <= 3 {
-		return msg[:maxLength] // Not enough space for ellipsis
+		return truncateUTF8(msg, maxLength) // Not enough space for ellipsis
+	}
+	return truncateUTF8(msg, maxLength-3) + "..."
+}
+
+// truncateUTF8 returns the longest prefix of s that fits in maxBytes and ends on a rune boundary.
+func truncateUTF8(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	for maxBytes > 0 && !utf8.RuneStart(s[maxBytes]) {
+		maxBytes--
 	}
-	return msg[:maxLength-3] + "..."
+	return s[:maxBytes]
 }
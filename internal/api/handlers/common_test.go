@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateMessageKeepsValidUTF8(t *testing.T) {
+	cases := []struct {
+		msg       string
+		maxLength int
+	}{
+		{strings.Repeat("a", 196) + "éééé", 200},
+		{strings.Repeat("б", 150), 200},
+		{strings.Repeat("a", 98) + "😀😀", 100},
+		{"😀😀", 3},
+		{"😀😀", 2},
+	}
+	for _, tc := range cases {
+		got := truncateMessage(tc.msg, tc.maxLength)
+		if !utf8.ValidString(got) {
+			t.Errorf("truncateMessage(%q, %d) = %q, not valid UTF-8", tc.msg, tc.maxLength, got)
+		}
+		if len(got) > tc.maxLength {
+			t.Errorf("truncateMessage(%q, %d) is %d bytes long", tc.msg, tc.maxLength, len(got))
+		}
+	}
+}
+
+func TestTruncateMessageLeavesShortMessages(t *testing.T) {
+	if got := truncateMessage("héllo", 200); got != "héllo" {
+		t.Errorf("truncateMessage = %q, want the message unchanged", got)
+	}
+	if got := truncateMessage(strings.Repeat("a", 10), 8); got != "aaaaa..." {
+		t.Errorf("truncateMessage = %q, want %q", got, "aaaaa...")
+	}
+}
@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"fmt"
+	"mwc_backend/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ConversationHandler handles the message inbox grouped by conversation
+type ConversationHandler struct {
+	db       *gorm.DB
+	notifier *Notifier
+}
+
+// NewConversationHandler creates a new ConversationHandler
+func NewConversationHandler(db *gorm.DB, notifier *Notifier) *ConversationHandler {
+	return &ConversationHandler{db: db, notifier: notifier}
+}
+
+// ArchiveConversationRequest is the request body for archiving or unarchiving a conversation
+type ArchiveConversationRequest struct {
+	Archived bool `json:"archived"`
+}
+
+// MuteConversationRequest is the request body for muting or unmuting a conversation
+type MuteConversationRequest struct {
+	Muted bool `json:"muted"`
+}
+
+// GetConversations lists the current user's conversations
+// @Summary List conversations
+// @Description Retrieves the current user's conversations, most recent activity first, with the other participants, the last message and the number of unread messages
+// @Tags messages
+// @Produce json
+// @Param archived query boolean false "List archived conversations instead of the inbox" default(false)
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(20)
+// @Success 200 {object} map[string]interface{} "List of conversations with pagination metadata"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/conversations [get]
+func (h *ConversationHandler) GetConversations(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	query := h.db.Model(&models.ConversationParticipant{}).
+		Where("user_id = ? AND is_archived = ?", userID, c.QueryBool("archived", false))
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to count conversations: " + err.Error()})
+	}
+
+	var memberships []models.ConversationParticipant
+	if err := query.
+		Joins("JOIN conversations ON conversations.id = conversation_participants.conversation_id AND conversations.deleted_at IS NULL").
+		Order("conversations.last_message_at DESC NULLS LAST").
+		Offset(offset).Limit(limit).
+		Find(&memberships).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve conversations: " + err.Error()})
+	}
+
+	conversationIDs := make([]uint, 0, len(memberships))
+	for _, membership := range memberships {
+		conversationIDs = append(conversationIDs, membership.ConversationID)
+	}
+
+	conversations := make(map[uint]models.Conversation, len(conversationIDs))
+	unreadCounts := make(map[uint]int64, len(conversationIDs))
+	if len(conversationIDs) > 0 {
+		var loaded []models.Conversation
+		if err := h.db.Preload("Participants.User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, first_name, last_name, role")
+		}).Where("id IN ?", conversationIDs).Find(&loaded).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve conversations: " + err.Error()})
+		}
+		for _, conversation := range loaded {
+			conversations[conversation.ID] = conversation
+		}
+
+		var counts []struct {
+			ConversationID uint
+			Count          int64
+		}
+		if err := h.db.Model(&models.Message{}).
+			Select("conversation_id, COUNT(*) AS count").
+			Where("conversation_id IN ? AND recipient_id = ? AND is_read = ?", conversationIDs, userID, false).
+			Group("conversation_id").
+			Scan(&counts).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to count unread messages: " + err.Error()})
+		}
+		for _, count := range counts {
+			unreadCounts[count.ConversationID] = count.Count
+		}
+	}
+
+	data := make([]fiber.Map, 0, len(memberships))
+	for _, membership := range memberships {
+		conversation, ok := conversations[membership.ConversationID]
+		if !ok {
+			continue
+		}
+		data = append(data, conversationResponse(conversation, membership, userID, unreadCounts[conversation.ID]))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": data,
+		"meta": fiber.Map{
+			"total":     total,
+			"page":      page,
+			"limit":     limit,
+			"last_page": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetConversationMessages pages through the messages of a conversation
+// @Summary Get conversation messages
+// @Description Retrieves the messages of a conversation, newest first. Pass the returned next_cursor as cursor to load older messages.
+// @Tags messages
+// @Produce json
+// @Param conversation_id path int true "Conversation ID"
+// @Param cursor query int false "Return messages older than this message ID"
+// @Param limit query int false "Number of messages to return" default(30)
+// @Success 200 {object} map[string]interface{} "Messages with the cursor for the next page"
+// @Failure 400 {object} map[string]string "Invalid conversation ID or cursor"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Conversation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/conversations/{conversation_id}/messages [get]
+func (h *ConversationHandler) GetConversationMessages(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+	membership, status, errMsg := h.membership(c.Params("conversation_id"), userID)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{"error": errMsg})
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit", "30"))
+	if limit < 1 || limit > 100 {
+		limit = 30
+	}
+
+	query := h.db.Where("conversation_id = ?", membership.ConversationID)
+	if cursorParam := c.Query("cursor"); cursorParam != "" {
+		cursor, err := strconv.ParseUint(cursorParam, 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid cursor"})
+		}
+		query = query.Where("id < ?", uint(cursor))
+	}
+
+	var messages []models.Message
+	// Fetch one extra message to know whether there is an older page
+	if err := query.Preload("Attachments").Order("id DESC").Limit(limit + 1).Find(&messages).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve messages: " + err.Error()})
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+	var nextCursor *uint
+	if hasMore {
+		nextCursor = &messages[len(messages)-1].ID
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data":        messages,
+		"next_cursor": nextCursor,
+		"has_more":    hasMore,
+	})
+}
+
+// MarkConversationAsRead marks every message of a conversation addressed to the user as read
+// @Summary Mark conversation as read
+// @Description Marks all unread messages of a conversation addressed to the current user as read and sends read receipts to the senders
+// @Tags messages
+// @Produce json
+// @Param conversation_id path int true "Conversation ID"
+// @Success 200 {object} map[string]interface{} "Number of messages marked as read"
+// @Failure 400 {object} map[string]string "Invalid conversation ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Conversation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/conversations/{conversation_id}/read [post]
+func (h *ConversationHandler) MarkConversationAsRead(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+	membership, status, errMsg := h.membership(c.Params("conversation_id"), userID)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{"error": errMsg})
+	}
+
+	var unread []models.Message
+	if err := h.db.Where("conversation_id = ? AND recipient_id = ? AND is_read = ?", membership.ConversationID, userID, false).
+		Find(&unread).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve unread messages: " + err.Error()})
+	}
+
+	now := time.Now()
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if len(unread) > 0 {
+			ids := make([]uint, 0, len(unread))
+			for _, message := range unread {
+				ids = append(ids, message.ID)
+			}
+			if err := tx.Model(&models.Message{}).Where("id IN ?", ids).
+				Updates(map[string]interface{}{"is_read": true, "read_at": now}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Message{}).Where("id IN ? AND delivered_at IS NULL", ids).
+				Update("delivered_at", now).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&membership).Update("last_read_at", now).Error
+	})
+	if err != nil {
+		LogUserAction(h.db, userID, "CONVERSATION_READ_FAIL_DB", membership.ConversationID, "Conversation", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to mark conversation as read: " + err.Error()})
+	}
+
+	for _, message := range unread {
+		message.IsRead = true
+		message.ReadAt = &now
+		if message.DeliveredAt == nil {
+			message.DeliveredAt = &now
+		}
+		h.notifier.pushMessageReceipt(message, "read")
+	}
+
+	LogUserAction(h.db, userID, "CONVERSATION_READ_SUCCESS", membership.ConversationID, "Conversation", fmt.Sprintf("%d message(s) marked as read", len(unread)), c)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": fmt.Sprintf("%d message(s) marked as read", len(unread)),
+		"updated": len(unread),
+	})
+}
+
+// ArchiveConversation archives or unarchives a conversation for the current user
+// @Summary Archive or unarchive a conversation
+// @Description Moves a conversation out of or back into the current user's inbox. An archived conversation returns to the inbox when a new message arrives.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param conversation_id path int true "Conversation ID"
+// @Param request body ArchiveConversationRequest true "Archived state"
+// @Success 200 {object} map[string]interface{} "Conversation updated"
+// @Failure 400 {object} map[string]string "Invalid conversation ID or request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Conversation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/conversations/{conversation_id}/archive [put]
+func (h *ConversationHandler) ArchiveConversation(c *fiber.Ctx) error {
+	var req ArchiveConversationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+	}
+	return h.updateMembership(c, "is_archived", req.Archived, "CONVERSATION_ARCHIVE")
+}
+
+// MuteConversation mutes or unmutes a conversation for the current user
+// @Summary Mute or unmute a conversation
+// @Description Muted conversations still receive messages but send no in-app, WebSocket or email notifications to the current user
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param conversation_id path int true "Conversation ID"
+// @Param request body MuteConversationRequest true "Muted state"
+// @Success 200 {object} map[string]interface{} "Conversation updated"
+// @Failure 400 {object} map[string]string "Invalid conversation ID or request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Conversation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/conversations/{conversation_id}/mute [put]
+func (h *ConversationHandler) MuteConversation(c *fiber.Ctx) error {
+	var req MuteConversationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+	}
+	return h.updateMembership(c, "is_muted", req.Muted, "CONVERSATION_MUTE")
+}
+
+// updateMembership sets a boolean flag on the current user's participation in the conversation
+func (h *ConversationHandler) updateMembership(c *fiber.Ctx, column string, value bool, action string) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+	membership, status, errMsg := h.membership(c.Params("conversation_id"), userID)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{"error": errMsg})
+	}
+
+	if err := h.db.Model(&membership).Update(column, value).Error; err != nil {
+		LogUserAction(h.db, userID, action+"_FAIL_DB", membership.ConversationID, "Conversation", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update conversation: " + err.Error()})
+	}
+	if column == "is_archived" {
+		membership.IsArchived = value
+	} else {
+		membership.IsMuted = value
+	}
+
+	LogUserAction(h.db, userID, action+"_SUCCESS", membership.ConversationID, "Conversation", fmt.Sprintf("%s set to %t", column, value), c)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":         "Conversation updated successfully",
+		"conversation_id": membership.ConversationID,
+		"is_archived":     membership.IsArchived,
+		"is_muted":        membership.IsMuted,
+	})
+}
+
+// membership loads the user's participation in the conversation. On failure it returns the HTTP status and error message.
+func (h *ConversationHandler) membership(conversationIDParam string, userID uint) (models.ConversationParticipant, int, string) {
+	var membership models.ConversationParticipant
+	conversationID, err := strconv.ParseUint(conversationIDParam, 10, 32)
+	if err != nil {
+		return membership, fiber.StatusBadRequest, "Invalid conversation ID format"
+	}
+	if err := h.db.Where("conversation_id = ? AND user_id = ?", uint(conversationID), userID).First(&membership).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return membership, fiber.StatusNotFound, "Conversation not found"
+		}
+		return membership, fiber.StatusInternalServerError, "Database error: " + err.Error()
+	}
+	return membership, 0, ""
+}
+
+// conversationResponse builds the inbox entry of a conversation as seen by userID
+func conversationResponse(conversation models.Conversation, membership models.ConversationParticipant, userID uint, unreadCount int64) fiber.Map {
+	participants := make([]fiber.Map, 0, len(conversation.Participants))
+	for _, participant := range conversation.Participants {
+		if participant.UserID == userID {
+			continue
+		}
+		participants = append(participants, fiber.Map{
+			"id":         participant.User.ID,
+			"first_name": participant.User.FirstName,
+			"last_name":  participant.User.LastName,
+			"role":       participant.User.Role,
+		})
+	}
+
+	return fiber.Map{
+		"id":                     conversation.ID,
+		"participants":           participants,
+		"last_message_id":        conversation.LastMessageID,
+		"last_message_at":        conversation.LastMessageAt,
+		"last_message_preview":   conversation.LastMessagePreview,
+		"last_message_sender_id": conversation.LastMessageSenderID,
+		"unread_count":           unreadCount,
+		"last_read_at":           membership.LastReadAt,
+		"is_archived":            membership.IsArchived,
+		"is_muted":               membership.IsMuted,
+	}
+}
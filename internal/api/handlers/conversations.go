@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"fmt"
+	"mwc_backend/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// directConversationKey identifies the one-to-one conversation of two users regardless of who writes first
+func directConversationKey(userA, userB uint) string {
+	if userA > userB {
+		userA, userB = userB, userA
+	}
+	return fmt.Sprintf("%d:%d", userA, userB)
+}
+
+// storeDirectMessage stores a message in the one-to-one conversation of sender and recipient, creating the
+// conversation on the first message. The conversation's last-message metadata is updated and it is moved
+// back to the inbox of participants who archived it. The sender's unsent attachments are linked to the message.
+func storeDirectMessage(db *gorm.DB, senderID, recipientID uint, content string, attachmentIDs []uint) (models.Message, error) {
+	var message models.Message
+	err := db.Transaction(func(tx *gorm.DB) error {
+		conversation := models.Conversation{DirectKey: directConversationKey(senderID, recipientID)}
+		if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "direct_key"}}, DoNothing: true}).Create(&conversation).Error; err != nil {
+			return err
+		}
+		if conversation.ID == 0 { // Created concurrently or earlier
+			if err := tx.Where("direct_key = ?", conversation.DirectKey).First(&conversation).Error; err != nil {
+				return err
+			}
+		}
+
+		participants := []models.ConversationParticipant{
+			{ConversationID: conversation.ID, UserID: senderID},
+			{ConversationID: conversation.ID, UserID: recipientID},
+		}
+		if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "conversation_id"}, {Name: "user_id"}}, DoNothing: true}).Create(&participants).Error; err != nil {
+			return err
+		}
+
+		message = models.Message{
+			ConversationID: &conversation.ID,
+			SenderID:       senderID,
+			RecipientID:    recipientID,
+			Content:        content,
+			SentAt:         time.Now(),
+			IsRead:         false,
+		}
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+		if len(attachmentIDs) > 0 {
+			result := tx.Model(&models.MessageAttachment{}).
+				Where("id IN ? AND uploader_id = ? AND message_id IS NULL", attachmentIDs, senderID).
+				Update("message_id", message.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected != int64(len(attachmentIDs)) {
+				return ErrInvalidAttachment
+			}
+			if err := tx.Where("message_id = ?", message.ID).Order("id").Find(&message.Attachments).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.Conversation{}).Where("id = ?", conversation.ID).Updates(map[string]interface{}{
+			"last_message_id":        message.ID,
+			"last_message_at":        message.SentAt,
+			"last_message_preview":   messagePreview(content, message.Attachments),
+			"last_message_sender_id": senderID,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ConversationParticipant{}).
+			Where("conversation_id = ? AND is_archived = ?", conversation.ID, true).
+			Update("is_archived", false).Error; err != nil {
+			return err
+		}
+		// The sender has seen everything up to their own message
+		return tx.Model(&models.ConversationParticipant{}).
+			Where("conversation_id = ? AND user_id = ?", conversation.ID, senderID).
+			Update("last_read_at", message.SentAt).Error
+	})
+	return message, err
+}
+
+// conversationMuted reports whether the user muted the conversation. Messages without a conversation are never muted.
+func conversationMuted(db *gorm.DB, conversationID *uint, userID uint) bool {
+	if conversationID == nil {
+		return false
+	}
+	var count int64
+	db.Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ? AND is_muted = ?", *conversationID, userID, true).
+		Count(&count)
+	return count > 0
+}
+
+// messagePreview is the inbox preview of a message. Messages with only attachments show the file names.
+func messagePreview(content string, attachments []models.MessageAttachment) string {
+	if strings.TrimSpace(content) != "" || len(attachments) == 0 {
+		return truncateMessage(content, 200)
+	}
+	names := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		names = append(names, attachment.FileName)
+	}
+	return truncateMessage("📎 "+strings.Join(names, ", "), 200)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"fmt"
+	"mwc_backend/internal/models"
+	"os"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB connects to the database in TEST_DATABASE_URL, migrates it and returns a transaction rolled back
+// when the test ends. Tests needing a database are skipped when the variable is not set.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connecting to the test database: %v", err)
+	}
+	if err := models.AutoMigrate(db); err != nil {
+		t.Fatalf("migrating the test database: %v", err)
+	}
+	tx := db.Begin()
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+// dryRunDB returns a database that builds statements without running them, calling capture with each
+// statement built by a create
+func dryRunDB(t *testing.T, capture func(sql string, vars []interface{})) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("opening a dry-run database: %v", err)
+	}
+	err = db.Callback().Create().After("gorm:create").Register("test:capture", func(db *gorm.DB) {
+		capture(db.Statement.SQL.String(), db.Statement.Vars)
+	})
+	if err != nil {
+		t.Fatalf("registering the capture callback: %v", err)
+	}
+	return db
+}
+
+// createTestUser stores a user with a unique email
+func createTestUser(t *testing.T, db *gorm.DB, role models.UserRole) models.User {
+	t.Helper()
+	user := models.User{
+		Email:        fmt.Sprintf("test-%d@example.com", time.Now().UnixNano()),
+		PasswordHash: "x",
+		Role:         role,
+		IsActive:     true,
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("creating a test user: %v", err)
+	}
+	return user
+}
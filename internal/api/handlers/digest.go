@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"mwc_backend/config"
+	"mwc_backend/internal/email"
+	"mwc_backend/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// digestSchedulingSlack lets a digest go out on the tick that lands just before its period has fully elapsed,
+// so an hourly digest is not pushed back by a whole tick because of scheduling jitter.
+const digestSchedulingSlack = 5 * time.Minute
+
+// eventReminderWindow is how long before an event starts the reminder is sent.
+const eventReminderWindow = 24 * time.Hour
+
+// notificationDelivery describes what happened to a notification email.
+type notificationDelivery int
+
+const (
+	deliverySkipped notificationDelivery = iota // The user opted out of the category
+	deliverySent                                // The email was sent immediately
+	deliveryQueued                              // The email was queued for the user's next digest
+)
+
+// deliverNotificationEmail sends a category email right away or queues it for the user's digest,
+// depending on the user's digest frequency. item describes the notification in the digest; its
+// user and category are filled in and its title defaults to the subject.
+func deliverNotificationEmail(db *gorm.DB, emailService email.EmailService, cfg *config.Config, user models.User, category models.NotificationCategory, subject, htmlBody string, item models.DigestItem) (notificationDelivery, error) {
+	if user.DigestFrequency == "" || user.DigestFrequency == models.DigestImmediate {
+		sent, err := sendNotificationEmail(db, emailService, cfg, user, category, subject, htmlBody)
+		if err != nil || !sent {
+			return deliverySkipped, err
+		}
+		return deliverySent, nil
+	}
+
+	if user.EmailSuppressed {
+		return deliverySkipped, nil
+	}
+	if !NotificationEnabled(db, user.ID, category, models.NotificationChannelEmail) {
+		log.Printf("User %d has opted out of %s emails, not queueing '%s' for digest", user.ID, category, subject)
+		return deliverySkipped, nil
+	}
+
+	item.UserID = user.ID
+	item.Category = category
+	if item.Title == "" {
+		item.Title = subject
+	}
+	if err := db.Create(&item).Error; err != nil {
+		return deliverySkipped, fmt.Errorf("failed to queue digest item: %w", err)
+	}
+	return deliveryQueued, nil
+}
+
+// DigestHandler sends batched notification digests and event reminders. Its methods are run by the queue scheduler.
+type DigestHandler struct {
+	db           *gorm.DB
+	emailService email.EmailService
+	cfg          *config.Config
+}
+
+// NewDigestHandler creates a new DigestHandler
+func NewDigestHandler(db *gorm.DB, emailService email.EmailService, cfg *config.Config) *DigestHandler {
+	return &DigestHandler{db: db, emailService: emailService, cfg: cfg}
+}
+
+// digestPeriod returns how often a digest is sent for the given frequency.
+func digestPeriod(frequency models.DigestFrequency) time.Duration {
+	switch frequency {
+	case models.DigestHourly:
+		return time.Hour
+	case models.DigestDaily:
+		return 24 * time.Hour
+	case models.DigestWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// SendDueDigests sends a digest to every user with pending digest items whose digest period has elapsed.
+func (h *DigestHandler) SendDueDigests(ctx context.Context) error {
+	var users []models.User
+	err := h.db.WithContext(ctx).
+		Where("digest_frequency IN ?", []models.DigestFrequency{models.DigestHourly, models.DigestDaily, models.DigestWeekly}).
+		Where("email_suppressed = ?", false).
+		Where("id IN (?)", h.db.Model(&models.DigestItem{}).Select("user_id").Where("sent_at IS NULL")).
+		Find(&users).Error
+	if err != nil {
+		return fmt.Errorf("failed to find users with pending digest items: %w", err)
+	}
+
+	now := time.Now()
+	sent := 0
+	for _, user := range users {
+		if user.LastDigestSentAt != nil && now.Sub(*user.LastDigestSentAt) < digestPeriod(user.DigestFrequency)-digestSchedulingSlack {
+			continue
+		}
+		ok, err := h.sendDigest(ctx, user, now)
+		if err != nil {
+			log.Printf("Digest: failed to send digest to user %d: %v", user.ID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	log.Printf("Digest: sent %d digest(s) to %d candidate user(s)", sent, len(users))
+	return nil
+}
+
+// sendDigest sends the user's pending digest items in a single email and marks them as sent.
+// Items about messages that have been read in the meantime are dropped.
+func (h *DigestHandler) sendDigest(ctx context.Context, user models.User, now time.Time) (bool, error) {
+	db := h.db.WithContext(ctx)
+
+	var items []models.DigestItem
+	if err := db.Where("user_id = ? AND sent_at IS NULL", user.ID).Order("created_at ASC").Find(&items).Error; err != nil {
+		return false, err
+	}
+
+	var messageIDs []uint
+	for _, item := range items {
+		if item.SourceType == "Message" {
+			messageIDs = append(messageIDs, item.SourceID)
+		}
+	}
+	readMessages := make(map[uint]bool)
+	if len(messageIDs) > 0 {
+		var readIDs []uint
+		if err := db.Model(&models.Message{}).Where("id IN ? AND is_read = ?", messageIDs, true).Pluck("id", &readIDs).Error; err != nil {
+			return false, err
+		}
+		for _, id := range readIDs {
+			readMessages[id] = true
+		}
+	}
+
+	var itemIDs []uint
+	var pending []models.DigestItem
+	for _, item := range items {
+		itemIDs = append(itemIDs, item.ID)
+		if item.SourceType == "Message" && readMessages[item.SourceID] {
+			continue
+		}
+		pending = append(pending, item)
+	}
+
+	if len(pending) > 0 {
+		subject := fmt.Sprintf("Your %s digest: %d new notification(s)", user.DigestFrequency, len(pending))
+		body := renderDigest(user, pending)
+		if err := sendEmailWithUnsubscribe(h.emailService, h.cfg, user, UnsubscribeAllCategories, "your digest email settings", subject, body); err != nil {
+			return false, err
+		}
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if len(itemIDs) > 0 {
+			if err := tx.Model(&models.DigestItem{}).Where("id IN ?", itemIDs).Update("sent_at", now).Error; err != nil {
+				return err
+			}
+		}
+		if len(pending) > 0 {
+			return tx.Model(&models.User{}).Where("id = ?", user.ID).Update("last_digest_sent_at", now).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to mark digest items as sent: %w", err)
+	}
+	if len(pending) > 0 {
+		LogUserAction(h.db, 0, "SYSTEM_DIGEST_EMAIL_SENT", user.ID, "User", fmt.Sprintf("Digest with %d item(s) sent to %s", len(pending), user.Email), nil)
+	}
+	return len(pending) > 0, nil
+}
+
+// renderDigest builds the digest email body, grouping items by category. Items with the same
+// title (e.g. several messages from the same sender) are collapsed into one line showing the latest summary.
+func renderDigest(user models.User, items []models.DigestItem) string {
+	type digestLine struct {
+		title   string
+		summary string
+		count   int
+	}
+
+	byCategory := make(map[models.NotificationCategory][]*digestLine)
+	for _, item := range items {
+		var line *digestLine
+		for _, existing := range byCategory[item.Category] {
+			if existing.title == item.Title {
+				line = existing
+				break
+			}
+		}
+		if line == nil {
+			line = &digestLine{title: item.Title}
+			byCategory[item.Category] = append(byCategory[item.Category], line)
+		}
+		line.summary = item.Summary
+		line.count++
+	}
+
+	name := user.FirstName
+	if name == "" {
+		name = user.Email
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<h1>Hi %s,</h1><p>Here is what happened since your last digest.</p>", html.EscapeString(name))
+	for _, category := range models.NotificationCategories {
+		lines := byCategory[category]
+		if len(lines) == 0 {
+			continue
+		}
+		heading := strings.ReplaceAll(string(category), "_", " ")
+		fmt.Fprintf(&b, "<h2>%s</h2><ul>", html.EscapeString(strings.ToUpper(heading[:1])+heading[1:]))
+		for _, line := range lines {
+			title := html.EscapeString(line.title)
+			if line.count > 1 {
+				title = fmt.Sprintf("%s (%d)", title, line.count)
+			}
+			if line.summary != "" {
+				fmt.Fprintf(&b, "<li><b>%s</b><br/>%s</li>", title, html.EscapeString(line.summary))
+			} else {
+				fmt.Fprintf(&b, "<li><b>%s</b></li>", title)
+			}
+		}
+		b.WriteString("</ul>")
+	}
+	b.WriteString("<p>Please log in to see the details.</p><p>Thank you,<br/>The Platform Team</p>")
+	return b.String()
+}
+
+// SendEventReminders notifies parents and educators who saved the hosting school about published events
+// starting within the next 24 hours. Each event is reminded about once.
+func (h *DigestHandler) SendEventReminders(ctx context.Context) error {
+	db := h.db.WithContext(ctx)
+	now := time.Now()
+
+	var events []models.Event
+	err := db.Preload("Institution").
+		Where("is_published = ? AND reminder_sent_at IS NULL AND start_date > ? AND start_date <= ?", true, now, now.Add(eventReminderWindow)).
+		Find(&events).Error
+	if err != nil {
+		return fmt.Errorf("failed to find upcoming events: %w", err)
+	}
+
+	for _, event := range events {
+		recipients, err := eventAudience(db, event)
+		if err != nil {
+			log.Printf("Event reminders: failed to find recipients for event %d: %v", event.ID, err)
+			continue
+		}
+
+		subject := fmt.Sprintf("Reminder: %s starts %s", event.Title, event.StartDate.Format("Mon, Jan 2 at 15:04 MST"))
+		location := event.Location
+		if event.VirtualEvent {
+			location = "Online"
+		}
+		summary := fmt.Sprintf("%s, %s", event.StartDate.Format("Mon, Jan 2 at 15:04 MST"), location)
+		delivered := 0
+		for _, recipient := range recipients {
+			name := recipient.FirstName
+			if name == "" {
+				name = recipient.Email
+			}
+			body := fmt.Sprintf(
+				"<h1>Hi %s,</h1><p><b>%s</b>, hosted by %s, starts soon.</p><p>%s</p><p>Thank you,<br/>The Platform Team</p>",
+				html.EscapeString(name), html.EscapeString(event.Title), html.EscapeString(event.Institution.InstitutionName), html.EscapeString(summary),
+			)
+			item := models.DigestItem{Title: "Upcoming: " + event.Title, Summary: summary, SourceType: "Event", SourceID: event.ID}
+			result, err := deliverNotificationEmail(h.db, h.emailService, h.cfg, recipient, models.NotificationCategoryEvents, subject, body, item)
+			if err != nil {
+				log.Printf("Event reminders: failed to notify user %d about event %d: %v", recipient.ID, event.ID, err)
+				continue
+			}
+			if result != deliverySkipped {
+				delivered++
+			}
+		}
+
+		if err := db.Model(&models.Event{}).Where("id = ?", event.ID).Update("reminder_sent_at", now).Error; err != nil {
+			log.Printf("Event reminders: failed to mark event %d as reminded: %v", event.ID, err)
+			continue
+		}
+		LogUserAction(h.db, 0, "SYSTEM_EVENT_REMINDER_SENT", event.ID, "Event", fmt.Sprintf("Reminder delivered to %d of %d user(s)", delivered, len(recipients)), nil)
+	}
+	return nil
+}
+
+// eventAudience returns the active parents and educators who saved the school of the event's institution,
+// limited to the event's audience. event.Institution must be loaded.
+func eventAudience(db *gorm.DB, event models.Event) ([]models.User, error) {
+	if event.Institution.SchoolID == nil {
+		return nil, nil
+	}
+	schoolID := *event.Institution.SchoolID
+	audience := strings.ToLower(event.Audience)
+
+	var subqueries []interface{}
+	conditions := []string{}
+	if audience != "educators" {
+		conditions = append(conditions, "id IN (?)")
+		subqueries = append(subqueries, db.Table("parent_profiles").
+			Select("parent_profiles.user_id").
+			Joins("JOIN parent_saved_schools ON parent_saved_schools.parent_profile_id = parent_profiles.id").
+			Where("parent_saved_schools.school_id = ?", schoolID))
+	}
+	if audience != "parents" {
+		conditions = append(conditions, "id IN (?)")
+		subqueries = append(subqueries, db.Table("educator_profiles").
+			Select("educator_profiles.user_id").
+			Joins("JOIN educator_saved_schools ON educator_saved_schools.educator_profile_id = educator_profiles.id").
+			Where("educator_saved_schools.school_id = ?", schoolID))
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	var users []models.User
+	err := db.Where("is_active = ?", true).Where("("+strings.Join(conditions, " OR ")+")", subqueries...).Find(&users).Error
+	return users, err
+}
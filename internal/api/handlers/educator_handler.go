@@ -1,20 +1,29 @@
 package handlers
 
 import (
+	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
+	"html"
+	"log"
+	"mwc_backend/config"
+	"mwc_backend/internal/email"
 	"mwc_backend/internal/models"
 	"mwc_backend/internal/queue"
 	"strconv"
 )
 
 type EducatorHandler struct {
-	db        *gorm.DB
-	mqService queue.MessageQueueService
+	db           *gorm.DB
+	mqService    queue.MessageQueueService
+	emailService email.EmailService
+	cfg          *config.Config
+	notifier     *Notifier
+	recommender  *Recommender
 }
 
-func NewEducatorHandler(db *gorm.DB, mq queue.MessageQueueService) *EducatorHandler {
-	return &EducatorHandler{db: db, mqService: mq}
+func NewEducatorHandler(db *gorm.DB, mq queue.MessageQueueService, emailService email.EmailService, cfg *config.Config, notifier *Notifier, recommender *Recommender) *EducatorHandler {
+	return &EducatorHandler{db: db, mqService: mq, emailService: emailService, cfg: cfg, notifier: notifier, recommender: recommender}
 }
 
 type EducatorProfileRequest struct {
@@ -126,6 +135,7 @@ func (h *EducatorHandler) SaveSchool(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid school ID format"})
 	}
+	schoolID = uint64(resolveSchoolID(h.db, uint(schoolID))) // Save the surviving school of a merged school
 
 	var educatorProfile models.EducatorProfile
 	if err := h.db.Preload("SavedSchools").Where("user_id = ?", actorUserID).First(&educatorProfile).Error; err != nil {
@@ -151,6 +161,7 @@ func (h *EducatorHandler) SaveSchool(c *fiber.Ctx) error {
 	}
 
 	LogUserAction(h.db, actorUserID, "EDU_SCHOOL_SAVE_SUCCESS", uint(schoolID), "School", "School saved", c)
+	h.recommender.Enqueue(actorUserID)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "School saved successfully."})
 }
 
@@ -193,6 +204,7 @@ func (h *EducatorHandler) DeleteSavedSchool(c *fiber.Ctx) error {
 	// Check RowsAffected if precise feedback is needed.
 
 	LogUserAction(h.db, actorUserID, "EDU_SCHOOL_UNSAVE_SUCCESS", uint(schoolID), "School", "School unsaved", c)
+	h.recommender.Enqueue(actorUserID)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Saved school deleted successfully."})
 }
 
@@ -286,10 +298,59 @@ func (h *EducatorHandler) ApplyForJob(c *fiber.Ctx) error {
 	}
 
 	LogUserAction(h.db, actorUserID, "EDU_JOB_APPLY_SUCCESS", uint(jobID), "JobApplication", "Application submitted", c)
-	// TODO: Notify institution about new application (e.g., via email or RabbitMQ)
+	h.recommender.Enqueue(actorUserID)
+	h.notifyInstitutionOfApplication(actorUserID, job, application)
+	h.notifier.Publish(InstitutionApplicantsTopic(job.InstitutionProfileID), "new_applicant", map[string]interface{}{
+		"application_id": application.ID,
+		"job_id":         job.ID,
+		"job_title":      job.Title,
+		"status":         application.Status,
+		"applied_at":     application.CreatedAt,
+	})
 	return c.Status(fiber.StatusCreated).JSON(application)
 }
 
+// notifyInstitutionOfApplication emails the institution that posted the job about a new applicant,
+// or queues it for their digest. Failures are logged and do not fail the application.
+func (h *EducatorHandler) notifyInstitutionOfApplication(applicantUserID uint, job models.Job, application models.JobApplication) {
+	var institution models.InstitutionProfile
+	if err := h.db.Preload("User").First(&institution, job.InstitutionProfileID).Error; err != nil {
+		log.Printf("Failed to load institution %d to notify about application %d: %v", job.InstitutionProfileID, application.ID, err)
+		return
+	}
+	var applicant models.User
+	if err := h.db.First(&applicant, applicantUserID).Error; err != nil {
+		log.Printf("Failed to load applicant %d to notify about application %d: %v", applicantUserID, application.ID, err)
+		return
+	}
+
+	applicantName := fmt.Sprintf("%s %s", applicant.FirstName, applicant.LastName)
+	subject := fmt.Sprintf("New applicant for %s", job.Title)
+	h.notifier.Notify(models.Notification{
+		UserID:   institution.UserID,
+		Category: models.NotificationCategoryJobApplications,
+		Type:     models.NotificationTypeNewApplication,
+		Title:    subject,
+		Body:     fmt.Sprintf("%s applied", applicantName),
+		Payload:  map[string]interface{}{"job_id": job.ID, "application_id": application.ID},
+		DeepLink: fmt.Sprintf("/institution/jobs/%d/applicants", job.ID),
+	})
+
+	body := fmt.Sprintf(
+		"<h1>Hi %s,</h1><p>%s has applied for your job posting <b>%s</b>.</p><p>Please log in to review the application.</p><p>Thank you,<br/>The Platform Team</p>",
+		html.EscapeString(institution.InstitutionName), html.EscapeString(applicantName), html.EscapeString(job.Title),
+	)
+	item := models.DigestItem{
+		Title:      subject,
+		Summary:    fmt.Sprintf("%s applied", applicantName),
+		SourceType: "JobApplication",
+		SourceID:   application.ID,
+	}
+	if _, err := deliverNotificationEmail(h.db, h.emailService, h.cfg, institution.User, models.NotificationCategoryJobApplications, subject, body, item); err != nil {
+		log.Printf("Failed to notify institution %d about application %d: %v", institution.ID, application.ID, err)
+	}
+}
+
 // GetAppliedJobs retrieves all jobs an educator has applied for.
 // @Summary Get applied jobs
 // @Description Retrieves all job applications submitted by the educator
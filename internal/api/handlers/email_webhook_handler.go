@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"mwc_backend/config"
+	"mwc_backend/internal/email"
+	"mwc_backend/internal/models"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// EmailWebhookHandler handles bounce and complaint notifications from email providers
+type EmailWebhookHandler struct {
+	db  *gorm.DB
+	cfg *config.Config
+	sns *email.SNSVerifier
+}
+
+// NewEmailWebhookHandler creates a new EmailWebhookHandler
+func NewEmailWebhookHandler(db *gorm.DB, cfg *config.Config) *EmailWebhookHandler {
+	return &EmailWebhookHandler{db: db, cfg: cfg, sns: email.NewSNSVerifier()}
+}
+
+// EmailSuppressionCheck returns an email.SuppressionCheck that looks the address up on models.User.
+func EmailSuppressionCheck(db *gorm.DB) email.SuppressionCheck {
+	return func(address string) (bool, error) {
+		var count int64
+		err := db.Model(&models.User{}).Where("LOWER(email) = LOWER(?) AND email_suppressed = ?", address, true).Count(&count).Error
+		return count > 0, err
+	}
+}
+
+// HandleSES processes Amazon SES bounce and complaint notifications delivered through SNS
+// @Summary Amazon SES bounce and complaint webhook
+// @Description Receives SES bounce and complaint notifications delivered by an SNS HTTPS subscription. SNS message signatures are verified and subscription confirmations are confirmed automatically. Requires the EMAIL_WEBHOOK_SECRET in the `X-Webhook-Secret` header or as the HTTP basic authentication password, e.g. in the URL configured at the provider. Only available when EMAIL_WEBHOOK_SECRET is set.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Secret header string false "Email webhook secret, unless sent with basic authentication"
+// @Success 200 {object} map[string]interface{} "Notification processed"
+// @Failure 400 {object} map[string]string "Invalid payload"
+// @Failure 401 {object} map[string]string "Invalid webhook secret"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /webhooks/email/ses [post]
+func (h *EmailWebhookHandler) HandleSES(c *fiber.Ctx) error {
+	if !h.authorized(c) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid webhook secret"})
+	}
+
+	envelope, err := email.ParseSNSEnvelope(c.Body())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := h.sns.Verify(envelope); err != nil {
+		log.Printf("[EmailWebhook] Rejected SNS message %s from %s: %v", envelope.MessageID, envelope.TopicArn, err)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid SNS signature"})
+	}
+
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		if err := confirmSNSSubscription(envelope.SubscribeURL); err != nil {
+			log.Printf("[EmailWebhook] Failed to confirm SNS subscription for %s: %v", envelope.TopicArn, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to confirm subscription"})
+		}
+		log.Printf("[EmailWebhook] Confirmed SNS subscription for %s", envelope.TopicArn)
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Subscription confirmed"})
+	case "Notification":
+		events, err := email.ParseSESNotification(envelope.Message)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return h.processEvents(c, events)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Ignored"})
+}
+
+// HandleSendGrid processes SendGrid Event Webhook batches
+// @Summary SendGrid bounce and complaint webhook
+// @Description Receives SendGrid Event Webhook batches. Bounce and spam report events are recorded, all other events are ignored. Requires the EMAIL_WEBHOOK_SECRET in the `X-Webhook-Secret` header or as the HTTP basic authentication password, e.g. in the URL configured at the provider. Only available when EMAIL_WEBHOOK_SECRET is set.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Secret header string false "Email webhook secret, unless sent with basic authentication"
+// @Success 200 {object} map[string]interface{} "Events processed"
+// @Failure 400 {object} map[string]string "Invalid payload"
+// @Failure 401 {object} map[string]string "Invalid webhook secret"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /webhooks/email/sendgrid [post]
+func (h *EmailWebhookHandler) HandleSendGrid(c *fiber.Ctx) error {
+	if !h.authorized(c) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid webhook secret"})
+	}
+
+	events, err := email.ParseSendGridEvents(c.Body())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return h.processEvents(c, events)
+}
+
+// HandlePostmark processes Postmark bounce and spam complaint webhooks
+// @Summary Postmark bounce and complaint webhook
+// @Description Receives Postmark bounce and spam complaint webhooks. Requires the EMAIL_WEBHOOK_SECRET in the `X-Webhook-Secret` header or as the HTTP basic authentication password, e.g. in the URL configured at the provider. Only available when EMAIL_WEBHOOK_SECRET is set.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Secret header string false "Email webhook secret, unless sent with basic authentication"
+// @Success 200 {object} map[string]interface{} "Event processed"
+// @Failure 400 {object} map[string]string "Invalid payload"
+// @Failure 401 {object} map[string]string "Invalid webhook secret"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /webhooks/email/postmark [post]
+func (h *EmailWebhookHandler) HandlePostmark(c *fiber.Ctx) error {
+	if !h.authorized(c) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid webhook secret"})
+	}
+
+	events, err := email.ParsePostmarkWebhook(c.Body())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return h.processEvents(c, events)
+}
+
+// authorized checks the shared webhook secret. Providers cannot all send custom headers, so the secret is
+// also accepted as the basic authentication password, which they support in the configured URL. The routes
+// are only registered when a secret is configured.
+func (h *EmailWebhookHandler) authorized(c *fiber.Ctx) bool {
+	if h.cfg.EmailWebhookSecret == "" {
+		return false
+	}
+	token := c.Get("X-Webhook-Secret")
+	if token == "" {
+		token = basicAuthPassword(c.Get(fiber.HeaderAuthorization))
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.cfg.EmailWebhookSecret)) == 1
+}
+
+// basicAuthPassword returns the password of a basic Authorization header, or "" if there is none
+func basicAuthPassword(header string) string {
+	encoded, ok := strings.CutPrefix(header, "Basic ")
+	if !ok {
+		return ""
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return ""
+	}
+	_, password, _ := strings.Cut(string(decoded), ":")
+	return password
+}
+
+// processEvents records the events and updates the suppression state of the affected users.
+func (h *EmailWebhookHandler) processEvents(c *fiber.Ctx, events []email.DeliveryEvent) error {
+	suppressed := 0
+	for _, event := range events {
+		if event.Email == "" {
+			continue
+		}
+		didSuppress, err := h.recordEvent(event)
+		if err != nil {
+			log.Printf("[EmailWebhook] Failed to record %s %s for %s: %v", event.Provider, event.Type, event.Email, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to record email event"})
+		}
+		if didSuppress {
+			suppressed++
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"processed": len(events), "suppressed": suppressed})
+}
+
+// recordEvent stores the event and suppresses the user's address on a hard bounce, a complaint,
+// or once soft bounces reach the configured limit. It reports whether the address was newly suppressed.
+func (h *EmailWebhookHandler) recordEvent(event email.DeliveryEvent) (bool, error) {
+	var suppressedUserID uint
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		var userID *uint
+		err := tx.Where("LOWER(email) = LOWER(?)", event.Email).First(&user).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if err == nil {
+			userID = &user.ID
+		}
+
+		emailEvent := models.EmailEvent{
+			Provider:          event.Provider,
+			Type:              models.EmailEventType(event.Type),
+			Email:             event.Email,
+			UserID:            userID,
+			Reason:            event.Reason,
+			ProviderMessageID: event.MessageID,
+			OccurredAt:        event.OccurredAt,
+		}
+		if err := tx.Create(&emailEvent).Error; err != nil {
+			return err
+		}
+		if userID == nil || user.EmailSuppressed {
+			return nil
+		}
+
+		updates := map[string]interface{}{}
+		reason := ""
+		switch event.Type {
+		case email.HardBounce:
+			reason = "Hard bounce: " + event.Reason
+		case email.Complaint:
+			reason = "Spam complaint"
+		case email.SoftBounce:
+			user.EmailBounceCount++
+			updates["email_bounce_count"] = user.EmailBounceCount
+			updates["last_email_bounce_at"] = event.OccurredAt
+			if user.EmailBounceCount >= h.cfg.EmailSoftBounceLimit {
+				reason = fmt.Sprintf("%d soft bounces, last: %s", user.EmailBounceCount, event.Reason)
+			}
+		}
+		if event.Type == email.HardBounce {
+			updates["last_email_bounce_at"] = event.OccurredAt
+		}
+		if reason != "" {
+			now := time.Now()
+			updates["email_suppressed"] = true
+			updates["email_suppressed_at"] = now
+			updates["email_suppression_reason"] = truncateMessage(strings.TrimSpace(reason), 255)
+			suppressedUserID = user.ID
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&models.User{}).Where("id = ?", user.ID).Updates(updates).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	if suppressedUserID == 0 {
+		return false, nil
+	}
+	log.Printf("[EmailWebhook] Suppressed email address %s after %s reported by %s", event.Email, event.Type, event.Provider)
+	LogUserAction(h.db, 0, "SYSTEM_EMAIL_SUPPRESSED", suppressedUserID, "User", fmt.Sprintf("%s reported by %s: %s", event.Type, event.Provider, event.Reason), nil)
+	return true, nil
+}
+
+// confirmSNSSubscription visits the SubscribeURL of an SNS subscription confirmation.
+// Only HTTPS URLs on SNS hosts are followed so the webhook cannot be used to make arbitrary requests.
+func confirmSNSSubscription(subscribeURL string) error {
+	if !email.ValidSNSURL(subscribeURL) {
+		return fmt.Errorf("unexpected SubscribeURL %q", subscribeURL)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(subscribeURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("SubscribeURL returned status %d", resp.StatusCode)
+	}
+	return nil
+}
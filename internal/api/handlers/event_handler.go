@@ -18,11 +18,12 @@ type EventHandler struct {
 	db        *gorm.DB
 	cfg       *config.Config
 	mqService queue.MessageQueueService
+	notifier  *Notifier
 }
 
 // NewEventHandler creates a new EventHandler
-func NewEventHandler(db *gorm.DB, cfg *config.Config, mqService queue.MessageQueueService) *EventHandler {
-	return &EventHandler{db: db, cfg: cfg, mqService: mqService}
+func NewEventHandler(db *gorm.DB, cfg *config.Config, mqService queue.MessageQueueService, notifier *Notifier) *EventHandler {
+	return &EventHandler{db: db, cfg: cfg, mqService: mqService, notifier: notifier}
 }
 
 // CreateEventRequest is the request body for creating an event
@@ -162,11 +163,9 @@ func (h *EventHandler) CreateEvent(c *fiber.Ctx) error {
 
 	LogUserAction(h.db, userID, "EVENT_CREATED", event.ID, "Event", fmt.Sprintf("Event created: %s", req.Title), c)
 
-	// If WebSocket is enabled, broadcast event creation to all connected clients
-	if h.cfg.WebSocketEnabled {
-		// This would be implemented if we had a reference to the WebSocketHandler
-		// For now, we'll just log it
-		log.Printf("WebSocket: Event created: %s", req.Title)
+	// Announce the event to clients following the events feed
+	if event.IsPublished {
+		h.publishEvent(EventsTopic, "event_created", event)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -511,10 +510,12 @@ func (h *EventHandler) UpdateEvent(c *fiber.Ctx) error {
 	event.LocalizedDescriptions = localizedDescriptions
 
 	// Update published status if changed
+	newlyPublished := false
 	if req.IsPublished != event.IsPublished {
 		event.IsPublished = req.IsPublished
 		if req.IsPublished {
 			event.PublishedAt = time.Now()
+			newlyPublished = true
 		}
 	}
 
@@ -524,6 +525,13 @@ func (h *EventHandler) UpdateEvent(c *fiber.Ctx) error {
 	}
 
 	LogUserAction(h.db, userID, "EVENT_UPDATED", event.ID, "Event", fmt.Sprintf("Event updated: %s", req.Title), c)
+	if event.IsPublished {
+		h.notifyEventAudience(event)
+	}
+	h.publishEvent(EventTopic(event.ID), "event_updated", event)
+	if newlyPublished {
+		h.publishEvent(EventsTopic, "event_created", event)
+	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Event updated successfully",
@@ -544,6 +552,54 @@ func (h *EventHandler) UpdateEvent(c *fiber.Ctx) error {
 	})
 }
 
+// publishEvent sends the event to the WebSocket subscribers of a topic when WebSocket is enabled.
+func (h *EventHandler) publishEvent(topic string, messageType string, event models.Event) {
+	if !h.cfg.WebSocketEnabled {
+		return
+	}
+	h.notifier.Publish(topic, messageType, map[string]interface{}{
+		"id":             event.ID,
+		"institution_id": event.InstitutionID,
+		"title":          event.Title,
+		"start_date":     event.StartDate,
+		"end_date":       event.EndDate,
+		"location":       event.Location,
+		"virtual_event":  event.VirtualEvent,
+		"event_type":     event.EventType,
+		"audience":       event.Audience,
+		"is_published":   event.IsPublished,
+		"published_at":   event.PublishedAt,
+	})
+}
+
+// notifyEventAudience tells the users who saved the hosting school that a published event changed.
+func (h *EventHandler) notifyEventAudience(event models.Event) {
+	if err := h.db.First(&event.Institution, event.InstitutionID).Error; err != nil {
+		log.Printf("Error loading institution of event %d: %v", event.ID, err)
+		return
+	}
+	audience, err := eventAudience(h.db, event)
+	if err != nil {
+		log.Printf("Error finding audience of event %d: %v", event.ID, err)
+		return
+	}
+
+	userIDs := make([]uint, 0, len(audience))
+	for _, user := range audience {
+		if user.ID != event.CreatorID {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+	h.notifier.NotifyUsers(userIDs, models.Notification{
+		Category: models.NotificationCategoryEvents,
+		Type:     models.NotificationTypeEventUpdated,
+		Title:    fmt.Sprintf("%s was updated", event.Title),
+		Body:     fmt.Sprintf("Starts %s", event.StartDate.Format("Mon, Jan 2 at 15:04 MST")),
+		Payload:  map[string]interface{}{"event_id": event.ID},
+		DeepLink: fmt.Sprintf("/events/%d", event.ID),
+	})
+}
+
 // DeleteEvent deletes an event
 // @Summary Delete an event
 // @Description Deletes an existing event
@@ -590,6 +646,7 @@ func (h *EventHandler) DeleteEvent(c *fiber.Ctx) error {
 	}
 
 	LogUserAction(h.db, userID, "EVENT_DELETED", event.ID, "Event", fmt.Sprintf("Event deleted: %s", event.Title), c)
+	h.publishEvent(EventTopic(event.ID), "event_deleted", event)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Event deleted successfully",
@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mwc_backend/config"
+	"mwc_backend/internal/models"
+	"mwc_backend/internal/queue"
+	"mwc_backend/internal/storage"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"gorm.io/gorm"
+)
+
+const (
+	SchoolImportQueue = "q.imports.schools" // Queue of school import jobs, published to through the default exchange
+
+	importJobProgressInterval = time.Second // Minimum time between progress pushes of a job
+)
+
+var errImportStorageUnavailable = errors.New("file storage is not available")
+
+// importJobMessage is the payload of a queued import job
+type importJobMessage struct {
+	JobID uint `json:"job_id"`
+}
+
+// ImportJobHandler runs school imports in the background and lets admins follow and cancel them
+type ImportJobHandler struct {
+	db       *gorm.DB
+	cfg      *config.Config
+	mq       queue.MessageQueueService
+	store    storage.Storage // Nil when the storage backend could not be initialized
+	notifier *Notifier
+}
+
+// NewImportJobHandler creates a new ImportJobHandler. store may be nil, in which case imports are unavailable.
+func NewImportJobHandler(db *gorm.DB, cfg *config.Config, mq queue.MessageQueueService, store storage.Storage, notifier *Notifier) *ImportJobHandler {
+	return &ImportJobHandler{db: db, cfg: cfg, mq: mq, store: store, notifier: notifier}
+}
+
+// Start declares the import queue and starts processing jobs from it. Without RabbitMQ jobs run on the
+// node that received the upload, and jobs left over from a previous run are picked up here.
+func (h *ImportJobHandler) Start() error {
+	if h.mq == nil || !h.mq.IsInitialized() {
+		log.Println("RabbitMQ service not initialized, school imports will run in-process.")
+		go h.resumeLocalJobs()
+		return nil
+	}
+	if _, err := h.mq.DeclareQueue(SchoolImportQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue '%s': %w", SchoolImportQueue, err)
+	}
+	return h.mq.Consume(SchoolImportQueue, "school-imports", func(delivery amqp.Delivery) error {
+		var message importJobMessage
+		if err := json.Unmarshal(delivery.Body, &message); err != nil {
+			return fmt.Errorf("invalid import job message: %w", err)
+		}
+		return h.process(context.Background(), message.JobID)
+	})
+}
+
+// resumeLocalJobs fails jobs interrupted by a restart and runs the queued ones, when jobs are not queued in RabbitMQ.
+func (h *ImportJobHandler) resumeLocalJobs() {
+	var jobIDs []uint
+	if err := h.db.Model(&models.ImportJob{}).Where("status IN ?", []models.ImportJobStatus{models.ImportJobQueued, models.ImportJobProcessing}).
+		Order("id").Pluck("id", &jobIDs).Error; err != nil {
+		log.Printf("Error loading pending import jobs: %v", err)
+		return
+	}
+	for _, jobID := range jobIDs {
+		if err := h.process(context.Background(), jobID); err != nil {
+			log.Printf("Error processing import job %d: %v", jobID, err)
+		}
+	}
+}
+
+// maxFileSize is the size limit of an uploaded import file in bytes
+func (h *ImportJobHandler) maxFileSize() int64 {
+	return int64(h.cfg.ImportMaxSizeMB) * 1024 * 1024
+}
+
+// EnqueueSchoolImport stores the uploaded file and queues a job that imports its schools.
+func (h *ImportJobHandler) EnqueueSchoolImport(ctx context.Context, adminUserID uint, fileName, format string, data []byte, columnMapping map[string]string) (*models.ImportJob, error) {
+	if h.store == nil {
+		return nil, errImportStorageUnavailable
+	}
+	job := &models.ImportJob{
+		CreatedByUserID: adminUserID,
+		FileName:        fileName,
+		Format:          format,
+		FileKey:         fmt.Sprintf("imports/%s/%s.%s", time.Now().UTC().Format("2006/01"), uuid.NewString(), format),
+		Status:          models.ImportJobQueued,
+	}
+	if len(columnMapping) > 0 {
+		mapping, _ := json.Marshal(columnMapping)
+		job.ColumnMapping = string(mapping)
+	}
+	if err := h.store.Put(ctx, job.FileKey, bytes.NewReader(data), int64(len(data)), "application/octet-stream"); err != nil {
+		return nil, fmt.Errorf("failed to store import file: %w", err)
+	}
+	if err := h.db.Create(job).Error; err != nil {
+		h.store.Delete(context.Background(), job.FileKey)
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	if h.mq == nil || !h.mq.IsInitialized() {
+		go func(jobID uint) {
+			if err := h.process(context.Background(), jobID); err != nil {
+				log.Printf("Error processing import job %d: %v", jobID, err)
+			}
+		}(job.ID)
+		return job, nil
+	}
+	body, _ := json.Marshal(importJobMessage{JobID: job.ID})
+	if err := h.mq.Publish(ctx, "", SchoolImportQueue, body, 0); err != nil {
+		h.finish(job, models.ImportJobFailed, "failed to queue the import")
+		return nil, fmt.Errorf("failed to queue import job: %w", err)
+	}
+	return job, nil
+}
+
+// process imports the file of a queued job. Progress is saved and pushed to the admin after every batch,
+// and the import stops at the next batch once the job is cancelled.
+func (h *ImportJobHandler) process(ctx context.Context, jobID uint) error {
+	now := time.Now()
+	claim := h.db.Model(&models.ImportJob{}).Where("id = ? AND status = ?", jobID, models.ImportJobQueued).
+		Updates(map[string]interface{}{"status": models.ImportJobProcessing, "started_at": now})
+	if claim.Error != nil {
+		return claim.Error
+	}
+
+	var job models.ImportJob
+	if err := h.db.First(&job, jobID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if claim.RowsAffected == 0 {
+		// A job that is still processing was interrupted, e.g. by a restart, and is redelivered.
+		// Its rows cannot be told apart from rows imported before, so it is not run again.
+		if job.Status == models.ImportJobProcessing {
+			h.finish(&job, models.ImportJobFailed, fmt.Sprintf("the import was interrupted after %d rows, upload the remaining rows again", job.ProcessedRows))
+		}
+		return nil
+	}
+	h.push(&job)
+
+	if h.store == nil {
+		h.finish(&job, models.ImportJobFailed, errImportStorageUnavailable.Error())
+		return nil
+	}
+	reader, err := h.store.Get(ctx, job.FileKey)
+	if err != nil {
+		h.finish(&job, models.ImportJobFailed, "failed to read the uploaded file: "+err.Error())
+		return nil
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		h.finish(&job, models.ImportJobFailed, "failed to read the uploaded file: "+err.Error())
+		return nil
+	}
+	mapping, err := parseColumnMapping(job.ColumnMapping)
+	if err != nil {
+		h.finish(&job, models.ImportJobFailed, err.Error())
+		return nil
+	}
+	rows, err := parseSchoolImport(job.Format, data, mapping)
+	if err != nil {
+		h.finish(&job, models.ImportJobFailed, err.Error())
+		return nil
+	}
+
+	job.TotalRows = len(rows)
+	h.db.Model(&job).Update("total_rows", job.TotalRows)
+	h.push(&job)
+
+	lastPush := time.Now()
+	result, err := importSchools(h.db, rows, job.CreatedByUserID, func(processed int, result SchoolImportResult) error {
+		job.ProcessedRows, job.CreatedCount, job.FailedCount = processed, result.CreatedCount, result.FailedCount
+		if err := h.db.Model(&job).Select("processed_rows", "created_count", "failed_count").Updates(&job).Error; err != nil {
+			log.Printf("Error saving progress of import job %d: %v", job.ID, err)
+		}
+		var current models.ImportJob
+		if err := h.db.Select("status").First(&current, job.ID).Error; err == nil && current.Status == models.ImportJobCancelled {
+			return errImportCancelled
+		}
+		if time.Since(lastPush) >= importJobProgressInterval {
+			h.push(&job)
+			lastPush = time.Now()
+		}
+		return nil
+	})
+
+	if len(result.Errors) > 0 {
+		var report bytes.Buffer
+		if reportErr := writeSchoolImportReport(&report, result.Errors); reportErr == nil {
+			errorFileKey := fmt.Sprintf("imports/%s/%s-errors.csv", time.Now().UTC().Format("2006/01"), uuid.NewString())
+			if putErr := h.store.Put(ctx, errorFileKey, &report, int64(report.Len()), "text/csv"); putErr != nil {
+				log.Printf("Error storing error report of import job %d: %v", job.ID, putErr)
+			} else {
+				job.ErrorFileKey = errorFileKey
+			}
+		}
+	}
+	if errors.Is(err, errImportCancelled) {
+		h.finish(&job, models.ImportJobCancelled, "")
+	} else {
+		h.finish(&job, models.ImportJobCompleted, "")
+	}
+
+	detail, _ := json.Marshal(map[string]interface{}{
+		"import_job_id": job.ID,
+		"file_name":     job.FileName,
+		"status":        job.Status,
+		"total_rows":    job.TotalRows,
+		"created_count": job.CreatedCount,
+		"failed_count":  job.FailedCount,
+	})
+	LogUserAction(h.db, job.CreatedByUserID, "ADMIN_SCHOOL_IMPORT_FINISHED", job.ID, "ImportJob", string(detail), nil)
+	return nil
+}
+
+// finish records the outcome of a job and deletes its uploaded file. The status only changes while the job
+// is queued or processing, so a cancellation is never overwritten.
+func (h *ImportJobHandler) finish(job *models.ImportJob, status models.ImportJobStatus, message string) {
+	if job.FileKey != "" && h.store != nil {
+		if err := h.store.Delete(context.Background(), job.FileKey); err != nil {
+			log.Printf("Error deleting file of import job %d: %v", job.ID, err)
+		}
+	}
+	now := time.Now()
+	job.FileKey, job.Error, job.FinishedAt = "", message, &now
+	if err := h.db.Model(job).Select("total_rows", "processed_rows", "created_count", "failed_count", "file_key", "error_file_key", "error", "finished_at").
+		Updates(job).Error; err != nil {
+		log.Printf("Error saving result of import job %d: %v", job.ID, err)
+	}
+	h.db.Model(&models.ImportJob{}).Where("id = ? AND status IN ?", job.ID, []models.ImportJobStatus{models.ImportJobQueued, models.ImportJobProcessing}).
+		Update("status", status)
+
+	var current models.ImportJob
+	if err := h.db.Select("status").First(&current, job.ID).Error; err == nil {
+		job.Status = current.Status
+	}
+	h.push(job)
+}
+
+// push sends the state of a job to the admin who uploaded the file
+func (h *ImportJobHandler) push(job *models.ImportJob) {
+	h.notifier.Push(job.CreatedByUserID, "import_job_progress", job)
+}
+
+// findJob loads the import job named by the job_id route parameter
+func (h *ImportJobHandler) findJob(c *fiber.Ctx) (*models.ImportJob, *fiber.Error) {
+	jobID, err := strconv.ParseUint(c.Params("job_id"), 10, 32)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid import job ID")
+	}
+	var job models.ImportJob
+	if err := h.db.First(&job, uint(jobID)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Import job not found")
+		}
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Database error: "+err.Error())
+	}
+	return &job, nil
+}
+
+// GetImportJobs lists import jobs, newest first (admin only)
+// @Summary List import jobs
+// @Description Retrieves background import jobs with their progress, newest first
+// @Tags admin,schools
+// @Produce json
+// @Param status query string false "Filter by status (queued, processing, completed, failed, cancelled)"
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(20)
+// @Success 200 {object} map[string]interface{} "List of import jobs with pagination metadata"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/import-jobs [get]
+func (h *ImportJobHandler) GetImportJobs(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	query := h.db.Model(&models.ImportJob{})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var jobs []models.ImportJob
+	if err := query.Order("created_at desc").Offset((page - 1) * limit).Limit(limit).Find(&jobs).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve import jobs: " + err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": jobs,
+		"meta": fiber.Map{
+			"total":     total,
+			"page":      page,
+			"limit":     limit,
+			"last_page": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetImportJob returns the progress of an import job (admin only)
+// @Summary Get an import job
+// @Description Retrieves the status and progress of a background import. The same data is pushed over the WebSocket of the admin who uploaded the file as `import_job_progress` messages while the job runs.
+// @Tags admin,schools
+// @Produce json
+// @Param job_id path int true "Import job ID"
+// @Success 200 {object} models.ImportJob "Import job"
+// @Failure 400 {object} map[string]string "Invalid import job ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Import job not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/import-jobs/{job_id} [get]
+func (h *ImportJobHandler) GetImportJob(c *fiber.Ctx) error {
+	job, lookupErr := h.findJob(c)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	return c.Status(fiber.StatusOK).JSON(job)
+}
+
+// CancelImportJob cancels a queued or running import job (admin only)
+// @Summary Cancel an import job
+// @Description Cancels a queued import, or stops a running import after the current batch. Schools imported before the cancellation are kept.
+// @Tags admin,schools
+// @Produce json
+// @Param job_id path int true "Import job ID"
+// @Success 200 {object} models.ImportJob "Import job cancelled"
+// @Failure 400 {object} map[string]string "Invalid import job ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Import job not found"
+// @Failure 409 {object} map[string]string "Import job already finished"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/import-jobs/{job_id}/cancel [post]
+func (h *ImportJobHandler) CancelImportJob(c *fiber.Ctx) error {
+	adminUserID, _ := c.Locals("user_id").(uint)
+	job, lookupErr := h.findJob(c)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+
+	// A queued job is finished right away, a processing job stops at its next batch.
+	now := time.Now()
+	queued := h.db.Model(&models.ImportJob{}).Where("id = ? AND status = ?", job.ID, models.ImportJobQueued).
+		Updates(map[string]interface{}{"status": models.ImportJobCancelled, "file_key": "", "finished_at": now})
+	if queued.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to cancel import job: " + queued.Error.Error()})
+	}
+	if queued.RowsAffected > 0 {
+		if h.store != nil && job.FileKey != "" {
+			h.store.Delete(context.Background(), job.FileKey)
+		}
+	} else {
+		processing := h.db.Model(&models.ImportJob{}).Where("id = ? AND status = ?", job.ID, models.ImportJobProcessing).
+			Update("status", models.ImportJobCancelled)
+		if processing.Error != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to cancel import job: " + processing.Error.Error()})
+		}
+		if processing.RowsAffected == 0 {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Import job has already finished"})
+		}
+	}
+
+	LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_IMPORT_CANCEL", job.ID, "ImportJob", "", c)
+	h.db.First(job, job.ID)
+	h.push(job)
+	return c.Status(fiber.StatusOK).JSON(job)
+}
+
+// DownloadImportJobErrors downloads the rejected rows of an import job (admin only)
+// @Summary Download the error report of an import job
+// @Description Downloads a CSV with the columns row, field, value and error for every rejected row of a finished import
+// @Tags admin,schools
+// @Produce text/csv
+// @Param job_id path int true "Import job ID"
+// @Success 200 {file} file "Error report"
+// @Failure 400 {object} map[string]string "Invalid import job ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Import job not found or without rejected rows"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "File storage unavailable"
+// @Security BearerAuth
+// @Router /api/v1/admin/import-jobs/{job_id}/errors [get]
+func (h *ImportJobHandler) DownloadImportJobErrors(c *fiber.Ctx) error {
+	job, lookupErr := h.findJob(c)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	if job.ErrorFileKey == "" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Import job has no error report"})
+	}
+	if h.store == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "File storage is not available"})
+	}
+
+	reader, err := h.store.Get(c.Context(), job.ErrorFileKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Error report not found"})
+		}
+		log.Printf("Error reading error report of import job %d: %v", job.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read error report"})
+	}
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="school-import-%d-errors.csv"`, job.ID))
+	// fasthttp closes the reader once the body has been sent
+	return c.Status(fiber.StatusOK).SendStream(reader)
+}
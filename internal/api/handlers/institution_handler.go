@@ -7,7 +7,6 @@ import (
 	"mwc_backend/internal/models"
 	"mwc_backend/internal/queue"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -17,10 +16,12 @@ import (
 type InstitutionHandler struct {
 	db        *gorm.DB
 	mqService queue.MessageQueueService
+	notifier  *Notifier
+	geocoder  *SchoolGeocoder
 }
 
-func NewInstitutionHandler(db *gorm.DB, mq queue.MessageQueueService) *InstitutionHandler {
-	return &InstitutionHandler{db: db, mqService: mq}
+func NewInstitutionHandler(db *gorm.DB, mq queue.MessageQueueService, notifier *Notifier, geocoder *SchoolGeocoder) *InstitutionHandler {
+	return &InstitutionHandler{db: db, mqService: mq, notifier: notifier, geocoder: geocoder}
 }
 
 type InstitutionProfileRequest struct {
@@ -99,77 +100,9 @@ func (h *InstitutionHandler) CreateOrUpdateInstitutionProfile(c *fiber.Ctx) erro
 	return c.Status(fiber.StatusOK).JSON(profile)
 }
 
-// SelectSchool allows an institution to select an existing school uploaded by admin.
-// @Summary Select a school
-// @Description Associates an institution with an existing school from the admin-uploaded list
-// @Tags institution,schools
-// @Produce json
-// @Param school_id path int true "School ID"
-// @Success 200 {object} map[string]interface{} "School selected successfully"
-// @Failure 400 {object} map[string]string "Bad request or invalid school ID"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Institution profile or school not found"
-// @Failure 409 {object} map[string]string "Institution already has a school or school already taken"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Security BearerAuth
-// @Router /api/v1/institution/schools/select/{school_id} [put]
-func (h *InstitutionHandler) SelectSchool(c *fiber.Ctx) error {
-	actorUserID, _ := c.Locals("user_id").(uint)
-	schoolIDStr := c.Params("school_id")
-	schoolID, err := strconv.ParseUint(schoolIDStr, 10, 32)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid school ID format"})
-	}
-
-	var institutionProfile models.InstitutionProfile
-	if err := h.db.Where("user_id = ?", actorUserID).First(&institutionProfile).Error; err != nil {
-		// ... (error handling)
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Institution profile not found."})
-	}
-
-	if institutionProfile.SchoolID != nil && *institutionProfile.SchoolID != 0 {
-		LogUserAction(h.db, actorUserID, "INST_SCHOOL_SELECT_FAIL_ALREADY_MAPPED", uint(schoolID), "School", "Institution already has a school", c)
-		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Institution already has a school selected."})
-	}
-
-	var school models.School
-	// Ensure school exists and was uploaded by admin (or meets other criteria if logic changes)
-	if err := h.db.Where("id = ? AND uploaded_by_admin = ?", uint(schoolID), true).First(&school).Error; err != nil {
-		// ... (error handling for school not found)
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Admin-uploaded school not found."})
-	}
-
-	// Critical: Check if this school is already selected by another institution (unique constraint on SchoolID in InstitutionProfile)
-	// GORM will enforce this at DB level if `gorm:"uniqueIndex"` is on SchoolID.
-	// We can pre-check to provide a friendlier error.
-	var existingSelection models.InstitutionProfile
-	errCheck := h.db.Where("school_id = ?", uint(schoolID)).First(&existingSelection).Error
-	if errCheck == nil { // A record was found
-		LogUserAction(h.db, actorUserID, "INST_SCHOOL_SELECT_FAIL_SCHOOL_TAKEN", uint(schoolID), "School", fmt.Sprintf("School taken by inst %d", existingSelection.ID), c)
-		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "This school is already mapped to another institution."})
-	}
-	if errCheck != gorm.ErrRecordNotFound { // Some other DB error during check
-		LogUserAction(h.db, actorUserID, "INST_SCHOOL_SELECT_FAIL_DB_CHECK", uint(schoolID), "School", errCheck.Error(), c)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error checking school availability: " + errCheck.Error()})
-	}
-
-	institutionProfile.SchoolID = &school.ID // Assign school.ID (which is uint)
-	if err := h.db.Save(&institutionProfile).Error; err != nil {
-		// This might fail due to the unique constraint if another request sneaked in.
-		LogUserAction(h.db, actorUserID, "INST_SCHOOL_SELECT_FAIL_SAVE", uint(schoolID), "School", err.Error(), c)
-		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "This school was just mapped by another institution. Please try another."})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to select school: " + err.Error()})
-	}
-
-	LogUserAction(h.db, actorUserID, "INST_SCHOOL_SELECT_SUCCESS", uint(schoolID), "School", "School selected", c)
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "School selected successfully", "school_id": school.ID, "school_name": school.Name})
-}
-
 // CreateSchool allows an institution to create a new school if not in the admin list.
 // @Summary Create a new school
-// @Description Creates a new school entry for an institution when not found in admin list
+// @Description Creates a new school entry for an institution when not found in admin list. The institution manages the school once it claims the school with verification documents and an admin approves the claim.
 // @Tags institution,schools
 // @Accept json
 // @Produce json
@@ -199,9 +132,8 @@ func (h *InstitutionHandler) CreateSchool(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
 	}
-	// TODO: Validate req (name, country_code are important)
-	if req.Name == "" || req.CountryCode == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "School name and country code are required."})
+	if fieldErrors := validationErrors(req); len(fieldErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": validationErrorsMessage(fieldErrors), "fields": fieldErrors})
 	}
 
 	newSchool := models.School{
@@ -217,30 +149,78 @@ func (h *InstitutionHandler) CreateSchool(c *fiber.Ctx) error {
 		UploadedByAdmin: false,
 		CreatedByUserID: &actorUserID, // Link to the institution user who created it
 	}
+	if err := h.geocoder.setSchoolLocation(c.Context(), &newSchool, req.Latitude, req.Longitude, true); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
 
-	tx := h.db.Begin() // Start transaction
-
-	if err := tx.Create(&newSchool).Error; err != nil {
-		tx.Rollback()
+	// The school is linked to the institution only once its claim is approved
+	if err := h.db.Create(&newSchool).Error; err != nil {
 		LogUserAction(h.db, actorUserID, "INST_SCHOOL_CREATE_FAIL_DB_SCHOOL", 0, "School", err.Error(), c)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create school: " + err.Error()})
 	}
 
-	// Link this new school to the institution
-	institutionProfile.SchoolID = &newSchool.ID
-	if err := tx.Save(&institutionProfile).Error; err != nil {
-		tx.Rollback()
-		LogUserAction(h.db, actorUserID, "INST_SCHOOL_CREATE_FAIL_DB_LINK", newSchool.ID, "InstitutionProfile", err.Error(), c)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to link new school to institution: " + err.Error()})
+	LogUserAction(h.db, actorUserID, "INST_SCHOOL_CREATE_SUCCESS", newSchool.ID, "School", "School created", c)
+	return c.Status(fiber.StatusCreated).JSON(newSchool)
+}
+
+// verifiedInstitutionSchool returns the school managed by the user's institution, if its claim was approved
+func verifiedInstitutionSchool(db *gorm.DB, userID uint) (*models.School, *fiber.Error) {
+	var institutionProfile models.InstitutionProfile
+	if err := db.Where("user_id = ?", userID).First(&institutionProfile).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Institution profile not found.")
+	}
+	notVerified := fiber.NewError(fiber.StatusForbidden, "Only verified institutions can edit their school. Claim your school to get verified.")
+	if !institutionProfile.IsVerified || institutionProfile.SchoolID == nil {
+		return nil, notVerified
+	}
+	var school models.School
+	if err := db.First(&school, *institutionProfile.SchoolID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "School not found.")
+	}
+	if school.OwnershipStatus != models.SchoolOwnershipVerified {
+		return nil, notVerified
 	}
+	return &school, nil
+}
 
-	if err := tx.Commit().Error; err != nil {
-		LogUserAction(h.db, actorUserID, "INST_SCHOOL_CREATE_FAIL_TX_COMMIT", newSchool.ID, "System", err.Error(), c)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Transaction failed while creating school: " + err.Error()})
+// UpdateSchoolAttributes lets a verified institution edit the Montessori profile of its school.
+// @Summary Update school programs and attributes
+// @Description Replaces the programs, age range, languages of instruction, tuition band, schedules and accreditations of the institution's school; omitted lists are cleared. Without age_min_months and age_max_months the age range is derived from the programs. Only institutions whose school claim was approved can edit their school.
+// @Tags institution,schools
+// @Accept json
+// @Produce json
+// @Param attributes body SchoolAttributesRequest true "School attributes"
+// @Success 200 {object} models.School "Updated school"
+// @Failure 400 {object} map[string]string "Invalid attributes"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Institution is not verified"
+// @Failure 404 {object} map[string]string "Institution profile or school not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/institution/school/attributes [put]
+func (h *InstitutionHandler) UpdateSchoolAttributes(c *fiber.Ctx) error {
+	actorUserID, _ := c.Locals("user_id").(uint)
+
+	school, lookupErr := verifiedInstitutionSchool(h.db, actorUserID)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
 	}
 
-	LogUserAction(h.db, actorUserID, "INST_SCHOOL_CREATE_SUCCESS", newSchool.ID, "School", "School created and linked", c)
-	return c.Status(fiber.StatusCreated).JSON(newSchool)
+	var req SchoolAttributesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+	}
+	fieldErrors, err := updateSchoolAttributes(h.db, school, req)
+	if len(fieldErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": validationErrorsMessage(fieldErrors), "fields": fieldErrors})
+	}
+	if err != nil {
+		LogUserAction(h.db, actorUserID, "INST_SCHOOL_ATTRIBUTES_FAIL", school.ID, "School", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update school: " + err.Error()})
+	}
+
+	LogUserAction(h.db, actorUserID, "INST_SCHOOL_ATTRIBUTES_SUCCESS", school.ID, "School", "School attributes updated", c)
+	return c.Status(fiber.StatusOK).JSON(school)
 }
 
 // PostJob allows an institution to post a new job.
@@ -266,7 +246,7 @@ func (h *InstitutionHandler) PostJob(c *fiber.Ctx) error {
 	}
 	if institutionProfile.SchoolID == nil || *institutionProfile.SchoolID == 0 {
 		LogUserAction(h.db, actorUserID, "INST_JOB_POST_FAIL_NO_SCHOOL", 0, "Job", "Institution has no school", c)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Institution must have a selected/created school to post jobs."})
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Institution must have an approved school claim to post jobs."})
 	}
 
 	req := new(JobRequest)
@@ -493,11 +473,101 @@ func (h *InstitutionHandler) GetJobApplicants(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// ApplicationStatusRequest for changing the status of a job application
+type ApplicationStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending viewed shortlisted rejected hired"`
+}
+
+// UpdateApplicationStatus changes the status of an application to one of the institution's jobs.
+// @Summary Update job application status
+// @Description Changes the status of an application (pending, viewed, shortlisted, rejected or hired) and notifies the applicant
+// @Tags institution,jobs
+// @Accept json
+// @Produce json
+// @Param job_id path int true "Job ID"
+// @Param application_id path int true "Application ID"
+// @Param status body ApplicationStatusRequest true "New application status"
+// @Success 200 {object} models.JobApplication "Updated job application"
+// @Failure 400 {object} map[string]string "Bad request, invalid ID or unknown status"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Job or application not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/institution/jobs/{job_id}/applicants/{application_id}/status [put]
+func (h *InstitutionHandler) UpdateApplicationStatus(c *fiber.Ctx) error {
+	actorUserID, _ := c.Locals("user_id").(uint)
+	jobID, err := strconv.ParseUint(c.Params("job_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid job ID format"})
+	}
+	applicationID, err := strconv.ParseUint(c.Params("application_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid application ID format"})
+	}
+
+	req := new(ApplicationStatusRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+	}
+	switch req.Status {
+	case "pending", "viewed", "shortlisted", "rejected", "hired":
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Status must be one of pending, viewed, shortlisted, rejected or hired"})
+	}
+
+	var institutionProfile models.InstitutionProfile
+	if err := h.db.Where("user_id = ?", actorUserID).First(&institutionProfile).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Institution profile not found."})
+	}
+	var job models.Job
+	if err := h.db.Where("id = ? AND institution_profile_id = ?", uint(jobID), institutionProfile.ID).First(&job).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Job not found or access denied."})
+	}
+	var application models.JobApplication
+	if err := h.db.Preload("Educator").Where("id = ? AND job_id = ?", uint(applicationID), job.ID).First(&application).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Application not found."})
+	}
+
+	if application.Status == req.Status {
+		return c.Status(fiber.StatusOK).JSON(application)
+	}
+	previousStatus := application.Status
+	if err := h.db.Model(&application).Update("status", req.Status).Error; err != nil {
+		LogUserAction(h.db, actorUserID, "INST_APPLICATION_STATUS_FAIL_DB", application.ID, "JobApplication", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update application status: " + err.Error()})
+	}
+
+	h.notifier.Notify(models.Notification{
+		UserID:   application.Educator.UserID,
+		Category: models.NotificationCategoryJobApplications,
+		Type:     models.NotificationTypeApplicationStatusChanged,
+		Title:    fmt.Sprintf("Your application for %s is now %s", job.Title, req.Status),
+		Body:     fmt.Sprintf("%s updated your application from %s to %s.", institutionProfile.InstitutionName, previousStatus, req.Status),
+		Payload:  map[string]interface{}{"job_id": job.ID, "application_id": application.ID, "status": req.Status, "previous_status": previousStatus},
+		DeepLink: "/educator/jobs/applied",
+	})
+
+	LogUserAction(h.db, actorUserID, "INST_APPLICATION_STATUS_SUCCESS", application.ID, "JobApplication", fmt.Sprintf("Status changed from %s to %s", previousStatus, req.Status), c)
+	// Keep the institution's other open sessions in sync
+	h.notifier.Publish(InstitutionApplicantsTopic(institutionProfile.ID), "application_status_changed", map[string]interface{}{
+		"application_id":  application.ID,
+		"job_id":          job.ID,
+		"status":          req.Status,
+		"previous_status": previousStatus,
+	})
+	return c.Status(fiber.StatusOK).JSON(application)
+}
+
 // GetAllJobs retrieves all active jobs in the system.
 // @Summary Get all jobs
-// @Description Retrieves all active job postings in the system
+// @Description Retrieves all active job postings in the system with the name and verified badge of the posting institution
 // @Tags jobs
 // @Produce json
+// @Param q query string false "Text in the title or description"
+// @Param location query string false "Part of the location"
+// @Param employment_type query string false "Employment type, e.g. Full-time"
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(10)
 // @Success 200 {array} models.Job "List of all active jobs"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/v1/jobs [get]
@@ -506,16 +576,21 @@ func (h *InstitutionHandler) GetAllJobs(c *fiber.Ctx) error {
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
 	offset := (page - 1) * limit
+	params := jobSearchParamsFromQuery(c)
 
 	var jobs []models.Job
-	query := h.db.Where("is_active = ?", true).Order("created_at desc").Offset(offset).Limit(limit)
+	query := params.apply(h.db).Order("created_at desc").Offset(offset).Limit(limit).
+		// Institution name and verified badge only
+		Preload("InstitutionProfile", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "institution_name", "school_id", "is_verified", "verified_at")
+		})
 
 	if err := query.Find(&jobs).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve jobs: " + err.Error()})
 	}
 
 	var total int64
-	h.db.Model(&models.Job{}).Where("is_active = ?", true).Count(&total)
+	params.apply(h.db.Model(&models.Job{})).Count(&total)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"data": jobs,
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// JobSearchParams are the filters of a job search
+type JobSearchParams struct {
+	Query          string `json:"q,omitempty"`               // Free text over title and description
+	Location       string `json:"location,omitempty"`        // Case-insensitive part of the location
+	EmploymentType string `json:"employment_type,omitempty"` // Case-insensitive exact employment type, e.g. Full-time
+}
+
+// jobSearchParamsFromQuery reads the job search filters from the query string
+func jobSearchParamsFromQuery(c *fiber.Ctx) JobSearchParams {
+	params := JobSearchParams{
+		Query:          c.Query("q"),
+		Location:       c.Query("location"),
+		EmploymentType: c.Query("employment_type"),
+	}
+	params.normalize()
+	return params
+}
+
+// normalize trims the filters
+func (p *JobSearchParams) normalize() {
+	p.Query = strings.TrimSpace(p.Query)
+	p.Location = strings.TrimSpace(p.Location)
+	p.EmploymentType = strings.TrimSpace(p.EmploymentType)
+}
+
+// apply adds the filters to a query of active jobs
+func (p JobSearchParams) apply(query *gorm.DB) *gorm.DB {
+	query = query.Where("jobs.is_active = ?", true)
+	if p.Query != "" {
+		pattern := "%" + escapeLike(p.Query) + "%"
+		query = query.Where("(jobs.title ILIKE ? OR jobs.description ILIKE ?)", pattern, pattern)
+	}
+	if p.Location != "" {
+		query = query.Where("jobs.location ILIKE ?", "%"+escapeLike(p.Location)+"%")
+	}
+	if p.EmploymentType != "" {
+		query = query.Where("LOWER(jobs.employment_type) = LOWER(?)", p.EmploymentType)
+	}
+	return query
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
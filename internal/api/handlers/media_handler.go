@@ -0,0 +1,506 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"mime"
+	"mwc_backend/config"
+	"mwc_backend/internal/imaging"
+	"mwc_backend/internal/models"
+	"mwc_backend/internal/storage"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxGalleryMedia is the largest number of gallery images of a school or institution
+const maxGalleryMedia = 30
+
+// mediaSizes are the sizes each kind of image is stored in, as the longest side in pixels
+var mediaSizes = map[models.MediaKind]map[string]int{
+	models.MediaLogo:    {"large": 512, "medium": 256, "thumb": 128},
+	models.MediaCover:   {"large": 2400, "medium": 1280, "thumb": 480},
+	models.MediaGallery: {"large": 2048, "medium": 1024, "thumb": 320},
+}
+
+// mediaSizeNames lists the sizes largest first, as each size is scaled from the one before
+var mediaSizeNames = []string{"large", "medium", "thumb"}
+
+// MediaHandler handles logo, cover and gallery images of schools and institutions
+type MediaHandler struct {
+	db    *gorm.DB
+	cfg   *config.Config
+	store storage.Storage // Nil when the storage backend could not be initialized
+}
+
+// NewMediaHandler creates a new MediaHandler. store may be nil, in which case images cannot be uploaded.
+func NewMediaHandler(db *gorm.DB, cfg *config.Config, store storage.Storage) *MediaHandler {
+	return &MediaHandler{db: db, cfg: cfg, store: store}
+}
+
+// UpdateMediaRequest changes the caption or gallery position of an image
+type UpdateMediaRequest struct {
+	Caption  *string `json:"caption,omitempty" validate:"omitempty,max=255"`
+	Position *int    `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+// mediaOwner is the school or the institution an image belongs to
+type mediaOwner struct {
+	schoolID             *uint
+	institutionProfileID *uint
+}
+
+func (o mediaOwner) scope(db *gorm.DB) *gorm.DB {
+	if o.schoolID != nil {
+		return db.Where("school_id = ?", *o.schoolID)
+	}
+	return db.Where("institution_profile_id = ?", *o.institutionProfileID)
+}
+
+func (o mediaOwner) keyPrefix() string {
+	if o.schoolID != nil {
+		return fmt.Sprintf("media/schools/%d", *o.schoolID)
+	}
+	return fmt.Sprintf("media/institutions/%d", *o.institutionProfileID)
+}
+
+// upload resizes an uploaded image and stores it for the owner. A new logo or cover replaces the previous one.
+func (h *MediaHandler) upload(c *fiber.Ctx, owner mediaOwner, userID uint) error {
+	if h.store == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "File storage is not available"})
+	}
+	kind := models.MediaKind(c.FormValue("kind"))
+	if _, ok := mediaSizes[kind]; !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "kind must be logo, cover or gallery"})
+	}
+	caption := strings.TrimSpace(c.FormValue("caption"))
+	if len(caption) > 255 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "caption must be at most 255 characters long"})
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "An image must be uploaded in the 'file' form field"})
+	}
+	maxSize := int64(h.cfg.MediaMaxSizeMB) * 1024 * 1024
+	if fileHeader.Size > maxSize {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": fmt.Sprintf("Image is larger than %d MB", h.cfg.MediaMaxSizeMB)})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot read uploaded file: " + err.Error()})
+	}
+	defer file.Close()
+	// Read at most one byte more than allowed so a wrong size header cannot bypass the limit
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot read uploaded file: " + err.Error()})
+	}
+	if int64(len(data)) > maxSize {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": fmt.Sprintf("Image is larger than %d MB", h.cfg.MediaMaxSizeMB)})
+	}
+
+	if kind == models.MediaGallery {
+		var count int64
+		owner.scope(h.db.Model(&models.Media{})).Where("kind = ?", models.MediaGallery).Count(&count)
+		if count >= maxGalleryMedia {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": fmt.Sprintf("A gallery can hold at most %d images", maxGalleryMedia)})
+		}
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		if errors.Is(err, imaging.ErrTooLarge) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Image dimensions are too large"})
+		}
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{"error": "File must be a JPEG, PNG, GIF or WebP image"})
+	}
+	variants, err := h.storeVariants(c.Context(), owner, kind, img)
+	if err != nil {
+		log.Printf("Error storing %s image from user %d: %v", kind, userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to store image"})
+	}
+
+	media := models.Media{
+		SchoolID:             owner.schoolID,
+		InstitutionProfileID: owner.institutionProfileID,
+		Kind:                 kind,
+		Caption:              caption,
+		UploadedByUserID:     userID,
+		Variants:             variants,
+	}
+	var replaced []models.Media
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if kind == models.MediaGallery {
+			if err := owner.scope(tx.Model(&models.Media{})).Where("kind = ?", kind).
+				Select("COALESCE(MAX(position), -1) + 1").Scan(&media.Position).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := owner.scope(tx).Where("kind = ?", kind).Find(&replaced).Error; err != nil {
+				return err
+			}
+			if len(replaced) > 0 {
+				if err := tx.Unscoped().Delete(&replaced).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Create(&media).Error
+	})
+	if err != nil {
+		h.deleteVariants(variants)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save image: " + err.Error()})
+	}
+	for _, old := range replaced {
+		h.deleteVariants(old.Variants)
+	}
+
+	LogUserAction(h.db, userID, "MEDIA_UPLOAD_SUCCESS", media.ID, "Media", fmt.Sprintf("%s image for %s", kind, owner.keyPrefix()), c)
+	return c.Status(fiber.StatusCreated).JSON(media)
+}
+
+// storeVariants encodes and stores every size of an image. Nothing is left stored if a size fails.
+func (h *MediaHandler) storeVariants(ctx context.Context, owner mediaOwner, kind models.MediaKind, img *image.NRGBA) (map[string]models.MediaVariant, error) {
+	id := uuid.NewString()
+	variants := make(map[string]models.MediaVariant, len(mediaSizeNames))
+	for _, name := range mediaSizeNames {
+		img = imaging.Fit(img, mediaSizes[kind][name])
+		data, contentType, extension, err := imaging.Encode(img)
+		if err != nil {
+			h.deleteVariants(variants)
+			return nil, err
+		}
+		key := fmt.Sprintf("%s/%s-%s%s", owner.keyPrefix(), id, name, extension)
+		if err := h.store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+			h.deleteVariants(variants)
+			return nil, err
+		}
+		variants[name] = models.MediaVariant{Key: key, Width: img.Bounds().Dx(), Height: img.Bounds().Dy(), ContentType: contentType}
+	}
+	return variants, nil
+}
+
+// deleteVariants removes the stored sizes of an image. Failures are logged.
+func (h *MediaHandler) deleteVariants(variants map[string]models.MediaVariant) {
+	for _, variant := range variants {
+		if err := h.store.Delete(context.Background(), variant.Key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Error deleting image %s: %v", variant.Key, err)
+		}
+	}
+}
+
+// findMedia loads an image of the owner from the media_id path parameter
+func (h *MediaHandler) findMedia(c *fiber.Ctx, owner mediaOwner) (*models.Media, *fiber.Error) {
+	mediaID, err := strconv.ParseUint(c.Params("media_id"), 10, 32)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid media ID format")
+	}
+	var media models.Media
+	if err := owner.scope(h.db).First(&media, uint(mediaID)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Image not found")
+		}
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Database error: "+err.Error())
+	}
+	return &media, nil
+}
+
+// delete removes an image of the owner and its stored sizes
+func (h *MediaHandler) delete(c *fiber.Ctx, owner mediaOwner, userID uint) error {
+	media, lookupErr := h.findMedia(c, owner)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	if err := h.db.Unscoped().Delete(media).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete image: " + err.Error()})
+	}
+	if h.store != nil {
+		h.deleteVariants(media.Variants)
+	}
+	LogUserAction(h.db, userID, "MEDIA_DELETE_SUCCESS", media.ID, "Media", fmt.Sprintf("%s image of %s", media.Kind, owner.keyPrefix()), c)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Image deleted successfully"})
+}
+
+// institutionOwner returns the logged-in user's institution as the owner of images
+func (h *MediaHandler) institutionOwner(userID uint) (mediaOwner, *fiber.Error) {
+	var profile models.InstitutionProfile
+	if err := h.db.Where("user_id = ?", userID).First(&profile).Error; err != nil {
+		return mediaOwner{}, fiber.NewError(fiber.StatusNotFound, "Institution profile not found.")
+	}
+	return mediaOwner{institutionProfileID: &profile.ID}, nil
+}
+
+// verifiedSchoolOwner returns the school of the logged-in user's verified institution as the owner of images
+func (h *MediaHandler) verifiedSchoolOwner(userID uint) (mediaOwner, *fiber.Error) {
+	school, lookupErr := verifiedInstitutionSchool(h.db, userID)
+	if lookupErr != nil {
+		return mediaOwner{}, lookupErr
+	}
+	return mediaOwner{schoolID: &school.ID}, nil
+}
+
+// adminSchoolOwner returns the school in the id path parameter as the owner of images
+func (h *MediaHandler) adminSchoolOwner(c *fiber.Ctx) (mediaOwner, *fiber.Error) {
+	schoolID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return mediaOwner{}, fiber.NewError(fiber.StatusBadRequest, "Invalid school ID format")
+	}
+	var school models.School
+	if err := h.db.Select("id").First(&school, uint(schoolID)).Error; err != nil {
+		return mediaOwner{}, fiber.NewError(fiber.StatusNotFound, "School not found")
+	}
+	return mediaOwner{schoolID: &school.ID}, nil
+}
+
+// UploadSchoolMedia uploads an image of the institution's school
+// @Summary Upload a school image
+// @Description Uploads a logo, cover or gallery image of the institution's school as multipart form field `file` (JPEG, PNG, GIF or WebP of at most MEDIA_MAX_SIZE_MB). The image is turned upright, stripped of EXIF data and stored in large, medium and thumb sizes, returned as URLs. A new logo or cover replaces the previous one; a gallery holds at most 30 images. Only institutions whose school claim was approved can upload images.
+// @Tags institution,schools
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Image"
+// @Param kind formData string true "logo, cover or gallery"
+// @Param caption formData string false "Caption"
+// @Success 201 {object} models.Media "Stored image with its URLs"
+// @Failure 400 {object} map[string]string "Missing file or invalid kind"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Institution is not verified"
+// @Failure 404 {object} map[string]string "Institution profile or school not found"
+// @Failure 409 {object} map[string]string "Gallery is full"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 415 {object} map[string]string "Not a supported image"
+// @Failure 422 {object} map[string]string "Image dimensions too large"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "File storage unavailable"
+// @Security BearerAuth
+// @Router /api/v1/institution/school/media [post]
+func (h *MediaHandler) UploadSchoolMedia(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	owner, lookupErr := h.verifiedSchoolOwner(userID)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	return h.upload(c, owner, userID)
+}
+
+// UpdateSchoolMedia changes the caption or gallery position of an image of the institution's school
+// @Summary Update a school image
+// @Description Changes the caption of an image of the institution's school, or its position in the gallery
+// @Tags institution,schools
+// @Accept json
+// @Produce json
+// @Param media_id path int true "Media ID"
+// @Param media body UpdateMediaRequest true "New caption or position"
+// @Success 200 {object} models.Media "Updated image"
+// @Failure 400 {object} map[string]string "Invalid media ID or request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Institution is not verified"
+// @Failure 404 {object} map[string]string "Image not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/institution/school/media/{media_id} [put]
+func (h *MediaHandler) UpdateSchoolMedia(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	owner, lookupErr := h.verifiedSchoolOwner(userID)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	media, lookupErr := h.findMedia(c, owner)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+
+	var req UpdateMediaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+	}
+	if fieldErrors := validationErrors(req); len(fieldErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": validationErrorsMessage(fieldErrors), "fields": fieldErrors})
+	}
+	if req.Caption != nil {
+		media.Caption = strings.TrimSpace(*req.Caption)
+	}
+	if req.Position != nil {
+		media.Position = *req.Position
+	}
+	if err := h.db.Model(media).Select("caption", "position").Updates(media).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update image: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(media)
+}
+
+// DeleteSchoolMedia deletes an image of the institution's school
+// @Summary Delete a school image
+// @Description Deletes an image of the institution's school with all its sizes
+// @Tags institution,schools
+// @Produce json
+// @Param media_id path int true "Media ID"
+// @Success 200 {object} map[string]string "Image deleted"
+// @Failure 400 {object} map[string]string "Invalid media ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Institution is not verified"
+// @Failure 404 {object} map[string]string "Image not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/institution/school/media/{media_id} [delete]
+func (h *MediaHandler) DeleteSchoolMedia(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	owner, lookupErr := h.verifiedSchoolOwner(userID)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	return h.delete(c, owner, userID)
+}
+
+// UploadInstitutionMedia uploads an image of the logged-in institution
+// @Summary Upload an institution image
+// @Description Uploads a logo, cover or gallery image of the institution itself, processed like school images
+// @Tags institution,profile
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Image"
+// @Param kind formData string true "logo, cover or gallery"
+// @Param caption formData string false "Caption"
+// @Success 201 {object} models.Media "Stored image with its URLs"
+// @Failure 400 {object} map[string]string "Missing file or invalid kind"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Institution profile not found"
+// @Failure 409 {object} map[string]string "Gallery is full"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 415 {object} map[string]string "Not a supported image"
+// @Failure 422 {object} map[string]string "Image dimensions too large"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "File storage unavailable"
+// @Security BearerAuth
+// @Router /api/v1/institution/media [post]
+func (h *MediaHandler) UploadInstitutionMedia(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	owner, lookupErr := h.institutionOwner(userID)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	return h.upload(c, owner, userID)
+}
+
+// DeleteInstitutionMedia deletes an image of the logged-in institution
+// @Summary Delete an institution image
+// @Description Deletes an image of the institution with all its sizes
+// @Tags institution,profile
+// @Produce json
+// @Param media_id path int true "Media ID"
+// @Success 200 {object} map[string]string "Image deleted"
+// @Failure 400 {object} map[string]string "Invalid media ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Institution profile or image not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/institution/media/{media_id} [delete]
+func (h *MediaHandler) DeleteInstitutionMedia(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	owner, lookupErr := h.institutionOwner(userID)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	return h.delete(c, owner, userID)
+}
+
+// AdminUploadSchoolMedia uploads an image of any school (admin only)
+// @Summary Upload a school image
+// @Description Uploads a logo, cover or gallery image of a school, processed like images uploaded by institutions
+// @Tags admin,schools
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "School ID"
+// @Param file formData file true "Image"
+// @Param kind formData string true "logo, cover or gallery"
+// @Param caption formData string false "Caption"
+// @Success 201 {object} models.Media "Stored image with its URLs"
+// @Failure 400 {object} map[string]string "Invalid school ID, missing file or invalid kind"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "School not found"
+// @Failure 409 {object} map[string]string "Gallery is full"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 415 {object} map[string]string "Not a supported image"
+// @Failure 422 {object} map[string]string "Image dimensions too large"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "File storage unavailable"
+// @Security BearerAuth
+// @Router /api/v1/admin/schools/{id}/media [post]
+func (h *MediaHandler) AdminUploadSchoolMedia(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	owner, lookupErr := h.adminSchoolOwner(c)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	return h.upload(c, owner, userID)
+}
+
+// AdminDeleteSchoolMedia deletes an image of any school (admin only)
+// @Summary Delete a school image
+// @Description Deletes an image of a school with all its sizes
+// @Tags admin,schools
+// @Produce json
+// @Param id path int true "School ID"
+// @Param media_id path int true "Media ID"
+// @Success 200 {object} map[string]string "Image deleted"
+// @Failure 400 {object} map[string]string "Invalid school or media ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "School or image not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/schools/{id}/media/{media_id} [delete]
+func (h *MediaHandler) AdminDeleteSchoolMedia(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	owner, lookupErr := h.adminSchoolOwner(c)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	return h.delete(c, owner, userID)
+}
+
+// ServeMedia streams a stored image. Keys are unique per upload, so responses can be cached forever.
+// @Summary Get an image
+// @Description Serves the images whose URLs are returned with schools and institutions when MEDIA_BASE_URL is not set to a CDN
+// @Tags schools
+// @Produce image/jpeg,image/png
+// @Param path path string true "Image path"
+// @Success 200 {file} file "Image"
+// @Failure 404 {object} map[string]string "Image not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "File storage unavailable"
+// @Router /media/{path} [get]
+func (h *MediaHandler) ServeMedia(c *fiber.Ctx) error {
+	if h.store == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "File storage is not available"})
+	}
+	// Only keys below media/ are served, so other stored files cannot be reached with ../
+	key := strings.TrimPrefix(path.Clean("/media/"+c.Params("*")), "/")
+	if !strings.HasPrefix(key, "media/") {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Image not found"})
+	}
+
+	reader, err := h.store.Get(c.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Image not found"})
+		}
+		log.Printf("Error reading image %s: %v", key, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read image"})
+	}
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		c.Set(fiber.HeaderContentType, contentType)
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+	// fasthttp closes the reader once the body has been sent
+	return c.Status(fiber.StatusOK).SendStream(reader)
+}
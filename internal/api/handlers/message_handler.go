@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"fmt"
+	"mwc_backend/internal/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// MessageHandler handles direct messages between users of any role
+type MessageHandler struct {
+	db        *gorm.DB
+	messenger *Messenger
+}
+
+// NewMessageHandler creates a new MessageHandler
+func NewMessageHandler(db *gorm.DB, messenger *Messenger) *MessageHandler {
+	return &MessageHandler{db: db, messenger: messenger}
+}
+
+// SendMessage sends a direct message to another user
+// @Summary Send a message
+// @Description Sends a direct message to another user. Which roles may start a conversation with which is configured with MESSAGING_RULES (e.g. parents to schools, educators to institutions, institutions to their applicants); replying to a user who wrote first is always allowed. Messages to the same recipient are rate limited. Files uploaded with the attachment endpoint are sent by listing their IDs in attachment_ids; the content may then be empty.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param recipient_id path int true "Recipient User ID"
+// @Param message body MessageRequest true "Message content"
+// @Success 201 {object} models.Message "Message sent successfully"
+// @Failure 400 {object} map[string]string "Bad request or invalid recipient ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Messaging this user is not allowed"
+// @Failure 404 {object} map[string]string "Recipient not found"
+// @Failure 429 {object} map[string]string "Too many messages to this recipient"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/messages/send/{recipient_id} [post]
+func (h *MessageHandler) SendMessage(c *fiber.Ctx) error {
+	return sendMessageFromRequest(c, h.db, h.messenger, "MSG_SEND")
+}
+
+// sendMessageFromRequest sends the MessageRequest in the body to the recipient_id path parameter.
+// actionPrefix namespaces the action log entries of the calling route.
+func sendMessageFromRequest(c *fiber.Ctx, db *gorm.DB, messenger *Messenger, actionPrefix string) error {
+	senderID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+	recipientID, err := strconv.ParseUint(c.Params("recipient_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid recipient ID format"})
+	}
+
+	req := new(MessageRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+	}
+
+	message, _, err := messenger.Send(c.Context(), senderID, uint(recipientID), req.Content, req.AttachmentIDs)
+	if err != nil {
+		status, errMsg := messageErrorStatus(err)
+		LogUserAction(db, senderID, actionPrefix+"_FAIL", uint(recipientID), "User", err.Error(), c)
+		return c.Status(status).JSON(fiber.Map{"error": errMsg})
+	}
+
+	LogUserAction(db, senderID, actionPrefix+"_SUCCESS", message.ID, "Message", fmt.Sprintf("Message sent to user %d", recipientID), c)
+	return c.Status(fiber.StatusCreated).JSON(message)
+}
+
+// messageSearchRow is a full-text search hit before the message itself is loaded
+type messageSearchRow struct {
+	ID       uint
+	Headline string
+	Rank     float64
+}
+
+// SearchMessages searches the current user's messages
+// @Summary Search messages
+// @Description Full-text search over the messages of conversations the current user is part of. The query supports web search syntax: quoted phrases, `or` and `-` to exclude words. Words are matched exactly, without stemming. Results are ordered by relevance, or by date with sort=recent. `headline` holds HTML-escaped excerpts with matches wrapped in `<mark>` tags.
+// @Tags messages
+// @Produce json
+// @Param q query string true "Search query"
+// @Param participant_id query int false "Only messages exchanged with this user"
+// @Param conversation_id query int false "Only messages of this conversation"
+// @Param from query string false "Only messages sent on or after this date (YYYY-MM-DD or RFC3339)"
+// @Param to query string false "Only messages sent on or before this date (YYYY-MM-DD or RFC3339)"
+// @Param sort query string false "Order by relevance or recent" default(relevance)
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(20)
+// @Success 200 {object} map[string]interface{} "Matching messages with highlights and pagination metadata"
+// @Failure 400 {object} map[string]string "Missing query or invalid filter"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/messages/search [get]
+func (h *MessageHandler) SearchMessages(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+
+	q := strings.TrimSpace(c.Query("q"))
+	if len(q) < 2 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Search query must be at least 2 characters"})
+	}
+	if len(q) > 200 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Search query cannot be longer than 200 characters"})
+	}
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// The expressions must match idx_messages_content_search
+	const document = "to_tsvector('" + models.MessageSearchConfig + "', messages.content)"
+	const tsQuery = "websearch_to_tsquery('" + models.MessageSearchConfig + "', ?)"
+	query := h.db.Model(&models.Message{}).
+		Where("(messages.sender_id = ? OR messages.recipient_id = ?)", userID, userID).
+		Where(document+" @@ "+tsQuery, q)
+
+	if participantParam := c.Query("participant_id"); participantParam != "" {
+		participantID, err := strconv.ParseUint(participantParam, 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid participant_id"})
+		}
+		query = query.Where("(messages.sender_id = ? OR messages.recipient_id = ?)", uint(participantID), uint(participantID))
+	}
+	if conversationParam := c.Query("conversation_id"); conversationParam != "" {
+		conversationID, err := strconv.ParseUint(conversationParam, 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid conversation_id"})
+		}
+		query = query.Where("messages.conversation_id = ?", uint(conversationID))
+	}
+	if fromParam := c.Query("from"); fromParam != "" {
+		from, err := parseDateParam(fromParam, false)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid from date, use YYYY-MM-DD or RFC3339"})
+		}
+		query = query.Where("messages.sent_at >= ?", from)
+	}
+	if toParam := c.Query("to"); toParam != "" {
+		to, err := parseDateParam(toParam, true)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid to date, use YYYY-MM-DD or RFC3339"})
+		}
+		query = query.Where("messages.sent_at <= ?", to)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to search messages: " + err.Error()})
+	}
+
+	order := "rank DESC, messages.sent_at DESC"
+	if c.Query("sort") == "recent" {
+		order = "messages.sent_at DESC"
+	}
+	// Content is HTML-escaped before highlighting so only the <mark> tags are markup. The parser skips
+	// the resulting entities, so the escaped text still matches the query.
+	escaped := "replace(replace(replace(messages.content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;')"
+	var rows []messageSearchRow
+	if err := query.Select(
+		"messages.id, "+
+			"ts_headline('"+models.MessageSearchConfig+"', "+escaped+", "+tsQuery+", 'StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2') AS headline, "+
+			"ts_rank("+document+", "+tsQuery+") AS rank", q, q).
+		Order(order).Offset((page - 1) * limit).Limit(limit).Scan(&rows).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to search messages: " + err.Error()})
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	var messages []models.Message
+	if len(ids) > 0 {
+		if err := h.db.Preload("Sender", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, first_name, last_name, role")
+		}).Preload("Recipient", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, first_name, last_name, role")
+		}).Preload("Attachments").Where("id IN ?", ids).Find(&messages).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load messages: " + err.Error()})
+		}
+	}
+	byID := make(map[uint]models.Message, len(messages))
+	for _, message := range messages {
+		byID[message.ID] = message
+	}
+
+	results := make([]fiber.Map, 0, len(rows))
+	for _, row := range rows {
+		message, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, fiber.Map{
+			"message":  message,
+			"headline": row.Headline,
+			"rank":     row.Rank,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": results,
+		"meta": fiber.Map{
+			"total":     total,
+			"page":      page,
+			"limit":     limit,
+			"last_page": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// parseDateParam parses a YYYY-MM-DD or RFC3339 query parameter. A bare date at the end of a range
+// includes the whole day.
+func parseDateParam(value string, endOfDay bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return parsed, err
+	}
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+	return parsed, nil
+}
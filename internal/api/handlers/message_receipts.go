@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"log"
+	"mwc_backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// wsMaxReceiptBatch is the largest number of messages a client may acknowledge in one receipt
+const wsMaxReceiptBatch = 100
+
+// markMessagesDelivered records that the recipient's client received the messages. Messages that are
+// not addressed to the recipient or were already acknowledged are ignored; the newly delivered ones are returned.
+func markMessagesDelivered(db *gorm.DB, recipientID uint, messageIDs []uint) ([]models.Message, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	var messages []models.Message
+	if err := db.Where("id IN ? AND recipient_id = ? AND delivered_at IS NULL", messageIDs, recipientID).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	ids := make([]uint, 0, len(messages))
+	for i := range messages {
+		messages[i].DeliveredAt = &now
+		ids = append(ids, messages[i].ID)
+	}
+	if err := db.Model(&models.Message{}).Where("id IN ? AND delivered_at IS NULL", ids).Update("delivered_at", now).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// markMessageRead marks a message addressed to the recipient as read, and as delivered if no client
+// acknowledged it before. It reports whether the message was newly read.
+func markMessageRead(db *gorm.DB, recipientID uint, messageID uint) (models.Message, bool, error) {
+	var message models.Message
+	if err := db.Where("id = ? AND recipient_id = ?", messageID, recipientID).First(&message).Error; err != nil {
+		return message, false, err
+	}
+	if message.IsRead {
+		return message, false, nil
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"is_read": true, "read_at": now}
+	message.IsRead = true
+	message.ReadAt = &now
+	if message.DeliveredAt == nil {
+		updates["delivered_at"] = now
+		message.DeliveredAt = &now
+	}
+	if err := db.Model(&models.Message{}).Where("id = ?", message.ID).Updates(updates).Error; err != nil {
+		return message, false, err
+	}
+	return message, true, nil
+}
+
+// pushMessageReceipt tells the sender's open clients that their message was delivered or read.
+// receiptType is "delivered" or "read".
+func (n *Notifier) pushMessageReceipt(message models.Message, receiptType string) {
+	if n == nil || n.pusher == nil {
+		return
+	}
+	payload := map[string]interface{}{
+		"message_id":   message.ID,
+		"recipient_id": message.RecipientID,
+		"delivered_at": message.DeliveredAt,
+	}
+	if receiptType == "read" {
+		payload["read_at"] = message.ReadAt
+	}
+	n.pusher.SendNotification(message.SenderID, receiptType, payload)
+}
+
+// handleTyping forwards a typing indicator to the recipient's open clients. Nothing is persisted.
+func (h *WebSocketHandler) handleTyping(senderID uint, payload interface{}) {
+	payloadMap, ok := payload.(map[string]interface{})
+	if !ok {
+		log.Printf("WebSocket: Invalid typing payload format")
+		return
+	}
+	recipientIDFloat, ok := payloadMap["recipient_id"].(float64)
+	if !ok || recipientIDFloat <= 0 || uint(recipientIDFloat) == senderID {
+		log.Printf("WebSocket: Missing or invalid recipient_id in typing payload")
+		return
+	}
+	isTyping, ok := payloadMap["is_typing"].(bool)
+	if !ok {
+		isTyping = true // A bare typing event means the user started typing
+	}
+
+	recipientID := uint(recipientIDFloat)
+	if h.OnlineDeviceCount(recipientID) == 0 {
+		return
+	}
+	if blocked, err := usersBlocked(h.db, senderID, recipientID); err != nil || blocked {
+		return
+	}
+	h.deliver(recipientID, WebSocketMessage{
+		Type: "typing",
+		Payload: map[string]interface{}{
+			"sender_id": senderID,
+			"is_typing": isTyping,
+			"time":      time.Now(),
+		},
+	})
+}
+
+// handleDelivered records a delivery acknowledgement sent by a recipient client and tells the senders
+func (h *WebSocketHandler) handleDelivered(recipientID uint, payload interface{}) {
+	messageIDs := payloadMessageIDs(payload)
+	if len(messageIDs) == 0 {
+		log.Printf("WebSocket: Missing or invalid message_id in delivered payload")
+		return
+	}
+
+	messages, err := markMessagesDelivered(h.db, recipientID, messageIDs)
+	if err != nil {
+		log.Printf("WebSocket: Error recording delivery of messages %v for user %d: %v", messageIDs, recipientID, err)
+		return
+	}
+	for _, message := range messages {
+		h.notifier.pushMessageReceipt(message, "delivered")
+	}
+}
+
+// handleRead marks messages as read on behalf of the recipient and tells the senders
+func (h *WebSocketHandler) handleRead(recipientID uint, payload interface{}) {
+	messageIDs := payloadMessageIDs(payload)
+	if len(messageIDs) == 0 {
+		log.Printf("WebSocket: Missing or invalid message_id in read payload")
+		return
+	}
+
+	for _, messageID := range messageIDs {
+		message, newlyRead, err := markMessageRead(h.db, recipientID, messageID)
+		if err != nil {
+			if err != gorm.ErrRecordNotFound {
+				log.Printf("WebSocket: Error marking message %d as read for user %d: %v", messageID, recipientID, err)
+			}
+			continue
+		}
+		if newlyRead {
+			LogUserAction(h.db, recipientID, "WS_MSG_READ_SUCCESS", message.ID, "Message", "Message marked as read over WebSocket", nil)
+			h.notifier.pushMessageReceipt(message, "read")
+		}
+	}
+}
+
+// payloadMessageIDs reads a single message_id or a message_ids array from a client payload
+func payloadMessageIDs(payload interface{}) []uint {
+	payloadMap, ok := payload.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var ids []uint
+	if id, ok := payloadMap["message_id"].(float64); ok && id > 0 {
+		ids = append(ids, uint(id))
+	}
+	if list, ok := payloadMap["message_ids"].([]interface{}); ok {
+		for _, value := range list {
+			if id, ok := value.(float64); ok && id > 0 {
+				ids = append(ids, uint(id))
+			}
+		}
+	}
+	if len(ids) > wsMaxReceiptBatch {
+		ids = ids[:wsMaxReceiptBatch]
+	}
+	return ids
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"mwc_backend/config"
+	"mwc_backend/internal/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// MessageReportHandler handles abuse reports on messages and their moderation
+type MessageReportHandler struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+// NewMessageReportHandler creates a new MessageReportHandler
+func NewMessageReportHandler(db *gorm.DB, cfg *config.Config) *MessageReportHandler {
+	return &MessageReportHandler{db: db, cfg: cfg}
+}
+
+// ReportMessageRequest is the request body for reporting a message
+type ReportMessageRequest struct {
+	Reason  models.MessageReportReason `json:"reason" validate:"required,oneof=spam harassment inappropriate scam other"`
+	Details string                     `json:"details"`
+	Block   bool                       `json:"block"` // Also block the sender
+}
+
+// ReviewMessageReportRequest is the request body for reviewing a message report
+type ReviewMessageReportRequest struct {
+	Status      models.MessageReportStatus `json:"status" validate:"required,oneof=dismissed actioned"`
+	Notes       string                     `json:"notes"`
+	SuspendUser *bool                      `json:"suspend_user,omitempty"` // true suspends the reported user, false reinstates them
+}
+
+func validReportReason(reason models.MessageReportReason) bool {
+	switch reason {
+	case models.ReportReasonSpam, models.ReportReasonHarassment, models.ReportReasonInappropriate,
+		models.ReportReasonScam, models.ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+// ReportMessage reports a received message to the moderators
+// @Summary Report a message
+// @Description Reports a message the current user received as spam, harassment, inappropriate, a scam or other abuse. The report opens a moderation case for admins; with `block` the sender is blocked as well. A sender reported by REPORT_SUSPEND_THRESHOLD different users within REPORT_SUSPEND_WINDOW_HOURS is suspended automatically until an admin reviews the reports.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param message_id path int true "Message ID"
+// @Param report body ReportMessageRequest true "Report reason and details"
+// @Success 201 {object} models.MessageReport "Report created"
+// @Failure 400 {object} map[string]string "Bad request or invalid reason"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Message not found"
+// @Failure 409 {object} map[string]string "Message already reported"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/messages/{message_id}/report [post]
+func (h *MessageReportHandler) ReportMessage(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+	messageID, err := strconv.ParseUint(c.Params("message_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid message ID format"})
+	}
+
+	var req ReportMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+	req.Reason = models.MessageReportReason(strings.ToLower(strings.TrimSpace(string(req.Reason))))
+	if !validReportReason(req.Reason) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Reason must be one of spam, harassment, inappropriate, scam or other"})
+	}
+	if len(req.Details) > 2000 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Details cannot be longer than 2000 characters"})
+	}
+
+	// Only the recipient can report a message
+	var message models.Message
+	if err := h.db.Where("id = ? AND recipient_id = ?", uint(messageID), userID).First(&message).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Message not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error: " + err.Error()})
+	}
+
+	var existing int64
+	h.db.Model(&models.MessageReport{}).Where("message_id = ? AND reporter_id = ?", message.ID, userID).Count(&existing)
+	if existing > 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "You have already reported this message"})
+	}
+
+	report := models.MessageReport{
+		MessageID:      message.ID,
+		ReporterID:     userID,
+		ReportedUserID: message.SenderID,
+		Reason:         req.Reason,
+		Details:        strings.TrimSpace(req.Details),
+		MessageContent: message.Content,
+		Status:         models.MessageReportPending,
+	}
+	if err := h.db.Create(&report).Error; err != nil {
+		LogUserAction(h.db, userID, "MSG_REPORT_FAIL_DB", message.ID, "Message", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to report message: " + err.Error()})
+	}
+	LogUserAction(h.db, userID, "MSG_REPORT_SUCCESS", report.ID, "MessageReport", fmt.Sprintf("Message %d from user %d reported as %s", message.ID, message.SenderID, report.Reason), c)
+
+	if req.Block {
+		if err := blockUser(h.db, userID, message.SenderID); err != nil {
+			log.Printf("Error blocking user %d for reporter %d: %v", message.SenderID, userID, err)
+		} else {
+			LogUserAction(h.db, userID, "USER_BLOCK_SUCCESS", message.SenderID, "User", fmt.Sprintf("Blocked user %d when reporting message %d", message.SenderID, message.ID), c)
+		}
+	}
+
+	h.suspendIfReportedTooOften(message.SenderID)
+	return c.Status(fiber.StatusCreated).JSON(report)
+}
+
+// suspendIfReportedTooOften deactivates a sender reported by enough different users within the window.
+// Dismissed reports do not count, and admins are never suspended automatically.
+func (h *MessageReportHandler) suspendIfReportedTooOften(reportedUserID uint) {
+	window := time.Duration(h.cfg.ReportSuspendWindowHours) * time.Hour
+	var reporters int64
+	if err := h.db.Model(&models.MessageReport{}).
+		Where("reported_user_id = ? AND status <> ? AND created_at > ?", reportedUserID, models.MessageReportDismissed, time.Now().Add(-window)).
+		Distinct("reporter_id").Count(&reporters).Error; err != nil {
+		log.Printf("Error counting reports against user %d: %v", reportedUserID, err)
+		return
+	}
+	if reporters < int64(h.cfg.ReportSuspendThreshold) {
+		return
+	}
+
+	result := h.db.Model(&models.User{}).
+		Where("id = ? AND is_active = ? AND role <> ?", reportedUserID, true, models.AdminRole).
+		Update("is_active", false)
+	if result.Error != nil {
+		log.Printf("Error suspending user %d after %d reports: %v", reportedUserID, reporters, result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("User %d suspended automatically after reports from %d users within %s", reportedUserID, reporters, window)
+		LogUserAction(h.db, 0, "USER_AUTO_SUSPENDED", reportedUserID, "User", fmt.Sprintf("Reported by %d users within %d hours", reporters, h.cfg.ReportSuspendWindowHours), nil)
+	}
+}
+
+// GetMessageReports lists message reports for moderation (admin only)
+// @Summary List message reports
+// @Description Retrieves message reports for moderation, oldest first, with the reporter, the reported user and a copy of the reported message
+// @Tags admin,messages
+// @Produce json
+// @Param status query string false "Filter by status (pending, dismissed, actioned)" default(pending)
+// @Param reported_user_id query int false "Filter by reported user"
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(20)
+// @Success 200 {object} map[string]interface{} "List of message reports with pagination metadata"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/message-reports [get]
+func (h *MessageReportHandler) GetMessageReports(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	query := h.db.Model(&models.MessageReport{})
+	if status := c.Query("status", string(models.MessageReportPending)); status != "all" {
+		query = query.Where("status = ?", status)
+	}
+	if reportedUserID, err := strconv.ParseUint(c.Query("reported_user_id"), 10, 32); err == nil {
+		query = query.Where("reported_user_id = ?", uint(reportedUserID))
+	}
+
+	var total int64
+	query.Count(&total)
+
+	selectUser := func(db *gorm.DB) *gorm.DB {
+		return db.Select("id, first_name, last_name, email, role, is_active")
+	}
+	var reports []models.MessageReport
+	if err := query.Preload("Reporter", selectUser).Preload("ReportedUser", selectUser).
+		Order("created_at asc").Offset((page - 1) * limit).Limit(limit).Find(&reports).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve message reports: " + err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": reports,
+		"meta": fiber.Map{
+			"total":     total,
+			"page":      page,
+			"limit":     limit,
+			"last_page": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// ReviewMessageReport resolves a message report (admin only)
+// @Summary Review a message report
+// @Description Marks a message report as dismissed or actioned. With `suspend_user` the reported user is suspended (true) or reinstated after an automatic suspension (false).
+// @Tags admin,messages
+// @Accept json
+// @Produce json
+// @Param report_id path int true "Report ID"
+// @Param review body ReviewMessageReportRequest true "Review outcome"
+// @Success 200 {object} map[string]interface{} "Report reviewed"
+// @Failure 400 {object} map[string]string "Bad request or invalid status"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Report not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/message-reports/{report_id} [put]
+func (h *MessageReportHandler) ReviewMessageReport(c *fiber.Ctx) error {
+	adminID, _ := c.Locals("user_id").(uint)
+	reportID, err := strconv.ParseUint(c.Params("report_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid report ID format"})
+	}
+
+	var req ReviewMessageReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+	if req.Status != models.MessageReportDismissed && req.Status != models.MessageReportActioned {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Status must be 'dismissed' or 'actioned'"})
+	}
+
+	var report models.MessageReport
+	if err := h.db.First(&report, uint(reportID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Report not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error: " + err.Error()})
+	}
+	if req.SuspendUser != nil && *req.SuspendUser && report.ReportedUserID == adminID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Admin cannot suspend themselves"})
+	}
+
+	now := time.Now()
+	report.Status = req.Status
+	report.ReviewedBy = &adminID
+	report.ReviewedAt = &now
+	report.ModeratorNotes = req.Notes
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&report).Error; err != nil {
+			return err
+		}
+		if req.SuspendUser == nil {
+			return nil
+		}
+		return tx.Model(&models.User{}).Where("id = ?", report.ReportedUserID).Update("is_active", !*req.SuspendUser).Error
+	})
+	if err != nil {
+		LogUserAction(h.db, adminID, "ADMIN_MSG_REPORT_REVIEW_FAIL", report.ID, "MessageReport", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to review report: " + err.Error()})
+	}
+
+	LogUserAction(h.db, adminID, "ADMIN_MSG_REPORT_REVIEW_SUCCESS", report.ID, "MessageReport", fmt.Sprintf("Report %s", report.Status), c)
+	if req.SuspendUser != nil {
+		action := "ADMIN_USER_SUSPENDED"
+		if !*req.SuspendUser {
+			action = "ADMIN_USER_REINSTATED"
+		}
+		LogUserAction(h.db, adminID, action, report.ReportedUserID, "User", fmt.Sprintf("After review of message report %d", report.ID), c)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": fmt.Sprintf("Report %s successfully", report.Status),
+		"report":  report,
+	})
+}
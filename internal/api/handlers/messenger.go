@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mwc_backend/config"
+	"mwc_backend/internal/models"
+	"mwc_backend/internal/queue"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	maxMessageLength      = 5000 // Longest direct message accepted, in bytes
+	maxMessageAttachments = 10   // Attachments a single message may carry
+)
+
+// Errors returned by Messenger.Send. Handlers map them to HTTP statuses with messageErrorStatus.
+var (
+	ErrEmptyMessage         = errors.New("message content cannot be empty")
+	ErrMessageTooLong       = fmt.Errorf("message content cannot be longer than %d characters", maxMessageLength)
+	ErrMessageToSelf        = errors.New("cannot send message to yourself")
+	ErrRecipientNotFound    = errors.New("recipient not found")
+	ErrMessagingNotAllowed  = errors.New("you are not allowed to message this user")
+	ErrUserBlocked          = errors.New("messaging between you and this user is blocked")
+	ErrSenderSuspended      = errors.New("your account is suspended and cannot send messages")
+	ErrMessageRateLimited   = errors.New("too many messages to this user, please try again later")
+	ErrTooManyAttachments   = fmt.Errorf("a message can have at most %d attachments", maxMessageAttachments)
+	ErrInvalidAttachment    = errors.New("attachments must be your own uploads that have not been sent yet")
+	errMessagingRuleInvalid = errors.New("invalid messaging rule")
+)
+
+// messagingRule allows users of the sender role to start conversations with users of the recipient role.
+type messagingRule struct {
+	sender     models.UserRole
+	recipient  models.UserRole // "*" for any role
+	applicants bool            // Only educators who applied to one of the sender institution's jobs
+}
+
+// parseMessagingRules parses a MESSAGING_RULES value such as "parent>institution,institution>educator:applicants".
+func parseMessagingRules(value string) ([]messagingRule, error) {
+	var rules []messagingRule
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		roles := strings.SplitN(entry, ">", 2)
+		if len(roles) != 2 {
+			return nil, fmt.Errorf("%w %q: expected sender>recipient", errMessagingRuleInvalid, entry)
+		}
+		rule := messagingRule{sender: models.UserRole(strings.TrimSpace(roles[0]))}
+		recipient := strings.TrimSpace(roles[1])
+		if base, qualifier, found := strings.Cut(recipient, ":"); found {
+			if qualifier != "applicants" {
+				return nil, fmt.Errorf("%w %q: unknown qualifier %q", errMessagingRuleInvalid, entry, qualifier)
+			}
+			recipient = base
+			rule.applicants = true
+		}
+		rule.recipient = models.UserRole(recipient)
+		if rule.sender == "" || rule.recipient == "" {
+			return nil, fmt.Errorf("%w %q: empty role", errMessagingRuleInvalid, entry)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Messenger sends direct messages on behalf of a user. It enforces the configured messaging rules
+// and per-recipient rate limit, stores the message in its conversation, schedules the unread email
+// check and notifies the recipient. It is shared by the HTTP and WebSocket send paths.
+type Messenger struct {
+	db        *gorm.DB
+	mqService queue.MessageQueueService
+	notifier  *Notifier
+	rules     []messagingRule
+	rateLimit int
+	window    time.Duration
+}
+
+// NewMessenger creates a new Messenger. Invalid MESSAGING_RULES fall back to the defaults.
+func NewMessenger(db *gorm.DB, cfg *config.Config, mq queue.MessageQueueService, notifier *Notifier) *Messenger {
+	rules, err := parseMessagingRules(cfg.MessagingRules)
+	if err != nil {
+		log.Printf("Warning: %v. Using default messaging rules.", err)
+		rules, _ = parseMessagingRules(config.DefaultMessagingRules)
+	}
+	return &Messenger{
+		db:        db,
+		mqService: mq,
+		notifier:  notifier,
+		rules:     rules,
+		rateLimit: cfg.MessageRateLimit,
+		window:    time.Duration(cfg.MessageRateWindowMinutes) * time.Minute,
+	}
+}
+
+// Send validates and stores a message from senderID to recipientID and notifies the recipient.
+// attachmentIDs are previously uploaded attachments of the sender; a message with attachments may have no text.
+func (m *Messenger) Send(ctx context.Context, senderID, recipientID uint, content string, attachmentIDs []uint) (models.Message, models.User, error) {
+	var message models.Message
+	var sender models.User
+	attachmentIDs = uniqueIDs(attachmentIDs)
+	if strings.TrimSpace(content) == "" && len(attachmentIDs) == 0 {
+		return message, sender, ErrEmptyMessage
+	}
+	if len(attachmentIDs) > maxMessageAttachments {
+		return message, sender, ErrTooManyAttachments
+	}
+	if len(content) > maxMessageLength {
+		return message, sender, ErrMessageTooLong
+	}
+	if senderID == recipientID {
+		return message, sender, ErrMessageToSelf
+	}
+
+	if err := m.db.Select("id, first_name, last_name, email, role, is_active").First(&sender, senderID).Error; err != nil {
+		return message, sender, err
+	}
+	if !sender.IsActive {
+		return message, sender, ErrSenderSuspended
+	}
+	var recipient models.User
+	if err := m.db.Select("id, role, is_active").Where("id = ? AND is_active = ?", recipientID, true).First(&recipient).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return message, sender, ErrRecipientNotFound
+		}
+		return message, sender, err
+	}
+
+	blocked, err := usersBlocked(m.db, senderID, recipientID)
+	if err != nil {
+		return message, sender, err
+	}
+	if blocked {
+		return message, sender, ErrUserBlocked
+	}
+	allowed, err := m.allowed(sender, recipient)
+	if err != nil {
+		return message, sender, err
+	}
+	if !allowed {
+		return message, sender, ErrMessagingNotAllowed
+	}
+	if err := m.checkRateLimit(senderID, recipientID); err != nil {
+		return message, sender, err
+	}
+
+	message, err = storeDirectMessage(m.db, senderID, recipientID, content, attachmentIDs)
+	if err != nil {
+		return message, sender, err
+	}
+
+	m.scheduleUnreadCheck(ctx, message)
+	if !conversationMuted(m.db, message.ConversationID, recipientID) {
+		m.notifier.Notify(newMessageNotification(message, sender))
+	}
+	return message, sender, nil
+}
+
+// allowed checks the messaging rules. Replying to a user who wrote first is always allowed.
+func (m *Messenger) allowed(sender, recipient models.User) (bool, error) {
+	for _, rule := range m.rules {
+		if rule.sender != sender.Role || (rule.recipient != "*" && rule.recipient != recipient.Role) {
+			continue
+		}
+		if !rule.applicants {
+			return true, nil
+		}
+		applied, err := m.appliedToInstitution(recipient.ID, sender.ID)
+		if err != nil {
+			return false, err
+		}
+		if applied {
+			return true, nil
+		}
+	}
+
+	var received int64
+	if err := m.db.Model(&models.Message{}).Where("sender_id = ? AND recipient_id = ?", recipient.ID, sender.ID).Count(&received).Error; err != nil {
+		return false, err
+	}
+	return received > 0, nil
+}
+
+// appliedToInstitution reports whether the educator user applied to a job of the institution user.
+func (m *Messenger) appliedToInstitution(educatorUserID, institutionUserID uint) (bool, error) {
+	var count int64
+	err := m.db.Model(&models.JobApplication{}).
+		Joins("JOIN educator_profiles ON educator_profiles.id = job_applications.educator_profile_id").
+		Joins("JOIN jobs ON jobs.id = job_applications.job_id").
+		Joins("JOIN institution_profiles ON institution_profiles.id = jobs.institution_profile_id").
+		Where("educator_profiles.user_id = ? AND institution_profiles.user_id = ?", educatorUserID, institutionUserID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// canSeePresence reports whether the viewer may see if the user is online: the two must share a
+// conversation or the viewer must be allowed to message the user, and neither may have blocked the other.
+func (m *Messenger) canSeePresence(viewerID, userID uint) (bool, error) {
+	if viewerID == userID {
+		return true, nil
+	}
+	blocked, err := usersBlocked(m.db, viewerID, userID)
+	if err != nil || blocked {
+		return false, err
+	}
+
+	var shared int64
+	if err := m.db.Model(&models.ConversationParticipant{}).
+		Joins("JOIN conversation_participants other ON other.conversation_id = conversation_participants.conversation_id AND other.deleted_at IS NULL").
+		Where("conversation_participants.user_id = ? AND other.user_id = ?", viewerID, userID).
+		Count(&shared).Error; err != nil {
+		return false, err
+	}
+	if shared > 0 {
+		return true, nil
+	}
+
+	var viewer, user models.User
+	if err := m.db.Select("id, role, is_active").First(&viewer, viewerID).Error; err != nil {
+		return false, err
+	}
+	if err := m.db.Select("id, role, is_active").Where("id = ? AND is_active = ?", userID, true).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return m.allowed(viewer, user)
+}
+
+// checkRateLimit counts the sender's recent messages to the recipient. Counting stored messages keeps
+// the limit consistent across API nodes.
+func (m *Messenger) checkRateLimit(senderID, recipientID uint) error {
+	if m.rateLimit <= 0 {
+		return nil
+	}
+	var sent int64
+	if err := m.db.Model(&models.Message{}).
+		Where("sender_id = ? AND recipient_id = ? AND sent_at > ?", senderID, recipientID, time.Now().Add(-m.window)).
+		Count(&sent).Error; err != nil {
+		return err
+	}
+	if sent >= int64(m.rateLimit) {
+		return ErrMessageRateLimited
+	}
+	return nil
+}
+
+// scheduleUnreadCheck publishes the delayed task that emails the recipient if the message is still unread.
+func (m *Messenger) scheduleUnreadCheck(ctx context.Context, message models.Message) {
+	if m.mqService == nil || !m.mqService.IsInitialized() {
+		log.Println("RabbitMQ service not available or not initialized, skipping delayed notification task for message.")
+		return
+	}
+	payloadBytes, err := json.Marshal(UnreadMessagePayload{
+		MessageID:   message.ID,
+		RecipientID: message.RecipientID,
+		SenderID:    message.SenderID,
+	})
+	if err != nil {
+		log.Printf("Error marshalling unread message payload for MQ: %v", err)
+		return
+	}
+	// Publish to the delay exchange, using the delay queue name as routing key for direct-to-queue via exchange
+	if err := m.mqService.Publish(ctx, UnreadMessageNotificationExchange, UnreadMessageNotificationQueue, payloadBytes, UnreadMessageCheckDelayMs); err != nil {
+		log.Printf("Error publishing unread message check to RabbitMQ for MessageID %d: %v", message.ID, err)
+		return
+	}
+	log.Printf("Published unread message check for MessageID %d to RabbitMQ.", message.ID)
+}
+
+// messageErrorStatus maps a Messenger.Send error to an HTTP status and client-facing message.
+func messageErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, ErrEmptyMessage), errors.Is(err, ErrMessageTooLong), errors.Is(err, ErrMessageToSelf),
+		errors.Is(err, ErrTooManyAttachments), errors.Is(err, ErrInvalidAttachment):
+		return fiber.StatusBadRequest, err.Error()
+	case errors.Is(err, ErrRecipientNotFound):
+		return fiber.StatusNotFound, err.Error()
+	case errors.Is(err, ErrMessagingNotAllowed), errors.Is(err, ErrUserBlocked), errors.Is(err, ErrSenderSuspended):
+		return fiber.StatusForbidden, err.Error()
+	case errors.Is(err, ErrMessageRateLimited):
+		return fiber.StatusTooManyRequests, err.Error()
+	}
+	return fiber.StatusInternalServerError, "Failed to send message: " + err.Error()
+}
+
+// uniqueIDs drops zero and duplicate IDs, keeping the original order
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]struct{}, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok || id == 0 {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	return unique
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"mwc_backend/config"
+	"mwc_backend/internal/models"
+	"testing"
+)
+
+func TestCanSeePresence(t *testing.T) {
+	db := openTestDB(t)
+	messenger := NewMessenger(db, &config.Config{MessagingRules: config.DefaultMessagingRules}, nil, nil)
+	parent := createTestUser(t, db, models.ParentRole)
+	institution := createTestUser(t, db, models.InstitutionRole)
+	educator := createTestUser(t, db, models.EducatorRole)
+
+	cases := []struct {
+		name         string
+		viewer, user uint
+		want         bool
+	}{
+		{"a parent may message an institution", parent.ID, institution.ID, true},
+		{"an institution may not message a parent who never wrote", institution.ID, parent.ID, false},
+		{"an educator may not message a parent", educator.ID, parent.ID, false},
+	}
+	for _, tc := range cases {
+		visible, err := messenger.canSeePresence(tc.viewer, tc.user)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if visible != tc.want {
+			t.Errorf("%s: visible = %v, want %v", tc.name, visible, tc.want)
+		}
+	}
+
+	if _, err := storeDirectMessage(db, parent.ID, institution.ID, "Hello", nil); err != nil {
+		t.Fatalf("storing a message: %v", err)
+	}
+	if visible, err := messenger.canSeePresence(institution.ID, parent.ID); err != nil || !visible {
+		t.Errorf("the institution cannot see the parent it shares a conversation with: %v, %v", visible, err)
+	}
+
+	if err := blockUser(db, parent.ID, institution.ID); err != nil {
+		t.Fatalf("blocking: %v", err)
+	}
+	for _, pair := range [][2]uint{{parent.ID, institution.ID}, {institution.ID, parent.ID}} {
+		if visible, err := messenger.canSeePresence(pair[0], pair[1]); err != nil || visible {
+			t.Errorf("presence of user %d is visible to user %d across a block: %v, %v", pair[1], pair[0], visible, err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"fmt"
+	"mwc_backend/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// NotificationHandler handles the in-app notification center
+type NotificationHandler struct {
+	db       *gorm.DB
+	notifier *Notifier
+}
+
+// NewNotificationHandler creates a new NotificationHandler
+func NewNotificationHandler(db *gorm.DB, notifier *Notifier) *NotificationHandler {
+	return &NotificationHandler{db: db, notifier: notifier}
+}
+
+// GetNotifications lists the current user's notifications
+// @Summary List notifications
+// @Description Retrieves the current user's in-app notifications, newest first
+// @Tags notifications
+// @Produce json
+// @Param unread_only query boolean false "Only return unread notifications" default(false)
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(20)
+// @Success 200 {object} map[string]interface{} "List of notifications with pagination metadata and unread count"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	query := h.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if c.QueryBool("unread_only", false) {
+		query = query.Where("is_read = ?", false)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to count notifications: " + err.Error()})
+	}
+	var notifications []models.Notification
+	if err := query.Order("created_at desc").Offset(offset).Limit(limit).Find(&notifications).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve notifications: " + err.Error()})
+	}
+
+	unreadCount, err := h.unreadCount(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to count unread notifications: " + err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data":         notifications,
+		"unread_count": unreadCount,
+		"meta": fiber.Map{
+			"total":     total,
+			"page":      page,
+			"limit":     limit,
+			"last_page": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetUnreadCount returns the number of unread notifications
+// @Summary Get unread notification count
+// @Description Returns the number of unread in-app notifications of the current user
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Unread notification count"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+
+	count, err := h.unreadCount(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to count unread notifications: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"unread_count": count})
+}
+
+// MarkNotificationAsRead marks a single notification as read
+// @Summary Mark notification as read
+// @Description Marks one of the current user's notifications as read
+// @Tags notifications
+// @Produce json
+// @Param notification_id path int true "Notification ID"
+// @Success 200 {object} map[string]interface{} "Notification marked as read"
+// @Failure 400 {object} map[string]string "Invalid notification ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Notification not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/notifications/{notification_id}/read [post]
+func (h *NotificationHandler) MarkNotificationAsRead(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+	notificationID, err := strconv.ParseUint(c.Params("notification_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid notification ID format"})
+	}
+
+	var notification models.Notification
+	if err := h.db.Where("id = ? AND user_id = ?", uint(notificationID), userID).First(&notification).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Notification not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error: " + err.Error()})
+	}
+
+	if !notification.IsRead {
+		now := time.Now()
+		notification.IsRead = true
+		notification.ReadAt = &now
+		if err := h.db.Model(&notification).Updates(map[string]interface{}{"is_read": true, "read_at": now}).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to mark notification as read: " + err.Error()})
+		}
+		h.notifier.pushUnreadCount(userID)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Notification marked as read", "notification": notification})
+}
+
+// MarkAllNotificationsAsRead marks all of the user's notifications as read
+// @Summary Mark all notifications as read
+// @Description Marks every unread notification of the current user as read
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Number of notifications marked as read"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsAsRead(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+
+	result := h.db.Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Updates(map[string]interface{}{"is_read": true, "read_at": time.Now()})
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to mark notifications as read: " + result.Error.Error()})
+	}
+	if result.RowsAffected > 0 {
+		h.notifier.pushUnreadCount(userID)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": fmt.Sprintf("%d notification(s) marked as read", result.RowsAffected),
+		"updated": result.RowsAffected,
+	})
+}
+
+func (h *NotificationHandler) unreadCount(userID uint) (int64, error) {
+	var count int64
+	err := h.db.Model(&models.Notification{}).Where("user_id = ? AND is_read = ?", userID, false).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"mwc_backend/config"
+	"mwc_backend/internal/email"
+	"mwc_backend/internal/models"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UnsubscribeAllCategories is the pseudo-category used by unsubscribe links that opt out of every email category.
+const UnsubscribeAllCategories = "all"
+
+// NotificationPreferenceHandler handles notification preference and unsubscribe requests
+type NotificationPreferenceHandler struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+// NewNotificationPreferenceHandler creates a new NotificationPreferenceHandler
+func NewNotificationPreferenceHandler(db *gorm.DB, cfg *config.Config) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{db: db, cfg: cfg}
+}
+
+// NotificationPreferenceItem is a single category/channel preference
+type NotificationPreferenceItem struct {
+	Category models.NotificationCategory `json:"category" validate:"required,oneof=messages job_applications events reviews newsletters school_claims saved_searches"`
+	Channel  models.NotificationChannel  `json:"channel" validate:"required,oneof=email in_app websocket"`
+	Enabled  bool                        `json:"enabled"`
+}
+
+// UpdateNotificationPreferencesRequest is the request body for updating notification preferences
+type UpdateNotificationPreferencesRequest struct {
+	Preferences     []NotificationPreferenceItem `json:"preferences"`
+	DigestFrequency models.DigestFrequency       `json:"digest_frequency,omitempty" validate:"omitempty,oneof=immediate hourly daily weekly"`
+}
+
+// GetPreferences returns the current user's notification preferences
+// @Summary Get notification preferences
+// @Description Retrieves the current user's notification preferences per category and channel, and how often notification emails are batched into a digest. Channels without a stored preference are enabled.
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Notification preferences by category and channel"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/notifications/preferences [get]
+func (h *NotificationPreferenceHandler) GetPreferences(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+
+	preferences, err := h.loadPreferences(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve notification preferences: " + err.Error()})
+	}
+	var user models.User
+	if err := h.db.Select("id, digest_frequency").First(&user, userID).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve user"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"preferences": preferences, "digest_frequency": user.DigestFrequency})
+}
+
+// UpdatePreferences updates the current user's notification preferences
+// @Summary Update notification preferences
+// @Description Enables or disables notifications for the given category and channel combinations and optionally changes the digest frequency (immediate, hourly, daily or weekly)
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body UpdateNotificationPreferencesRequest true "Preferences to update"
+// @Success 200 {object} map[string]interface{} "Updated notification preferences"
+// @Failure 400 {object} map[string]string "Bad request or unknown category/channel"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/notifications/preferences [put]
+func (h *NotificationPreferenceHandler) UpdatePreferences(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+
+	var req UpdateNotificationPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+	}
+	if len(req.Preferences) == 0 && req.DigestFrequency == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "At least one preference or a digest frequency is required"})
+	}
+	if req.DigestFrequency != "" && !isValidDigestFrequency(req.DigestFrequency) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Digest frequency must be one of immediate, hourly, daily or weekly"})
+	}
+
+	for _, item := range req.Preferences {
+		if !isValidNotificationCategory(item.Category) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Unknown notification category '%s'", item.Category)})
+		}
+		if !isValidNotificationChannel(item.Channel) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Unknown notification channel '%s'", item.Channel)})
+		}
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		for _, item := range req.Preferences {
+			if err := setNotificationPreference(tx, userID, item.Category, item.Channel, item.Enabled); err != nil {
+				return err
+			}
+		}
+		if req.DigestFrequency != "" {
+			return tx.Model(&models.User{}).Where("id = ?", userID).Update("digest_frequency", req.DigestFrequency).Error
+		}
+		return nil
+	})
+	if err != nil {
+		LogUserAction(h.db, userID, "NOTIFICATION_PREFS_UPDATE_FAIL", userID, "NotificationPreference", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update notification preferences: " + err.Error()})
+	}
+
+	LogUserAction(h.db, userID, "NOTIFICATION_PREFS_UPDATE_SUCCESS", userID, "NotificationPreference", fmt.Sprintf("%d preference(s) updated, digest frequency '%s'", len(req.Preferences), req.DigestFrequency), c)
+
+	return h.GetPreferences(c)
+}
+
+// ShowUnsubscribe renders a confirmation page for an unsubscribe link
+// @Summary Unsubscribe confirmation page
+// @Description Renders a confirmation page for a signed unsubscribe link. The actual opt-out happens on POST so that link scanners cannot unsubscribe users.
+// @Tags notifications
+// @Produce html
+// @Param token query string true "Signed unsubscribe token"
+// @Success 200 {string} string "Confirmation page"
+// @Failure 400 {string} string "Invalid or missing token"
+// @Router /api/v1/unsubscribe [get]
+func (h *NotificationPreferenceHandler) ShowUnsubscribe(c *fiber.Ctx) error {
+	token := c.Query("token")
+	_, category, err := parseUnsubscribeToken(h.cfg.UnsubscribeSecret, token)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).Type("html").SendString("<p>This unsubscribe link is invalid.</p>")
+	}
+
+	description := "emails about " + strings.ReplaceAll(category, "_", " ")
+	if category == UnsubscribeAllCategories {
+		description = "all notification emails"
+	}
+	page := fmt.Sprintf(
+		"<html><body><h1>Unsubscribe</h1><p>Do you want to stop receiving %s from Montessori World Connect?</p>"+
+			"<form method=\"POST\" action=\"%s/api/v1/unsubscribe?token=%s\"><button type=\"submit\">Unsubscribe</button></form></body></html>",
+		html.EscapeString(description), h.cfg.AppBaseURL, url.QueryEscape(token),
+	)
+	return c.Status(fiber.StatusOK).Type("html").SendString(page)
+}
+
+// Unsubscribe opts a user out of email for the category in a signed unsubscribe link
+// @Summary One-click unsubscribe
+// @Description Disables email notifications for the category encoded in a signed unsubscribe token. Supports RFC 8058 one-click unsubscribe (List-Unsubscribe-Post).
+// @Tags notifications
+// @Produce json
+// @Param token query string true "Signed unsubscribe token"
+// @Success 200 {object} map[string]string "Unsubscribed successfully"
+// @Failure 400 {object} map[string]string "Invalid or missing token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/unsubscribe [post]
+func (h *NotificationPreferenceHandler) Unsubscribe(c *fiber.Ctx) error {
+	userID, category, err := parseUnsubscribeToken(h.cfg.UnsubscribeSecret, c.Query("token"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid unsubscribe link"})
+	}
+
+	categories := []models.NotificationCategory{models.NotificationCategory(category)}
+	if category == UnsubscribeAllCategories {
+		categories = models.NotificationCategories
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		for _, cat := range categories {
+			if err := setNotificationPreference(tx, userID, cat, models.NotificationChannelEmail, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		LogUserAction(h.db, userID, "EMAIL_UNSUBSCRIBE_FAIL", userID, "NotificationPreference", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to unsubscribe: " + err.Error()})
+	}
+
+	LogUserAction(h.db, userID, "EMAIL_UNSUBSCRIBE_SUCCESS", userID, "NotificationPreference", fmt.Sprintf("Unsubscribed from %s emails", category), c)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "You have been unsubscribed."})
+}
+
+// loadPreferences builds the full category/channel matrix for a user, defaulting to enabled.
+func (h *NotificationPreferenceHandler) loadPreferences(userID uint) (map[models.NotificationCategory]map[models.NotificationChannel]bool, error) {
+	var stored []models.NotificationPreference
+	if err := h.db.Where("user_id = ?", userID).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+
+	preferences := make(map[models.NotificationCategory]map[models.NotificationChannel]bool)
+	for _, category := range models.NotificationCategories {
+		preferences[category] = make(map[models.NotificationChannel]bool)
+		for _, channel := range models.NotificationChannels {
+			preferences[category][channel] = true
+		}
+	}
+	for _, pref := range stored {
+		if _, ok := preferences[pref.Category]; ok {
+			preferences[pref.Category][pref.Channel] = pref.Enabled
+		}
+	}
+	return preferences, nil
+}
+
+// setNotificationPreference creates or updates a single preference row.
+func setNotificationPreference(db *gorm.DB, userID uint, category models.NotificationCategory, channel models.NotificationChannel, enabled bool) error {
+	pref := models.NotificationPreference{
+		UserID:   userID,
+		Category: category,
+		Channel:  channel,
+		Enabled:  enabled,
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "category"}, {Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&pref).Error
+}
+
+// NotificationEnabled reports whether a user wants notifications of a category on a channel.
+// Users are opted in unless they stored a preference saying otherwise.
+func NotificationEnabled(db *gorm.DB, userID uint, category models.NotificationCategory, channel models.NotificationChannel) bool {
+	var pref models.NotificationPreference
+	err := db.Where("user_id = ? AND category = ? AND channel = ?", userID, category, channel).First(&pref).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return true
+		}
+		// Err on the side of not contacting the user if we cannot read their preferences
+		log.Printf("Error reading notification preference for user %d (%s/%s): %v", userID, category, channel, err)
+		return false
+	}
+	return pref.Enabled
+}
+
+// sendNotificationEmail sends a category email to a user if they have not opted out.
+// It returns false without error when the email was skipped because of the user's preferences or a suppressed address.
+func sendNotificationEmail(db *gorm.DB, emailService email.EmailService, cfg *config.Config, user models.User, category models.NotificationCategory, subject, htmlBody string) (bool, error) {
+	if !NotificationEnabled(db, user.ID, category, models.NotificationChannelEmail) {
+		log.Printf("User %d has opted out of %s emails, skipping '%s'", user.ID, category, subject)
+		return false, nil
+	}
+
+	reason := fmt.Sprintf("your notification settings for %s", strings.ReplaceAll(string(category), "_", " "))
+	if err := sendEmailWithUnsubscribe(emailService, cfg, user, string(category), reason, subject, htmlBody); err != nil {
+		if errors.Is(err, email.ErrRecipientSuppressed) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// sendEmailWithUnsubscribe adds a signed one-click unsubscribe link for unsubscribeCategory to the
+// body and the List-Unsubscribe headers, then sends the email.
+func sendEmailWithUnsubscribe(emailService email.EmailService, cfg *config.Config, user models.User, unsubscribeCategory, reason, subject, htmlBody string) error {
+	unsubscribeURL := unsubscribeURL(cfg, user.ID, unsubscribeCategory)
+	body := htmlBody + fmt.Sprintf(
+		"<hr/><p style=\"font-size:12px;color:#888888\">You are receiving this email because of %s. <a href=\"%s\">Unsubscribe</a></p>",
+		reason, unsubscribeURL,
+	)
+	headers := map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	return emailService.SendEmailWithHeaders(user.Email, subject, body, headers)
+}
+
+// unsubscribeURL builds the public one-click unsubscribe URL for a user and category.
+func unsubscribeURL(cfg *config.Config, userID uint, category string) string {
+	token := generateUnsubscribeToken(cfg.UnsubscribeSecret, userID, category)
+	return fmt.Sprintf("%s/api/v1/unsubscribe?token=%s", cfg.AppBaseURL, url.QueryEscape(token))
+}
+
+// generateUnsubscribeToken signs "userID:category" with HMAC-SHA256.
+func generateUnsubscribeToken(secret string, userID uint, category string) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", userID, category)))
+	return payload + "." + signUnsubscribePayload(secret, payload)
+}
+
+// parseUnsubscribeToken verifies a token created by generateUnsubscribeToken.
+func parseUnsubscribeToken(secret, token string) (uint, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return 0, "", fmt.Errorf("malformed unsubscribe token")
+	}
+	if !hmac.Equal([]byte(parts[1]), []byte(signUnsubscribePayload(secret, parts[0]))) {
+		return 0, "", fmt.Errorf("invalid unsubscribe token signature")
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return 0, "", fmt.Errorf("malformed unsubscribe token payload: %w", err)
+	}
+	fields := strings.SplitN(string(decoded), ":", 2)
+	if len(fields) != 2 {
+		return 0, "", fmt.Errorf("malformed unsubscribe token payload")
+	}
+	userID, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid user ID in unsubscribe token: %w", err)
+	}
+	category := fields[1]
+	if category != UnsubscribeAllCategories && !isValidNotificationCategory(models.NotificationCategory(category)) {
+		return 0, "", fmt.Errorf("unknown category in unsubscribe token")
+	}
+	return uint(userID), category, nil
+}
+
+func signUnsubscribePayload(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func isValidNotificationCategory(category models.NotificationCategory) bool {
+	for _, c := range models.NotificationCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidDigestFrequency(frequency models.DigestFrequency) bool {
+	switch frequency {
+	case models.DigestImmediate, models.DigestHourly, models.DigestDaily, models.DigestWeekly:
+		return true
+	}
+	return false
+}
+
+func isValidNotificationChannel(channel models.NotificationChannel) bool {
+	for _, c := range models.NotificationChannels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
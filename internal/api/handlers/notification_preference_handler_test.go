@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"mwc_backend/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestSetNotificationPreferenceInsertsFalse(t *testing.T) {
+	var sql string
+	var vars []interface{}
+	db := dryRunDB(t, func(s string, v []interface{}) { sql, vars = s, v })
+
+	err := setNotificationPreference(db, 1, models.NotificationCategoryEvents, models.NotificationChannelEmail, false)
+	if err != nil {
+		t.Fatalf("setNotificationPreference: %v", err)
+	}
+	if !strings.Contains(sql, `"enabled"`) || !strings.Contains(sql, `"enabled"="excluded"."enabled"`) {
+		t.Fatalf("enabled is not written on insert and conflict: %s", sql)
+	}
+	found := false
+	for _, v := range vars {
+		if b, ok := v.(bool); ok {
+			if b {
+				t.Fatalf("the disabled preference is inserted as enabled: %s %v", sql, vars)
+			}
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("no enabled value is inserted: %s %v", sql, vars)
+	}
+}
+
+func TestSetNotificationPreferenceDisablesAndReEnables(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, models.ParentRole)
+	category, channel := models.NotificationCategoryEvents, models.NotificationChannelEmail
+
+	if !NotificationEnabled(db, user.ID, category, channel) {
+		t.Fatal("a preference without a row should be enabled")
+	}
+	if err := setNotificationPreference(db, user.ID, category, channel, false); err != nil {
+		t.Fatalf("disabling: %v", err)
+	}
+	if NotificationEnabled(db, user.ID, category, channel) {
+		t.Fatal("the preference is still enabled after disabling it")
+	}
+	if err := setNotificationPreference(db, user.ID, category, channel, true); err != nil {
+		t.Fatalf("re-enabling: %v", err)
+	}
+	if !NotificationEnabled(db, user.ID, category, channel) {
+		t.Fatal("the preference is still disabled after re-enabling it")
+	}
+	if err := setNotificationPreference(db, user.ID, category, channel, false); err != nil {
+		t.Fatalf("disabling again: %v", err)
+	}
+	if NotificationEnabled(db, user.ID, category, channel) {
+		t.Fatal("the existing preference row was not updated to disabled")
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"mwc_backend/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// NotificationPusher delivers real-time payloads to a user's open connections
+// and to the subscribers of a topic. It is implemented by WebSocketHandler.
+type NotificationPusher interface {
+	SendNotification(userID uint, notificationType string, payload interface{})
+	PublishToTopic(topic string, messageType string, payload interface{})
+}
+
+// Notifier creates in-app notifications and pushes them to connected users,
+// honouring the user's in_app and websocket notification preferences.
+type Notifier struct {
+	db     *gorm.DB
+	pusher NotificationPusher
+}
+
+// NewNotifier creates a new Notifier. pusher may be nil, in which case notifications are only persisted.
+func NewNotifier(db *gorm.DB, pusher NotificationPusher) *Notifier {
+	return &Notifier{db: db, pusher: pusher}
+}
+
+// Notify stores the notification for notification.UserID and pushes it over the websocket
+// if the user is connected. Errors are logged, a failed notification never fails the caller.
+func (n *Notifier) Notify(notification models.Notification) {
+	if n == nil || notification.UserID == 0 {
+		return
+	}
+
+	if NotificationEnabled(n.db, notification.UserID, notification.Category, models.NotificationChannelInApp) {
+		notification.IsRead = false
+		if err := n.db.Create(&notification).Error; err != nil {
+			log.Printf("Notifier: failed to store %s notification for user %d: %v", notification.Type, notification.UserID, err)
+		}
+	}
+
+	if n.pusher != nil && NotificationEnabled(n.db, notification.UserID, notification.Category, models.NotificationChannelWebSocket) {
+		n.pusher.SendNotification(notification.UserID, "notification", notification)
+		n.pushUnreadCount(notification.UserID)
+	}
+}
+
+// NotifyUsers sends a copy of the notification to each of the given users.
+func (n *Notifier) NotifyUsers(userIDs []uint, notification models.Notification) {
+	for _, userID := range userIDs {
+		userNotification := notification
+		userNotification.UserID = userID
+		n.Notify(userNotification)
+	}
+}
+
+// Publish sends a live update to the WebSocket subscribers of a topic. Nothing is persisted.
+func (n *Notifier) Publish(topic string, messageType string, payload interface{}) {
+	if n == nil || n.pusher == nil {
+		return
+	}
+	n.pusher.PublishToTopic(topic, messageType, payload)
+}
+
+// Push sends a live update to every connection of a user. Nothing is persisted.
+func (n *Notifier) Push(userID uint, messageType string, payload interface{}) {
+	if n == nil || n.pusher == nil {
+		return
+	}
+	n.pusher.SendNotification(userID, messageType, payload)
+}
+
+// newMessageNotification builds the notification for a direct message.
+func newMessageNotification(message models.Message, sender models.User) models.Notification {
+	senderName := strings.TrimSpace(sender.FirstName + " " + sender.LastName)
+	if senderName == "" {
+		senderName = "A user"
+	}
+	return models.Notification{
+		UserID:   message.RecipientID,
+		Category: models.NotificationCategoryMessages,
+		Type:     models.NotificationTypeNewMessage,
+		Title:    fmt.Sprintf("New message from %s", senderName),
+		Body:     truncateMessage(messagePreview(message.Content, message.Attachments), 100),
+		Payload:  map[string]interface{}{"message_id": message.ID, "sender_id": message.SenderID, "conversation_id": message.ConversationID},
+		DeepLink: fmt.Sprintf("/messages/%d", message.SenderID),
+	}
+}
+
+// pushUnreadCount sends the user's current unread count so every open client can update its badge.
+func (n *Notifier) pushUnreadCount(userID uint) {
+	if n == nil || n.pusher == nil {
+		return
+	}
+	var count int64
+	if err := n.db.Model(&models.Notification{}).Where("user_id = ? AND is_read = ?", userID, false).Count(&count).Error; err != nil {
+		log.Printf("Notifier: failed to count unread notifications for user %d: %v", userID, err)
+		return
+	}
+	n.pusher.SendNotification(userID, "notifications_unread_count", map[string]interface{}{"unread_count": count})
+}
@@ -1,15 +1,11 @@
 package handlers
 
 import (
-	"encoding/json"
-	"fmt"
 	"log"
 	"mwc_backend/internal/email"
 	"mwc_backend/internal/models"
 	"mwc_backend/internal/queue"
 	"strconv"
-	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -29,10 +25,13 @@ type ParentHandler struct {
 	db           *gorm.DB
 	mqService    queue.MessageQueueService
 	emailService email.EmailService
+	notifier     *Notifier
+	messenger    *Messenger
+	recommender  *Recommender
 }
 
-func NewParentHandler(db *gorm.DB, mq queue.MessageQueueService, emailSvc email.EmailService) *ParentHandler {
-	handler := &ParentHandler{db: db, mqService: mq, emailService: emailSvc}
+func NewParentHandler(db *gorm.DB, mq queue.MessageQueueService, emailSvc email.EmailService, notifier *Notifier, messenger *Messenger, recommender *Recommender) *ParentHandler {
+	handler := &ParentHandler{db: db, mqService: mq, emailService: emailSvc, notifier: notifier, messenger: messenger, recommender: recommender}
 	if mq != nil && mq.(*queue.RabbitMQService).IsInitialized() { // Check if mqService is the actual RabbitMQService and initialized
 		// Declare RabbitMQ topology for delayed unread message notifications
 		err := mq.DeclareDelayedMessageExchangeAndQueue(
@@ -72,7 +71,8 @@ type ParentProfileRequest struct {
 }
 
 type MessageRequest struct {
-	Content string `json:"content" validate:"required"`
+	Content       string `json:"content"`                  // Required unless attachments are sent
+	AttachmentIDs []uint `json:"attachment_ids,omitempty"` // IDs returned by the attachment upload endpoint
 }
 
 // UnreadMessagePayload is the data sent to RabbitMQ
@@ -173,6 +173,7 @@ func (h *ParentHandler) SaveSchool(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid school ID format"})
 	}
+	schoolID = uint64(resolveSchoolID(h.db, uint(schoolID))) // Save the surviving school of a merged school
 
 	var parentProfile models.ParentProfile
 	if err := h.db.Preload("SavedSchools").Where("user_id = ?", actorUserID).First(&parentProfile).Error; err != nil {
@@ -195,6 +196,7 @@ func (h *ParentHandler) SaveSchool(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save school: " + err.Error()})
 	}
 	LogUserAction(h.db, actorUserID, "PARENT_SCHOOL_SAVE_SUCCESS", uint(schoolID), "School", "School saved", c)
+	h.recommender.Enqueue(actorUserID)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "School saved successfully."})
 }
 
@@ -232,6 +234,7 @@ func (h *ParentHandler) DeleteSavedSchool(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete saved school: " + err.Error()})
 	}
 	LogUserAction(h.db, actorUserID, "PARENT_SCHOOL_UNSAVE_SUCCESS", uint(schoolID), "School", "School unsaved", c)
+	h.recommender.Enqueue(actorUserID)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Saved school deleted successfully."})
 }
 
@@ -255,9 +258,9 @@ func (h *ParentHandler) GetSavedSchools(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(parentProfile.SavedSchools)
 }
 
-// SendMessage handles sending a message from one parent to another.
+// SendMessage handles sending a message from a parent to another parent or a school.
 // @Summary Send a message
-// @Description Sends a message to an institution or educator
+// @Description Sends a message to another parent or to a school, subject to MESSAGING_RULES and the per-recipient rate limit. Same as /api/v1/messages/send/{recipient_id}.
 // @Tags parent,messages
 // @Accept json
 // @Produce json
@@ -266,82 +269,14 @@ func (h *ParentHandler) GetSavedSchools(c *fiber.Ctx) error {
 // @Success 201 {object} models.Message "Message sent successfully"
 // @Failure 400 {object} map[string]string "Bad request or invalid recipient ID"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Messaging this user is not allowed"
 // @Failure 404 {object} map[string]string "Recipient not found"
+// @Failure 429 {object} map[string]string "Too many messages to this recipient"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Security BearerAuth
 // @Router /api/v1/parent/messages/send/{recipient_id} [post]
 func (h *ParentHandler) SendMessage(c *fiber.Ctx) error {
-	senderID, _ := c.Locals("user_id").(uint)
-	recipientIDStr := c.Params("recipient_id")
-	recipientID, err := strconv.ParseUint(recipientIDStr, 10, 32)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid recipient ID format"})
-	}
-
-	if senderID == uint(recipientID) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot send message to yourself."})
-	}
-
-	var recipientUser models.User
-	if err := h.db.Where("id = ? AND role = ?", uint(recipientID), models.ParentRole).First(&recipientUser).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Recipient parent not found."})
-	}
-
-	req := new(MessageRequest)
-	if err := c.BodyParser(req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
-	}
-	if strings.TrimSpace(req.Content) == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Message content cannot be empty."})
-	}
-
-	message := models.Message{
-		SenderID:    senderID,
-		RecipientID: uint(recipientID),
-		Content:     req.Content,
-		IsRead:      false, // Default to unread
-	}
-
-	if err := h.db.Create(&message).Error; err != nil {
-		LogUserAction(h.db, senderID, "PARENT_MSG_SEND_FAIL_DB", message.ID, "Message", err.Error(), c)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to send message: " + err.Error()})
-	}
-
-	// Publish to RabbitMQ for delayed notification check if mqService is available
-	if h.mqService != nil && h.mqService.(*queue.RabbitMQService).IsInitialized() {
-		payload := UnreadMessagePayload{
-			MessageID:   message.ID,
-			RecipientID: message.RecipientID,
-			SenderID:    message.SenderID,
-		}
-		payloadBytes, MqErr := json.Marshal(payload)
-		if MqErr != nil {
-			log.Printf("Error marshalling unread message payload for MQ: %v", MqErr)
-			LogUserAction(h.db, senderID, "PARENT_MSG_SEND_WARN_MQ_MARSHAL", message.ID, "Message", MqErr.Error(), c)
-		} else {
-			// Publish to the delay exchange, using the delay queue name as routing key for direct-to-queue via exchange
-			MqErr = h.mqService.Publish(
-				c.Context(),
-				UnreadMessageNotificationExchange, // Exchange that routes to the delay queue
-				UnreadMessageNotificationQueue,    // Routing key (often same as queue name for direct binding)
-				payloadBytes,
-				UnreadMessageCheckDelayMs,
-			)
-			if MqErr != nil {
-				log.Printf("Error publishing unread message check to RabbitMQ for MessageID %d: %v", message.ID, MqErr)
-				LogUserAction(h.db, senderID, "PARENT_MSG_SEND_WARN_MQ_PUBLISH", message.ID, "Message", MqErr.Error(), c)
-			} else {
-				log.Printf("Published unread message check for MessageID %d to RabbitMQ.", message.ID)
-				LogUserAction(h.db, senderID, "PARENT_MSG_SEND_MQ_PUBLISHED", message.ID, "Message", "MQ task for unread check published", c)
-			}
-		}
-	} else {
-		log.Println("RabbitMQ service not available or not initialized, skipping delayed notification task for message.")
-		LogUserAction(h.db, senderID, "PARENT_MSG_SEND_WARN_MQ_UNAVAILABLE", message.ID, "Message", "MQ unavailable for unread check", c)
-	}
-
-	LogUserAction(h.db, senderID, "PARENT_MSG_SEND_SUCCESS", message.ID, "Message", fmt.Sprintf("Message sent to user %d", recipientID), c)
-	return c.Status(fiber.StatusCreated).JSON(message)
+	return sendMessageFromRequest(c, h.db, h.messenger, "PARENT_MSG_SEND")
 }
 
 // GetMessages retrieves messages for the logged-in parent.
@@ -363,7 +298,7 @@ func (h *ParentHandler) GetMessages(c *fiber.Ctx) error {
 	offset := (page - 1) * limit
 
 	var messages []models.Message
-	query := h.db.Preload("Sender").Preload("Recipient").
+	query := h.db.Preload("Sender").Preload("Recipient").Preload("Attachments").
 		Where("sender_id = ? OR recipient_id = ?", actorUserID, actorUserID).
 		Order("sent_at desc").
 		Offset(offset).Limit(limit)
@@ -408,24 +343,19 @@ func (h *ParentHandler) MarkMessageAsRead(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid message ID format"})
 	}
 
-	var message models.Message
 	// Ensure the message is for the current user and they are the recipient
-	if err := h.db.Where("id = ? AND recipient_id = ?", uint(messageID), actorUserID).First(&message).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Message not found or you are not the recipient."})
-	}
-
-	if message.IsRead {
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Message already marked as read.", "message_data": message})
-	}
-
-	now := time.Now()
-	message.IsRead = true
-	message.ReadAt = &now
-
-	if err := h.db.Save(&message).Error; err != nil {
+	message, newlyRead, err := markMessageRead(h.db, actorUserID, uint(messageID))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Message not found or you are not the recipient."})
+		}
 		LogUserAction(h.db, actorUserID, "PARENT_MSG_READ_FAIL_DB", uint(messageID), "Message", err.Error(), c)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to mark message as read: " + err.Error()})
 	}
+	if !newlyRead {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Message already marked as read.", "message_data": message})
+	}
+	h.notifier.pushMessageReceipt(message, "read")
 
 	LogUserAction(h.db, actorUserID, "PARENT_MSG_READ_SUCCESS", uint(messageID), "Message", "Message marked as read", c)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Message marked as read successfully.", "message_data": message})
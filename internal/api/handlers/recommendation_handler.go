@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"mwc_backend/internal/models"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// RecommendationHandler serves the school recommendations of parents and the job recommendations of educators
+type RecommendationHandler struct {
+	db          *gorm.DB
+	recommender *Recommender
+}
+
+// NewRecommendationHandler creates a new RecommendationHandler
+func NewRecommendationHandler(db *gorm.DB, recommender *Recommender) *RecommendationHandler {
+	return &RecommendationHandler{db: db, recommender: recommender}
+}
+
+// RecommendedSchool is a school recommended to a parent
+type RecommendedSchool struct {
+	School  models.School `json:"school"` // With its logo and cover
+	Score   float64       `json:"score"`  // From 0 to 1
+	Reasons []string      `json:"reasons"`
+}
+
+// RecommendedJob is a job recommended to an educator
+type RecommendedJob struct {
+	Job     models.Job `json:"job"` // With the name and verified badge of the posting institution
+	Score   float64    `json:"score"`
+	Reasons []string   `json:"reasons"`
+}
+
+// recommendations loads the user's recommendations, computing them on the first request and queueing their
+// recomputation once they are older than a day
+func (h *RecommendationHandler) recommendations(c *fiber.Ctx, userID uint, kind models.RecommendationKind, computedAt *time.Time) ([]models.Recommendation, *time.Time, error) {
+	if computedAt == nil {
+		if err := h.recommender.Recompute(c.Context(), userID); err != nil {
+			return nil, nil, err
+		}
+		now := time.Now()
+		computedAt = &now
+	} else if time.Since(*computedAt) > recommendationMaxAge {
+		h.recommender.Enqueue(userID)
+	}
+
+	var recommendations []models.Recommendation
+	err := h.db.Where("user_id = ? AND kind = ?", userID, kind).Order("score DESC, id").Find(&recommendations).Error
+	return recommendations, computedAt, err
+}
+
+// GetParentRecommendations lists schools recommended to the logged-in parent.
+// @Summary Get school recommendations
+// @Description Lists up to 20 schools recommended to the parent, best first, each with a score and the reasons for it. Schools are scored on their distance from saved schools and saved searches, their similarity to saved schools (programs, languages, accreditations, schedules and tuition) and their popularity (saves and ratings). Recommendations are recomputed in the background when the parent saves or removes schools or changes saved searches, and daily.
+// @Tags parent,schools
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Recommended schools, with meta.computed_at"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Parent profile not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/parent/recommendations [get]
+func (h *RecommendationHandler) GetParentRecommendations(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	var profile models.ParentProfile
+	if err := h.db.Where("user_id = ?", userID).First(&profile).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Parent profile not found."})
+	}
+	recommendations, computedAt, err := h.recommendations(c, userID, models.RecommendationSchool, profile.RecommendationsComputedAt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load recommendations: " + err.Error()})
+	}
+
+	ids := make([]uint, len(recommendations))
+	for i, recommendation := range recommendations {
+		ids[i] = recommendation.TargetID
+	}
+	var schools []models.School
+	if len(ids) > 0 {
+		if err := h.db.Preload("Media", "kind IN ?", []models.MediaKind{models.MediaLogo, models.MediaCover}).
+			Where("id IN ?", ids).Find(&schools).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load recommended schools: " + err.Error()})
+		}
+	}
+	schoolsByID := make(map[uint]models.School, len(schools))
+	for _, school := range schools {
+		schoolsByID[school.ID] = school
+	}
+	data := []RecommendedSchool{}
+	for _, recommendation := range recommendations {
+		if school, ok := schoolsByID[recommendation.TargetID]; ok { // Schools deleted since are skipped
+			data = append(data, RecommendedSchool{School: school, Score: recommendation.Score, Reasons: recommendation.Reasons})
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": data, "meta": fiber.Map{"computed_at": computedAt}})
+}
+
+// GetEducatorRecommendations lists jobs recommended to the logged-in educator.
+// @Summary Get job recommendations
+// @Description Lists up to 20 active jobs recommended to the educator, best first, each with a score and the reasons for it. Jobs are scored on the distance of the posting school from saved schools, saved searches and schools applied to, their similarity to jobs applied for (title, employment type and location) or being at a saved school, and their popularity (applications and verified institution). Recommendations are recomputed in the background when the educator applies, saves or removes schools or changes saved searches, and daily.
+// @Tags educator,jobs
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Recommended jobs, with meta.computed_at"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Educator profile not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/educator/recommendations [get]
+func (h *RecommendationHandler) GetEducatorRecommendations(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	var profile models.EducatorProfile
+	if err := h.db.Where("user_id = ?", userID).First(&profile).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Educator profile not found. Please complete your profile first."})
+	}
+	recommendations, computedAt, err := h.recommendations(c, userID, models.RecommendationJob, profile.RecommendationsComputedAt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load recommendations: " + err.Error()})
+	}
+
+	ids := make([]uint, len(recommendations))
+	for i, recommendation := range recommendations {
+		ids[i] = recommendation.TargetID
+	}
+	var jobs []models.Job
+	if len(ids) > 0 {
+		// Jobs closed since the recommendations were computed are left out
+		err := h.db.Where("id IN ? AND is_active = ?", ids, true).
+			Preload("InstitutionProfile", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "institution_name", "school_id", "is_verified", "verified_at")
+			}).Find(&jobs).Error
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load recommended jobs: " + err.Error()})
+		}
+	}
+	jobsByID := make(map[uint]models.Job, len(jobs))
+	for _, job := range jobs {
+		jobsByID[job.ID] = job
+	}
+	data := []RecommendedJob{}
+	for _, recommendation := range recommendations {
+		if job, ok := jobsByID[recommendation.TargetID]; ok {
+			data = append(data, RecommendedJob{Job: job, Score: recommendation.Score, Reasons: recommendation.Reasons})
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": data, "meta": fiber.Map{"computed_at": computedAt}})
+}
@@ -0,0 +1,607 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"mwc_backend/internal/geo"
+	"mwc_backend/internal/models"
+	"mwc_backend/internal/queue"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	RecommendationQueue = "q.recommendations" // Users whose recommendations are recomputed, published to through the default exchange
+
+	maxRecommendations           = 20             // Recommendations kept per user
+	maxRecommendationAnchors     = 10             // Locations distances are measured from
+	recommendationCandidates     = 100            // Candidates loaded per source: nearby, similar, popular
+	recommendationMaxDistanceKm  = 100            // Candidates further away from every anchor get no location score
+	recommendationMaxAge         = 24 * time.Hour // Recommendations older than this are recomputed
+	recommendationRefreshBatch   = 500            // Users queued per refresh run
+	recommendationMinScore       = 0.01           // Candidates scoring less are not recommended
+	recommendationPopularSaves   = 50             // Saves at which a school counts as fully popular
+	recommendationPopularApplies = 20             // Applications at which a job counts as fully popular
+)
+
+// Weights of the parts of a school recommendation's score
+const (
+	schoolLocationWeight   = 0.35
+	schoolSimilarityWeight = 0.40
+	schoolPopularityWeight = 0.25
+)
+
+// Weights of the parts of a job recommendation's score
+const (
+	jobLocationWeight   = 0.30
+	jobAttributesWeight = 0.45
+	jobPopularityWeight = 0.25
+)
+
+// schoolSavesSQL lists one row per time a school was saved by a parent or an educator
+const schoolSavesSQL = "(SELECT school_id FROM parent_saved_schools UNION ALL SELECT school_id FROM educator_saved_schools)"
+
+// recommendationMessage is published to RecommendationQueue
+type recommendationMessage struct {
+	UserID uint `json:"user_id"`
+}
+
+// Recommender computes the school recommendations of parents and the job recommendations of educators. Each
+// recommendation has a score from location, similarity to what the user saved or applied to, and popularity,
+// and the reasons behind it.
+type Recommender struct {
+	db *gorm.DB
+	mq queue.MessageQueueService
+}
+
+// NewRecommender creates a new Recommender. Without an initialized queue, recomputations run in-process.
+func NewRecommender(db *gorm.DB, mq queue.MessageQueueService) *Recommender {
+	return &Recommender{db: db, mq: mq}
+}
+
+func (r *Recommender) queued() bool {
+	return r.mq != nil && r.mq.IsInitialized()
+}
+
+// Start consumes the recommendation queue
+func (r *Recommender) Start() error {
+	if !r.queued() {
+		log.Println("RabbitMQ service not initialized, recommendations will be recomputed in-process.")
+		return nil
+	}
+	if _, err := r.mq.DeclareQueue(RecommendationQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue '%s': %w", RecommendationQueue, err)
+	}
+	return r.mq.Consume(RecommendationQueue, "recommendations", func(delivery amqp.Delivery) error {
+		var message recommendationMessage
+		if err := json.Unmarshal(delivery.Body, &message); err != nil {
+			return fmt.Errorf("invalid recommendation message: %w", err)
+		}
+		return r.Recompute(context.Background(), message.UserID)
+	})
+}
+
+// Enqueue recomputes a user's recommendations in the background, e.g. after the user saved a school
+func (r *Recommender) Enqueue(userID uint) {
+	if r == nil || userID == 0 {
+		return
+	}
+	if r.queued() {
+		body, _ := json.Marshal(recommendationMessage{UserID: userID})
+		err := r.mq.Publish(context.Background(), "", RecommendationQueue, body, 0)
+		if err == nil {
+			return
+		}
+		log.Printf("Failed to queue recommendations of user %d, recomputing in-process: %v", userID, err)
+	}
+	go func() {
+		if err := r.Recompute(context.Background(), userID); err != nil {
+			log.Printf("Error recomputing recommendations of user %d: %v", userID, err)
+		}
+	}()
+}
+
+// RefreshStale queues the recomputation of recommendations older than a day, for users who requested them before
+func (r *Recommender) RefreshStale(ctx context.Context) error {
+	db := r.db.WithContext(ctx)
+	cutoff := time.Now().Add(-recommendationMaxAge)
+	var userIDs []uint
+	for _, table := range []string{"parent_profiles", "educator_profiles"} {
+		var ids []uint
+		err := db.Table(table).
+			Joins(fmt.Sprintf("JOIN users ON users.id = %s.user_id AND users.is_active = ? AND users.deleted_at IS NULL", table), true).
+			Where(table+".deleted_at IS NULL AND "+table+".recommendations_computed_at <= ?", cutoff).
+			Order(table+".recommendations_computed_at").Limit(recommendationRefreshBatch).
+			Pluck(table+".user_id", &ids).Error
+		if err != nil {
+			return fmt.Errorf("failed to find stale recommendations in %s: %w", table, err)
+		}
+		userIDs = append(userIDs, ids...)
+	}
+	for _, userID := range userIDs {
+		if r.queued() {
+			r.Enqueue(userID)
+		} else if err := r.Recompute(ctx, userID); err != nil {
+			log.Printf("Error recomputing recommendations of user %d: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// Recompute replaces the recommendations of a parent or an educator. Other users have none.
+func (r *Recommender) Recompute(ctx context.Context, userID uint) error {
+	db := r.db.WithContext(ctx)
+	var user models.User
+	if err := db.Select("id", "role").First(&user, userID).Error; err != nil {
+		return fmt.Errorf("failed to load user %d: %w", userID, err)
+	}
+
+	switch user.Role {
+	case models.ParentRole:
+		var profile models.ParentProfile
+		if err := db.Preload("SavedSchools").Where("user_id = ?", userID).First(&profile).Error; err != nil {
+			return fmt.Errorf("failed to load parent profile of user %d: %w", userID, err)
+		}
+		recommendations, err := r.recommendSchools(db, userID, profile.SavedSchools)
+		if err != nil {
+			return err
+		}
+		return r.save(db, userID, models.RecommendationSchool, recommendations, &models.ParentProfile{}, profile.ID)
+	case models.EducatorRole:
+		var profile models.EducatorProfile
+		if err := db.Preload("SavedSchools").Where("user_id = ?", userID).First(&profile).Error; err != nil {
+			return fmt.Errorf("failed to load educator profile of user %d: %w", userID, err)
+		}
+		recommendations, err := r.recommendJobs(db, userID, profile)
+		if err != nil {
+			return err
+		}
+		return r.save(db, userID, models.RecommendationJob, recommendations, &models.EducatorProfile{}, profile.ID)
+	}
+	return nil
+}
+
+// save replaces the user's recommendations and records when they were computed on the profile. Recomputations
+// of the same user can overlap, from the queue, the in-process fallback and a first request; locking the
+// profile row makes them replace the rows one after the other instead of both inserting theirs.
+func (r *Recommender) save(db *gorm.DB, userID uint, kind models.RecommendationKind, recommendations []models.Recommendation, profile interface{}, profileID uint) error {
+	now := time.Now()
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", profileID).Take(profile).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND kind = ?", userID, kind).Delete(&models.Recommendation{}).Error; err != nil {
+			return err
+		}
+		if len(recommendations) > 0 {
+			for i := range recommendations {
+				recommendations[i].CreatedAt = now
+			}
+			if err := tx.Create(&recommendations).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(profile).Where("id = ?", profileID).Update("recommendations_computed_at", now).Error
+	})
+}
+
+// recommendationAnchor is a place the user is interested in, distances of candidates are measured from
+type recommendationAnchor struct {
+	point geo.Point
+	label string // e.g. "Casa dei Bambini, which you saved"
+}
+
+// anchors returns the locations of the user's saved school searches, then those of the given schools
+func (r *Recommender) anchors(db *gorm.DB, userID uint, schools []*models.School, label string) []recommendationAnchor {
+	var anchors []recommendationAnchor
+	var searches []models.SavedSearch
+	if err := db.Where("user_id = ? AND kind = ? AND filters->>'lat' IS NOT NULL AND filters->>'lng' IS NOT NULL", userID, models.SavedSearchSchools).
+		Order("updated_at DESC").Limit(maxRecommendationAnchors).Find(&searches).Error; err != nil {
+		log.Printf("Recommendations: failed to load saved searches of user %d: %v", userID, err)
+	}
+	for _, search := range searches {
+		var params SchoolSearchParams
+		if err := decodeSearchFilters(search.Filters, &params); err == nil && params.Latitude != nil && params.Longitude != nil {
+			anchors = append(anchors, recommendationAnchor{
+				point: geo.Point{Lat: *params.Latitude, Lng: *params.Longitude},
+				label: fmt.Sprintf("the area of your saved search %q", search.Name),
+			})
+		}
+	}
+	for _, school := range schools {
+		if school.Latitude != nil && school.Longitude != nil {
+			anchors = append(anchors, recommendationAnchor{
+				point: geo.Point{Lat: *school.Latitude, Lng: *school.Longitude},
+				label: fmt.Sprintf(label, school.Name),
+			})
+		}
+	}
+	return anchors[:min(len(anchors), maxRecommendationAnchors)]
+}
+
+// recommendationScore adds up the weighted parts of a score and keeps the reason of each part
+type recommendationScore struct {
+	total   float64
+	reasons []scoredReason
+}
+
+type scoredReason struct {
+	contribution float64
+	text         string
+}
+
+// add adds a part scoring from 0 to 1 with its weight. reason is kept if the part scores.
+func (s *recommendationScore) add(weight, score float64, reason string) {
+	if score <= 0 {
+		return
+	}
+	s.total += weight * math.Min(score, 1)
+	if reason != "" {
+		s.reasons = append(s.reasons, scoredReason{contribution: weight * score, text: reason})
+	}
+}
+
+// explanation returns the reasons, strongest first
+func (s recommendationScore) explanation() []string {
+	sort.SliceStable(s.reasons, func(i, j int) bool { return s.reasons[i].contribution > s.reasons[j].contribution })
+	reasons := make([]string, len(s.reasons))
+	for i, reason := range s.reasons {
+		reasons[i] = reason.text
+	}
+	return reasons
+}
+
+// addLocation scores how close a point is to the nearest anchor
+func (s *recommendationScore) addLocation(weight float64, anchors []recommendationAnchor, latitude, longitude *float64) {
+	if latitude == nil || longitude == nil {
+		return
+	}
+	point := geo.Point{Lat: *latitude, Lng: *longitude}
+	nearest, distance := -1, math.MaxFloat64
+	for i, anchor := range anchors {
+		if d := geo.Distance(anchor.point, point); d < distance {
+			nearest, distance = i, d
+		}
+	}
+	if nearest >= 0 {
+		s.add(weight, 1-distance/recommendationMaxDistanceKm, fmt.Sprintf("%.1f km from %s", distance, anchors[nearest].label))
+	}
+}
+
+// topRecommendations keeps the best scoring candidates
+func topRecommendations(recommendations []models.Recommendation) []models.Recommendation {
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].TargetID < recommendations[j].TargetID
+	})
+	return recommendations[:min(len(recommendations), maxRecommendations)]
+}
+
+// roundScore rounds a score to three decimals
+func roundScore(score float64) float64 {
+	return math.Round(score*1000) / 1000
+}
+
+// jaccard is the share of values two lists have in common, from 0 to 1
+func jaccard(a, b []string) float64 {
+	union := map[string]bool{}
+	for _, value := range a {
+		union[value] = true
+	}
+	shared := 0
+	for _, value := range b {
+		if union[value] {
+			shared++
+		}
+		union[value] = true
+	}
+	if len(union) == 0 {
+		return 0
+	}
+	return float64(shared) / float64(len(union))
+}
+
+// sharedValues lists the values of a that are also in b
+func sharedValues[T ~string](a, b []T) []string {
+	var shared []string
+	for _, value := range a {
+		if slices.Contains(b, value) && !slices.Contains(shared, string(value)) {
+			shared = append(shared, string(value))
+		}
+	}
+	return shared
+}
+
+// stringValues converts a list of string constants to strings
+func stringValues[T ~string](values []T) []string {
+	converted := make([]string, len(values))
+	for i, value := range values {
+		converted[i] = string(value)
+	}
+	return converted
+}
+
+// schoolSimilarity scores how much a candidate school is like a saved one, from 0 to 1, and names what they share
+func schoolSimilarity(candidate, saved *models.School) (float64, []string) {
+	var score float64
+	var features []string
+	if shared := sharedValues(candidate.Programs, saved.Programs); len(shared) > 0 {
+		score += 0.4 * jaccard(stringValues(candidate.Programs), stringValues(saved.Programs))
+		features = append(features, strings.Join(shared, " and ")+" program")
+	}
+	if shared := sharedValues(candidate.Languages, saved.Languages); len(shared) > 0 {
+		score += 0.2 * jaccard(candidate.Languages, saved.Languages)
+		features = append(features, "taught in "+strings.ToUpper(strings.Join(shared, ", ")))
+	}
+	if shared := sharedValues(candidate.Accreditations, saved.Accreditations); len(shared) > 0 {
+		score += 0.15 * jaccard(stringValues(candidate.Accreditations), stringValues(saved.Accreditations))
+		features = append(features, strings.ToUpper(strings.Join(shared, ", "))+" accredited")
+	}
+	if shared := sharedValues(candidate.Schedules, saved.Schedules); len(shared) > 0 {
+		score += 0.1 * jaccard(stringValues(candidate.Schedules), stringValues(saved.Schedules))
+		features = append(features, strings.ReplaceAll(strings.Join(shared, ", "), "_", "-")+" schedule")
+	}
+	if candidate.TuitionBand != "" && candidate.TuitionBand == saved.TuitionBand {
+		score += 0.15
+		features = append(features, string(candidate.TuitionBand)+" tuition")
+	}
+	return score, features
+}
+
+// schoolRating is the number and average of a school's approved review ratings
+type schoolRating struct {
+	SchoolID uint
+	Count    int64
+	Average  float64
+}
+
+// recommendSchools scores schools the parent has not saved
+func (r *Recommender) recommendSchools(db *gorm.DB, userID uint, saved []*models.School) ([]models.Recommendation, error) {
+	anchors := r.anchors(db, userID, saved, "%s, which you saved")
+	savedIDs := make([]uint, len(saved))
+	for i, school := range saved {
+		savedIDs[i] = school.ID
+	}
+	exclude := func(query *gorm.DB) *gorm.DB {
+		if len(savedIDs) == 0 {
+			return query
+		}
+		return query.Where("schools.id NOT IN ?", savedIDs)
+	}
+
+	// Candidates near the anchors, sharing programs or cities with saved schools, and the most saved ones
+	candidates := map[uint]*models.School{}
+	collect := func(query *gorm.DB) error {
+		var schools []models.School
+		if err := exclude(query).Limit(recommendationCandidates).Find(&schools).Error; err != nil {
+			return err
+		}
+		for i := range schools {
+			candidates[schools[i].ID] = &schools[i]
+		}
+		return nil
+	}
+	for _, anchor := range anchors {
+		params := SchoolSearchParams{Latitude: &anchor.point.Lat, Longitude: &anchor.point.Lng, RadiusKm: recommendationMaxDistanceKm}
+		if err := collect(params.order(params.apply(db.Model(&models.School{}), ""))); err != nil {
+			return nil, fmt.Errorf("failed to load nearby schools: %w", err)
+		}
+	}
+	var programs, cities []string
+	for _, school := range saved {
+		programs = append(programs, stringValues(school.Programs)...)
+		if school.City != "" {
+			cities = append(cities, strings.ToLower(school.City))
+		}
+	}
+	if programs, _ = parseAttributeFilter("program", strings.Join(programs, ","), nil); len(programs) > 0 {
+		condition, vars := jsonbContainsAny("schools.programs", programs)
+		if err := collect(db.Model(&models.School{}).Where(condition, vars...).Order("schools.updated_at DESC")); err != nil {
+			return nil, fmt.Errorf("failed to load similar schools: %w", err)
+		}
+	}
+	if len(cities) > 0 {
+		if err := collect(db.Model(&models.School{}).Where("LOWER(schools.city) IN ?", cities).Order("schools.updated_at DESC")); err != nil {
+			return nil, fmt.Errorf("failed to load schools in saved cities: %w", err)
+		}
+	}
+	if err := collect(db.Model(&models.School{}).
+		Joins("JOIN (SELECT school_id, COUNT(*) AS saves FROM " + schoolSavesSQL + " AS all_saves GROUP BY school_id) AS saves ON saves.school_id = schools.id").
+		Order("saves.saves DESC, schools.id")); err != nil {
+		return nil, fmt.Errorf("failed to load popular schools: %w", err)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, 0, len(candidates))
+	for id := range candidates {
+		ids = append(ids, id)
+	}
+	saves, err := countBySchool(db.Raw("SELECT school_id, COUNT(*) AS count FROM "+schoolSavesSQL+" AS all_saves WHERE school_id IN ? GROUP BY school_id", ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count school saves: %w", err)
+	}
+	var ratingRows []schoolRating
+	if err := db.Model(&models.Review{}).Select("school_id, COUNT(*) AS count, AVG(rating) AS average").
+		Where("school_id IN ? AND status = ?", ids, models.ReviewApproved).Group("school_id").Scan(&ratingRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load school ratings: %w", err)
+	}
+	ratings := make(map[uint]schoolRating, len(ratingRows))
+	for _, rating := range ratingRows {
+		ratings[rating.SchoolID] = rating
+	}
+
+	recommendations := make([]models.Recommendation, 0, len(candidates))
+	for id, candidate := range candidates {
+		var score recommendationScore
+		score.addLocation(schoolLocationWeight, anchors, candidate.Latitude, candidate.Longitude)
+
+		var similarity float64
+		var similarTo string
+		var features []string
+		for _, school := range saved {
+			if s, f := schoolSimilarity(candidate, school); s > similarity {
+				similarity, similarTo, features = s, school.Name, f
+			}
+		}
+		score.add(schoolSimilarityWeight, similarity, fmt.Sprintf("Like %s, which you saved: %s", similarTo, strings.Join(features, ", ")))
+
+		savesScore := math.Min(1, math.Log1p(float64(saves[id]))/math.Log1p(recommendationPopularSaves))
+		savesReason := ""
+		if saves[id] >= 3 {
+			savesReason = fmt.Sprintf("Saved by %d families and educators", saves[id])
+		}
+		score.add(schoolPopularityWeight/2, savesScore, savesReason)
+		if rating, ok := ratings[id]; ok {
+			ratingReason := ""
+			if rating.Average >= 3.5 {
+				ratingReason = fmt.Sprintf("Rated %.1f from %d review(s)", rating.Average, rating.Count)
+			}
+			score.add(schoolPopularityWeight/2, rating.Average/5*math.Min(1, float64(rating.Count)/3), ratingReason)
+		}
+
+		if score.total >= recommendationMinScore {
+			recommendations = append(recommendations, models.Recommendation{
+				UserID: userID, Kind: models.RecommendationSchool, TargetID: id, Score: roundScore(score.total), Reasons: score.explanation(),
+			})
+		}
+	}
+	return topRecommendations(recommendations), nil
+}
+
+// titleWords are the words of a job title that tell jobs apart
+func titleWords(title string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		if len(word) >= 3 && !slices.Contains([]string{"and", "the", "for", "with"}, word) && !slices.Contains(words, word) {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// jobSimilarity scores how much a candidate job is like one applied to, from 0 to 1, and names what they share
+func jobSimilarity(candidate, applied *models.Job) (float64, []string) {
+	var score float64
+	var features []string
+	if similarity := jaccard(titleWords(candidate.Title), titleWords(applied.Title)); similarity > 0 {
+		score += 0.5 * similarity
+		features = append(features, "similar title")
+	}
+	if candidate.EmploymentType != "" && strings.EqualFold(candidate.EmploymentType, applied.EmploymentType) {
+		score += 0.3
+		features = append(features, candidate.EmploymentType)
+	}
+	if candidate.Location != "" && strings.EqualFold(strings.TrimSpace(candidate.Location), strings.TrimSpace(applied.Location)) {
+		score += 0.2
+		features = append(features, "in "+candidate.Location)
+	}
+	return score, features
+}
+
+// recommendJobs scores active jobs the educator has not applied to
+func (r *Recommender) recommendJobs(db *gorm.DB, userID uint, profile models.EducatorProfile) ([]models.Recommendation, error) {
+	appliedJobIDs := db.Model(&models.JobApplication{}).Select("job_id").Where("educator_profile_id = ?", profile.ID)
+	var applied []models.Job
+	if err := db.Preload("InstitutionProfile.School").Where("id IN (?)", appliedJobIDs).
+		Order("created_at DESC").Limit(recommendationCandidates).Find(&applied).Error; err != nil {
+		return nil, fmt.Errorf("failed to load applied jobs: %w", err)
+	}
+	anchorSchools := append([]*models.School{}, profile.SavedSchools...)
+	savedSchoolIDs := make([]uint, len(profile.SavedSchools))
+	for i, school := range profile.SavedSchools {
+		savedSchoolIDs[i] = school.ID
+	}
+	anchors := r.anchors(db, userID, anchorSchools, "%s, which you saved")
+	for i := range applied {
+		school := applied[i].InstitutionProfile.School
+		if school != nil && school.Latitude != nil && school.Longitude != nil && len(anchors) < maxRecommendationAnchors {
+			anchors = append(anchors, recommendationAnchor{
+				point: geo.Point{Lat: *school.Latitude, Lng: *school.Longitude},
+				label: fmt.Sprintf("%s, where you applied", school.Name),
+			})
+		}
+	}
+
+	var candidates []models.Job
+	err := JobSearchParams{}.apply(db.Model(&models.Job{})).
+		Preload("InstitutionProfile.School").
+		Where("(jobs.expires_at IS NULL OR jobs.expires_at > ?)", time.Now()).
+		Where("jobs.id NOT IN (?)", appliedJobIDs).
+		Order("jobs.created_at DESC").Limit(3 * recommendationCandidates).Find(&candidates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load candidate jobs: %w", err)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, len(candidates))
+	for i, job := range candidates {
+		ids[i] = job.ID
+	}
+	var applicationCounts []struct {
+		JobID uint
+		Count int64
+	}
+	if err := db.Model(&models.JobApplication{}).Select("job_id, COUNT(*) AS count").
+		Where("job_id IN ?", ids).Group("job_id").Scan(&applicationCounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count job applications: %w", err)
+	}
+	applications := make(map[uint]int64, len(applicationCounts))
+	for _, count := range applicationCounts {
+		applications[count.JobID] = count.Count
+	}
+
+	recommendations := make([]models.Recommendation, 0, len(candidates))
+	for i := range candidates {
+		candidate := &candidates[i]
+		var score recommendationScore
+		school := candidate.InstitutionProfile.School
+		if school != nil {
+			score.addLocation(jobLocationWeight, anchors, school.Latitude, school.Longitude)
+		}
+
+		var similarity float64
+		var reason string
+		for j := range applied {
+			if s, features := jobSimilarity(candidate, &applied[j]); s > similarity {
+				similarity = s
+				reason = fmt.Sprintf("Like %s, which you applied for: %s", applied[j].Title, strings.Join(features, ", "))
+			}
+		}
+		if school != nil && slices.Contains(savedSchoolIDs, school.ID) {
+			similarity, reason = 1, fmt.Sprintf("At %s, which you saved", school.Name)
+		}
+		score.add(jobAttributesWeight, similarity, reason)
+
+		applicationsReason := ""
+		if applications[candidate.ID] >= 3 {
+			applicationsReason = fmt.Sprintf("%d educators applied", applications[candidate.ID])
+		}
+		score.add(jobPopularityWeight*0.7, math.Min(1, math.Log1p(float64(applications[candidate.ID]))/math.Log1p(recommendationPopularApplies)), applicationsReason)
+		if candidate.InstitutionProfile.IsVerified {
+			score.add(jobPopularityWeight*0.3, 1, "Posted by a verified institution")
+		}
+
+		if score.total >= recommendationMinScore {
+			recommendations = append(recommendations, models.Recommendation{
+				UserID: userID, Kind: models.RecommendationJob, TargetID: candidate.ID, Score: roundScore(score.total), Reasons: score.explanation(),
+			})
+		}
+	}
+	return topRecommendations(recommendations), nil
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"math"
+	"mwc_backend/internal/geo"
+	"mwc_backend/internal/models"
+	"slices"
+	"testing"
+)
+
+func TestJaccard(t *testing.T) {
+	cases := []struct {
+		a, b []string
+		want float64
+	}{
+		{nil, nil, 0},
+		{[]string{"a"}, nil, 0},
+		{[]string{"a", "b"}, []string{"a", "b"}, 1},
+		{[]string{"a", "b"}, []string{"b", "c"}, 1.0 / 3},
+		{[]string{"a"}, []string{"b"}, 0},
+		{[]string{"a", "b", "c", "d"}, []string{"a"}, 0.25},
+	}
+	for _, tc := range cases {
+		if got := jaccard(tc.a, tc.b); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("jaccard(%v, %v) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+		if got, reversed := jaccard(tc.a, tc.b), jaccard(tc.b, tc.a); math.Abs(got-reversed) > 1e-9 {
+			t.Errorf("jaccard(%v, %v) = %v but reversed is %v", tc.a, tc.b, got, reversed)
+		}
+	}
+}
+
+func TestSchoolSimilarity(t *testing.T) {
+	full := &models.School{
+		Programs:       []models.SchoolProgram{models.ProgramPrimary, models.ProgramElementary},
+		Languages:      []string{"en", "fr"},
+		Accreditations: []models.SchoolAccreditation{models.AccreditationAMI},
+		Schedules:      []models.SchoolSchedule{models.ScheduleFullDay},
+		TuitionBand:    models.TuitionMedium,
+	}
+	cases := []struct {
+		name         string
+		candidate    *models.School
+		want         float64
+		wantFeatures []string
+	}{
+		{"identical", full, 1, []string{"primary and elementary program", "taught in EN, FR", "AMI accredited", "full-day schedule", "medium tuition"}},
+		{"nothing in common", &models.School{Programs: []models.SchoolProgram{models.ProgramToddler}, TuitionBand: models.TuitionHigh}, 0, nil},
+		{"no attributes", &models.School{}, 0, nil},
+		{"half the programs", &models.School{Programs: []models.SchoolProgram{models.ProgramPrimary}}, 0.2, []string{"primary program"}},
+		{"same tuition only", &models.School{TuitionBand: models.TuitionMedium}, 0.15, []string{"medium tuition"}},
+	}
+	for _, tc := range cases {
+		got, features := schoolSimilarity(tc.candidate, full)
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("%s: similarity = %v, want %v", tc.name, got, tc.want)
+		}
+		if got < 0 || got > 1 {
+			t.Errorf("%s: similarity %v is outside [0, 1]", tc.name, got)
+		}
+		if !slices.Equal(features, tc.wantFeatures) {
+			t.Errorf("%s: features = %q, want %q", tc.name, features, tc.wantFeatures)
+		}
+	}
+}
+
+func TestJobSimilarity(t *testing.T) {
+	applied := &models.Job{Title: "Lead Primary Guide", EmploymentType: "Full-time", Location: "Accra"}
+	cases := []struct {
+		name         string
+		candidate    *models.Job
+		want         float64
+		wantFeatures []string
+	}{
+		{"identical", &models.Job{Title: "lead primary guide", EmploymentType: "full-time", Location: "accra"}, 1, []string{"similar title", "full-time", "in accra"}},
+		{"nothing in common", &models.Job{Title: "Cook", EmploymentType: "Part-time", Location: "Kumasi"}, 0, nil},
+		{"empty job", &models.Job{}, 0, nil},
+		{"title words only", &models.Job{Title: "Primary Assistant"}, 0.5 * 1.0 / 4, []string{"similar title"}},
+		{"short and common words ignored", &models.Job{Title: "The guide for an AM class"}, 0.5 * 1.0 / 4, []string{"similar title"}},
+	}
+	for _, tc := range cases {
+		got, features := jobSimilarity(tc.candidate, applied)
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("%s: similarity = %v, want %v", tc.name, got, tc.want)
+		}
+		if got < 0 || got > 1 {
+			t.Errorf("%s: similarity %v is outside [0, 1]", tc.name, got)
+		}
+		if !slices.Equal(features, tc.wantFeatures) {
+			t.Errorf("%s: features = %q, want %q", tc.name, features, tc.wantFeatures)
+		}
+	}
+}
+
+func TestTitleWords(t *testing.T) {
+	got := titleWords("Lead Guide (Primary) - Lead guide for the 3-6 class")
+	want := []string{"lead", "guide", "primary", "class"}
+	if !slices.Equal(got, want) {
+		t.Errorf("titleWords = %q, want %q", got, want)
+	}
+}
+
+func TestRecommendationScore(t *testing.T) {
+	var score recommendationScore
+	score.add(0.25, 0.5, "weak")
+	score.add(0.40, 1, "strong")
+	score.add(0.35, 0, "ignored: no score")
+	score.add(0.10, -1, "ignored: negative score")
+	score.add(0.10, 3, "")
+	if want := 0.125 + 0.40 + 0.10; math.Abs(score.total-want) > 1e-9 {
+		t.Errorf("total = %v, want %v: scores above 1 count as 1 and reasonless parts still count", score.total, want)
+	}
+	if got, want := score.explanation(), []string{"strong", "weak"}; !slices.Equal(got, want) {
+		t.Errorf("explanation = %q, want %q", got, want)
+	}
+
+	// A score made of parts whose weights add up to 1 stays within [0, 1]
+	var max recommendationScore
+	max.add(schoolLocationWeight, 5, "a")
+	max.add(schoolSimilarityWeight, 5, "b")
+	max.add(schoolPopularityWeight, 5, "c")
+	if max.total < 0 || max.total > 1+1e-9 {
+		t.Errorf("total = %v, want at most 1", max.total)
+	}
+}
+
+func TestRecommendationScoreLocation(t *testing.T) {
+	home := geo.Point{Lat: 5.6037, Lng: -0.1870} // Accra
+	anchors := []recommendationAnchor{
+		{point: geo.Point{Lat: 6.6885, Lng: -1.6244}, label: "Kumasi school"},
+		{point: home, label: "Accra school"},
+	}
+	// About 0.1 degree of latitude north of the Accra anchor, roughly 11 km
+	near := geo.Point{Lat: home.Lat + 0.1, Lng: home.Lng}
+	// Further than recommendationMaxDistanceKm from both anchors
+	far := geo.Point{Lat: 9.4034, Lng: -0.8424} // Tamale
+
+	var same recommendationScore
+	same.addLocation(0.35, anchors, &home.Lat, &home.Lng)
+	if math.Abs(same.total-0.35) > 1e-9 {
+		t.Errorf("score at an anchor = %v, want the full weight", same.total)
+	}
+
+	var nearby recommendationScore
+	nearby.addLocation(0.35, anchors, &near.Lat, &near.Lng)
+	distance := geo.Distance(home, near)
+	if want := 0.35 * (1 - distance/recommendationMaxDistanceKm); math.Abs(nearby.total-want) > 1e-9 {
+		t.Errorf("score near an anchor = %v, want %v", nearby.total, want)
+	}
+	if reasons := nearby.explanation(); len(reasons) != 1 || reasons[0] != "11.1 km from Accra school" {
+		t.Errorf("reasons = %q, want the distance to the nearest anchor", reasons)
+	}
+
+	var distant recommendationScore
+	distant.addLocation(0.35, anchors, &far.Lat, &far.Lng)
+	if distant.total != 0 || len(distant.reasons) != 0 {
+		t.Errorf("score beyond %d km = %v with reasons %q, want none", recommendationMaxDistanceKm, distant.total, distant.explanation())
+	}
+
+	var unknown recommendationScore
+	unknown.addLocation(0.35, anchors, nil, nil)
+	unknown.addLocation(0.35, nil, &home.Lat, &home.Lng)
+	if unknown.total != 0 {
+		t.Errorf("score without coordinates or anchors = %v, want 0", unknown.total)
+	}
+}
@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"fmt"
+	"html"
 	"log"
+	"mwc_backend/config"
+	"mwc_backend/internal/email"
 	"mwc_backend/internal/models"
 	"mwc_backend/internal/queue"
 	"time"
@@ -13,13 +16,16 @@ import (
 
 // ReviewHandler handles review-related requests
 type ReviewHandler struct {
-	db        *gorm.DB
-	mqService queue.MessageQueueService
+	db           *gorm.DB
+	mqService    queue.MessageQueueService
+	emailService email.EmailService
+	cfg          *config.Config
+	notifier     *Notifier
 }
 
 // NewReviewHandler creates a new ReviewHandler
-func NewReviewHandler(db *gorm.DB, mqService queue.MessageQueueService) *ReviewHandler {
-	return &ReviewHandler{db: db, mqService: mqService}
+func NewReviewHandler(db *gorm.DB, mqService queue.MessageQueueService, emailService email.EmailService, cfg *config.Config, notifier *Notifier) *ReviewHandler {
+	return &ReviewHandler{db: db, mqService: mqService, emailService: emailService, cfg: cfg, notifier: notifier}
 }
 
 // CreateReviewRequest is the request body for creating a review
@@ -77,7 +83,8 @@ func (h *ReviewHandler) CreateReview(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Comment must be at least 10 characters"})
 	}
 
-	// Check if school exists
+	// Check if school exists, following the redirect of a merged school
+	req.SchoolID = resolveSchoolID(h.db, req.SchoolID)
 	var school models.School
 	if err := h.db.First(&school, req.SchoolID).Error; err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "School not found"})
@@ -138,6 +145,11 @@ func (h *ReviewHandler) GetSchoolReviews(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid school ID"})
 	}
 
+	// Reviews of a merged school moved to the school it was merged into
+	if targetID := resolveSchoolID(h.db, uint(schoolID)); targetID != uint(schoolID) {
+		return c.Redirect(fmt.Sprintf("/api/v1/schools/%d/reviews", targetID), fiber.StatusMovedPermanently)
+	}
+
 	// Check if school exists
 	var school models.School
 	if err := h.db.First(&school, schoolID).Error; err != nil {
@@ -435,6 +447,17 @@ func (h *ReviewHandler) ModerateReview(c *fiber.Ctx) error {
 	}
 
 	LogUserAction(h.db, adminID, "REVIEW_MODERATED", review.ID, "Review", fmt.Sprintf("Review moderated with status %s", req.Status), c)
+	h.notifyReviewerOfModeration(review)
+	if review.Status == models.ReviewApproved {
+		h.notifyInstitutionOfReview(review)
+		h.notifier.Publish(SchoolReviewsTopic(review.SchoolID), "review_approved", map[string]interface{}{
+			"id":         review.ID,
+			"school_id":  review.SchoolID,
+			"rating":     review.Rating,
+			"comment":    review.Comment,
+			"created_at": review.CreatedAt,
+		})
+	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": fmt.Sprintf("Review %s successfully", req.Status),
@@ -452,6 +475,71 @@ func (h *ReviewHandler) ModerateReview(c *fiber.Ctx) error {
 	})
 }
 
+// notifyReviewerOfModeration tells the author of a review whether it was approved or rejected.
+func (h *ReviewHandler) notifyReviewerOfModeration(review models.Review) {
+	var school models.School
+	h.db.Select("id, name").First(&school, review.SchoolID)
+
+	body := "Your review is now visible to everyone."
+	if review.Status == models.ReviewRejected {
+		body = "Your review did not meet our guidelines and will not be published."
+		if review.ModeratorNotes != "" {
+			body += " Moderator notes: " + review.ModeratorNotes
+		}
+	}
+	h.notifier.Notify(models.Notification{
+		UserID:   review.ReviewerID,
+		Category: models.NotificationCategoryReviews,
+		Type:     models.NotificationTypeReviewModerated,
+		Title:    fmt.Sprintf("Your review of %s was %s", school.Name, review.Status),
+		Body:     body,
+		Payload:  map[string]interface{}{"review_id": review.ID, "school_id": review.SchoolID, "status": review.Status},
+		DeepLink: "/reviews/mine",
+	})
+}
+
+// notifyInstitutionOfReview emails the institution mapped to the reviewed school about a newly approved review,
+// or queues it for their digest. Failures are logged and do not fail the moderation.
+func (h *ReviewHandler) notifyInstitutionOfReview(review models.Review) {
+	var institution models.InstitutionProfile
+	err := h.db.Preload("User").Preload("School").Where("school_id = ?", review.SchoolID).First(&institution).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Printf("Failed to load institution for school %d to notify about review %d: %v", review.SchoolID, review.ID, err)
+		}
+		return
+	}
+
+	schoolName := institution.InstitutionName
+	if institution.School != nil {
+		schoolName = institution.School.Name
+	}
+	subject := fmt.Sprintf("New %d-star review for %s", review.Rating, schoolName)
+	h.notifier.Notify(models.Notification{
+		UserID:   institution.UserID,
+		Category: models.NotificationCategoryReviews,
+		Type:     models.NotificationTypeNewReview,
+		Title:    subject,
+		Body:     truncateMessage(review.Comment, 100),
+		Payload:  map[string]interface{}{"review_id": review.ID, "school_id": review.SchoolID, "rating": review.Rating},
+		DeepLink: fmt.Sprintf("/schools/%d/reviews", review.SchoolID),
+	})
+
+	body := fmt.Sprintf(
+		"<h1>Hi %s,</h1><p>A new review of <b>%s</b> has been published.</p><p><b>Rating:</b> %d/5<br/><b>Review:</b> \"%s\"</p><p>Thank you,<br/>The Platform Team</p>",
+		html.EscapeString(institution.InstitutionName), html.EscapeString(schoolName), review.Rating, html.EscapeString(truncateMessage(review.Comment, 200)),
+	)
+	item := models.DigestItem{
+		Title:      subject,
+		Summary:    truncateMessage(review.Comment, 100),
+		SourceType: "Review",
+		SourceID:   review.ID,
+	}
+	if _, err := deliverNotificationEmail(h.db, h.emailService, h.cfg, institution.User, models.NotificationCategoryReviews, subject, body, item); err != nil {
+		log.Printf("Failed to notify institution %d about review %d: %v", institution.ID, review.ID, err)
+	}
+}
+
 // GetPendingReviews gets all pending reviews (admin only)
 // @Summary Get pending reviews
 // @Description Retrieves all reviews that are currently in 'pending' status, awaiting admin moderation.
@@ -0,0 +1,518 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"mwc_backend/config"
+	"mwc_backend/internal/email"
+	"mwc_backend/internal/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxSavedSearches is the largest number of searches a user can save
+const maxSavedSearches = 20
+
+// errTooManySavedSearches is returned when a user already has maxSavedSearches saved searches
+var errTooManySavedSearches = fmt.Errorf("you can save at most %d searches", maxSavedSearches)
+
+// maxSavedSearchAlertItems is how many new matches an alert names; the others are only counted
+const maxSavedSearchAlertItems = 5
+
+// savedSearchPeriods is how long after its last run a saved search is run again
+var savedSearchPeriods = map[models.SavedSearchFrequency]time.Duration{
+	models.SavedSearchDaily:  24 * time.Hour,
+	models.SavedSearchWeekly: 7 * 24 * time.Hour,
+}
+
+// SavedSearchHandler handles the saved school and job searches of parents and educators and their alerts
+type SavedSearchHandler struct {
+	db           *gorm.DB
+	cfg          *config.Config
+	notifier     *Notifier
+	emailService email.EmailService
+	recommender  *Recommender // Locations of saved school searches are a recommendation signal
+}
+
+// NewSavedSearchHandler creates a new SavedSearchHandler
+func NewSavedSearchHandler(db *gorm.DB, cfg *config.Config, notifier *Notifier, emailService email.EmailService, recommender *Recommender) *SavedSearchHandler {
+	return &SavedSearchHandler{db: db, cfg: cfg, notifier: notifier, emailService: emailService, recommender: recommender}
+}
+
+// SavedSearchRequest is a named search to save. Filters are the filters of GET /schools/public for school
+// searches (programs, languages, tuition_bands, schedules and accreditations as lists) or of GET /jobs for job
+// searches.
+type SavedSearchRequest struct {
+	Name      string                      `json:"name" validate:"required,max=100"`
+	Kind      models.SavedSearchKind      `json:"kind" validate:"required,oneof=schools jobs"`
+	Filters   map[string]interface{}      `json:"filters"`
+	Frequency models.SavedSearchFrequency `json:"frequency" validate:"omitempty,oneof=daily weekly never"` // Defaults to daily
+}
+
+// decodeSearchFilters decodes stored or requested filters into the search parameters of their kind, rejecting
+// unknown filters
+func decodeSearchFilters(filters map[string]interface{}, params interface{}) error {
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(params)
+}
+
+// normalizeSearchFilters checks the filters of a search and returns them in the form they are stored
+func normalizeSearchFilters(kind models.SavedSearchKind, filters map[string]interface{}) (map[string]interface{}, error) {
+	var params interface{}
+	switch kind {
+	case models.SavedSearchSchools:
+		var schoolParams SchoolSearchParams
+		if err := decodeSearchFilters(filters, &schoolParams); err != nil {
+			return nil, fmt.Errorf("invalid school search filters: %w", err)
+		}
+		schoolParams.Query = strings.TrimSpace(schoolParams.Query)
+		schoolParams.City = strings.TrimSpace(schoolParams.City)
+		schoolParams.State = strings.TrimSpace(schoolParams.State)
+		schoolParams.CountryCode = strings.TrimSpace(schoolParams.CountryCode)
+		if err := schoolParams.validateAttributes(); err != nil {
+			return nil, err
+		}
+		if err := schoolParams.validateLocation(); err != nil {
+			return nil, err
+		}
+		params = schoolParams
+	case models.SavedSearchJobs:
+		var jobParams JobSearchParams
+		if err := decodeSearchFilters(filters, &jobParams); err != nil {
+			return nil, fmt.Errorf("invalid job search filters: %w", err)
+		}
+		jobParams.normalize()
+		params = jobParams
+	default:
+		return nil, errors.New("kind must be schools or jobs")
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	normalized := map[string]interface{}{}
+	return normalized, json.Unmarshal(data, &normalized)
+}
+
+// validateSavedSearch validates a request and normalizes its filters and frequency
+func validateSavedSearch(req *SavedSearchRequest) []FieldError {
+	req.Name = strings.TrimSpace(req.Name)
+	fieldErrors := validationErrors(*req)
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+	filters, err := normalizeSearchFilters(req.Kind, req.Filters)
+	if err != nil {
+		return []FieldError{{Field: "filters", Message: err.Error()}}
+	}
+	req.Filters = filters
+	if req.Frequency == "" {
+		req.Frequency = models.SavedSearchDaily
+	}
+	return nil
+}
+
+// findSavedSearch loads a saved search of the user from the search_id path parameter
+func (h *SavedSearchHandler) findSavedSearch(c *fiber.Ctx, userID uint) (*models.SavedSearch, *fiber.Error) {
+	searchID, err := strconv.ParseUint(c.Params("search_id"), 10, 32)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid search ID format")
+	}
+	var search models.SavedSearch
+	if err := h.db.Where("user_id = ?", userID).First(&search, uint(searchID)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Saved search not found")
+		}
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Database error: "+err.Error())
+	}
+	return &search, nil
+}
+
+// createSavedSearch stores a saved search unless its user already has maxSavedSearches. The user row is locked
+// while counting, so concurrent requests cannot both pass the limit.
+func createSavedSearch(db *gorm.DB, search *models.SavedSearch) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", search.UserID).Take(&user).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.SavedSearch{}).Where("user_id = ?", search.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxSavedSearches {
+			return errTooManySavedSearches
+		}
+		return tx.Create(search).Error
+	})
+}
+
+// CreateSavedSearch saves a school or job search of the logged-in user.
+// @Summary Save a search
+// @Description Saves a named school or job search with the filters of GET /api/v1/schools/public or GET /api/v1/jobs. Searches with a daily or weekly frequency are run on that schedule and the user is alerted in-app and by email about schools and jobs added since the search was saved that match it. Alerts follow the saved_searches notification preferences. A user can save up to 20 searches.
+// @Tags searches
+// @Accept json
+// @Produce json
+// @Param search body SavedSearchRequest true "Name, kind, filters and alert frequency"
+// @Success 201 {object} models.SavedSearch "Saved search"
+// @Failure 400 {object} map[string]string "Invalid request body or filters"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 409 {object} map[string]string "Too many saved searches"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/searches [post]
+func (h *SavedSearchHandler) CreateSavedSearch(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	var req SavedSearchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+	}
+	if fieldErrors := validateSavedSearch(&req); len(fieldErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": validationErrorsMessage(fieldErrors), "fields": fieldErrors})
+	}
+
+	search := models.SavedSearch{
+		UserID:    userID,
+		Name:      req.Name,
+		Kind:      req.Kind,
+		Filters:   req.Filters,
+		Frequency: req.Frequency,
+	}
+	if err := createSavedSearch(h.db, &search); err != nil {
+		if errors.Is(err, errTooManySavedSearches) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": fmt.Sprintf("You can save at most %d searches", maxSavedSearches)})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save search: " + err.Error()})
+	}
+	LogUserAction(h.db, userID, "SAVED_SEARCH_CREATED", search.ID, "SavedSearch", fmt.Sprintf("%s search %q", search.Kind, search.Name), c)
+	h.recommender.Enqueue(userID)
+	return c.Status(fiber.StatusCreated).JSON(search)
+}
+
+// GetSavedSearches lists the saved searches of the logged-in user.
+// @Summary List saved searches
+// @Description Lists the saved school and job searches of the logged-in user, newest first
+// @Tags searches
+// @Produce json
+// @Success 200 {object} map[string][]models.SavedSearch "Saved searches"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/searches [get]
+func (h *SavedSearchHandler) GetSavedSearches(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	searches := []models.SavedSearch{}
+	if err := h.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&searches).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve saved searches: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": searches})
+}
+
+// UpdateSavedSearch replaces the name, kind, filters and frequency of a saved search.
+// @Summary Update a saved search
+// @Description Replaces the name, kind, filters and alert frequency of a saved search of the logged-in user. Schools and jobs already alerted about are not alerted about again.
+// @Tags searches
+// @Accept json
+// @Produce json
+// @Param search_id path int true "Saved search ID"
+// @Param search body SavedSearchRequest true "Name, kind, filters and alert frequency"
+// @Success 200 {object} models.SavedSearch "Updated saved search"
+// @Failure 400 {object} map[string]string "Invalid search ID, request body or filters"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Saved search not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/searches/{search_id} [put]
+func (h *SavedSearchHandler) UpdateSavedSearch(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	search, lookupErr := h.findSavedSearch(c, userID)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	var req SavedSearchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+	}
+	if fieldErrors := validateSavedSearch(&req); len(fieldErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": validationErrorsMessage(fieldErrors), "fields": fieldErrors})
+	}
+
+	kindChanged := search.Kind != req.Kind
+	search.Name, search.Kind, search.Filters, search.Frequency = req.Name, req.Kind, req.Filters, req.Frequency
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(search).Select("name", "kind", "filters", "frequency").Updates(search).Error; err != nil {
+			return err
+		}
+		if kindChanged { // Recorded matches are school or job IDs depending on the kind
+			return tx.Where("saved_search_id = ?", search.ID).Delete(&models.SavedSearchMatch{}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update saved search: " + err.Error()})
+	}
+	LogUserAction(h.db, userID, "SAVED_SEARCH_UPDATED", search.ID, "SavedSearch", fmt.Sprintf("%s search %q", search.Kind, search.Name), c)
+	h.recommender.Enqueue(userID)
+	return c.Status(fiber.StatusOK).JSON(search)
+}
+
+// DeleteSavedSearch deletes a saved search.
+// @Summary Delete a saved search
+// @Description Deletes a saved search of the logged-in user and stops its alerts
+// @Tags searches
+// @Produce json
+// @Param search_id path int true "Saved search ID"
+// @Success 200 {object} map[string]string "Saved search deleted"
+// @Failure 400 {object} map[string]string "Invalid search ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Saved search not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/searches/{search_id} [delete]
+func (h *SavedSearchHandler) DeleteSavedSearch(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	search, lookupErr := h.findSavedSearch(c, userID)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("saved_search_id = ?", search.ID).Delete(&models.SavedSearchMatch{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(search).Error
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete saved search: " + err.Error()})
+	}
+	LogUserAction(h.db, userID, "SAVED_SEARCH_DELETED", search.ID, "SavedSearch", fmt.Sprintf("%s search %q", search.Kind, search.Name), c)
+	h.recommender.Enqueue(userID)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Saved search deleted successfully"})
+}
+
+// GetSavedSearchResults runs a saved search.
+// @Summary Run a saved search
+// @Description Runs a saved search of the logged-in user and returns a page of results shaped like GET /api/v1/schools/public or GET /api/v1/jobs
+// @Tags searches
+// @Produce json
+// @Param search_id path int true "Saved search ID"
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} map[string]interface{} "Matching schools or jobs with pagination metadata"
+// @Failure 400 {object} map[string]string "Invalid search ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Saved search not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/searches/{search_id}/results [get]
+func (h *SavedSearchHandler) GetSavedSearchResults(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+	search, lookupErr := h.findSavedSearch(c, userID)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	if search.Kind == models.SavedSearchJobs {
+		var params JobSearchParams
+		if err := decodeSearchFilters(search.Filters, &params); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Invalid stored filters: " + err.Error()})
+		}
+		if page < 1 {
+			page = 1
+		}
+		if limit < 1 || limit > 100 {
+			limit = 10
+		}
+		var jobs []models.Job
+		var total int64
+		if err := params.apply(h.db.Model(&models.Job{})).Count(&total).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve jobs: " + err.Error()})
+		}
+		err := params.apply(h.db).Order("created_at desc").Offset((page-1)*limit).Limit(limit).
+			Preload("InstitutionProfile", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "institution_name", "school_id", "is_verified", "verified_at")
+			}).Find(&jobs).Error
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve jobs: " + err.Error()})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"data": jobs,
+			"meta": fiber.Map{"total": total, "page": page, "limit": limit, "last_page": (total + int64(limit) - 1) / int64(limit)},
+		})
+	}
+
+	var params SchoolSearchParams
+	if err := decodeSearchFilters(search.Filters, &params); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Invalid stored filters: " + err.Error()})
+	}
+	params.Page, params.Limit = page, limit
+	params.normalize()
+	result, err := searchSchools(h.db, params)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error while fetching schools: " + err.Error()})
+	}
+	page, limit = params.Page, params.Limit
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data":   result.Schools,
+		"facets": result.Facets,
+		"meta":   fiber.Map{"total": result.Total, "page": page, "limit": limit, "last_page": (result.Total + int64(limit) - 1) / int64(limit)},
+	})
+}
+
+// savedSearchMatch is a school or job newly matching a saved search, as named in the alert
+type savedSearchMatch struct {
+	ID    uint
+	Title string
+}
+
+// newMatches records the schools or jobs that match a saved search and were added since it was saved, and
+// that it has not alerted about. It returns their number and the first ones. Edits to existing schools do
+// not count, since the alert announces new ones.
+func (h *SavedSearchHandler) newMatches(db *gorm.DB, search models.SavedSearch, now time.Time) (int, []savedSearchMatch, error) {
+	alerted := db.Model(&models.SavedSearchMatch{}).Select("target_id").Where("saved_search_id = ?", search.ID)
+	var query *gorm.DB
+	var titleColumn string
+	switch search.Kind {
+	case models.SavedSearchSchools:
+		var params SchoolSearchParams
+		if err := decodeSearchFilters(search.Filters, &params); err != nil {
+			return 0, nil, err
+		}
+		query = params.apply(db.Model(&models.School{}), "").
+			Where("schools.created_at > ? AND schools.id NOT IN (?)", search.CreatedAt, alerted)
+		titleColumn = "schools.id, schools.name AS title"
+	case models.SavedSearchJobs:
+		var params JobSearchParams
+		if err := decodeSearchFilters(search.Filters, &params); err != nil {
+			return 0, nil, err
+		}
+		query = params.apply(db.Model(&models.Job{})).
+			Where("jobs.created_at > ? AND jobs.id NOT IN (?)", search.CreatedAt, alerted)
+		titleColumn = "jobs.id, jobs.title AS title"
+	default:
+		return 0, nil, fmt.Errorf("unknown saved search kind %q", search.Kind)
+	}
+
+	var matches []savedSearchMatch
+	if err := query.Select(titleColumn).Order("id").Scan(&matches).Error; err != nil {
+		return 0, nil, err
+	}
+	if len(matches) == 0 {
+		return 0, nil, nil
+	}
+	records := make([]models.SavedSearchMatch, len(matches))
+	for i, match := range matches {
+		records[i] = models.SavedSearchMatch{SavedSearchID: search.ID, TargetID: match.ID, CreatedAt: now}
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(records, 1000).Error; err != nil {
+		return 0, nil, err
+	}
+	return len(matches), matches[:min(len(matches), maxSavedSearchAlertItems)], nil
+}
+
+// RunDueSavedSearches runs the daily and weekly saved searches that are due and alerts their users about new
+// matches.
+func (h *SavedSearchHandler) RunDueSavedSearches(ctx context.Context) error {
+	db := h.db.WithContext(ctx)
+	now := time.Now()
+
+	var searches []models.SavedSearch
+	err := db.Preload("User").
+		Joins("JOIN users ON users.id = saved_searches.user_id AND users.deleted_at IS NULL AND users.is_active = ?", true).
+		Where("(saved_searches.last_run_at IS NULL OR (saved_searches.frequency = ? AND saved_searches.last_run_at <= ?) OR (saved_searches.frequency = ? AND saved_searches.last_run_at <= ?))",
+			models.SavedSearchDaily, now.Add(-savedSearchPeriods[models.SavedSearchDaily]+digestSchedulingSlack),
+			models.SavedSearchWeekly, now.Add(-savedSearchPeriods[models.SavedSearchWeekly]+digestSchedulingSlack)).
+		Where("saved_searches.frequency <> ?", models.SavedSearchNever).
+		Order("saved_searches.id").Find(&searches).Error
+	if err != nil {
+		return fmt.Errorf("failed to find due saved searches: %w", err)
+	}
+
+	for _, search := range searches {
+		var count int
+		var matches []savedSearchMatch
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if count, matches, err = h.newMatches(tx, search, now); err != nil {
+				return err
+			}
+			updates := map[string]interface{}{"last_run_at": now}
+			if count > 0 {
+				updates["last_matched_at"] = now
+			}
+			return tx.Model(&models.SavedSearch{}).Where("id = ?", search.ID).Updates(updates).Error
+		})
+		if err != nil {
+			log.Printf("Saved searches: failed to run search %d: %v", search.ID, err)
+			continue
+		}
+		if count > 0 {
+			h.alert(search, count, matches)
+			LogUserAction(h.db, 0, "SYSTEM_SAVED_SEARCH_ALERT_SENT", search.ID, "SavedSearch", fmt.Sprintf("%d new match(es)", count), nil)
+		}
+	}
+	return nil
+}
+
+// alert notifies the user of a saved search in-app and by email about its new matches
+func (h *SavedSearchHandler) alert(search models.SavedSearch, count int, matches []savedSearchMatch) {
+	noun := strings.TrimSuffix(string(search.Kind), "s")
+	subject := fmt.Sprintf("1 new %s matches your search %q", noun, search.Name)
+	if count > 1 {
+		subject = fmt.Sprintf("%d new %ss match your search %q", count, noun, search.Name)
+	}
+	titles := make([]string, len(matches))
+	ids := make([]uint, len(matches))
+	for i, match := range matches {
+		titles[i], ids[i] = match.Title, match.ID
+	}
+	summary := strings.Join(titles, ", ")
+	if count > len(matches) {
+		summary += fmt.Sprintf(" and %d more", count-len(matches))
+	}
+
+	h.notifier.Notify(models.Notification{
+		UserID:   search.UserID,
+		Category: models.NotificationCategorySavedSearches,
+		Type:     models.NotificationTypeSavedSearchMatches,
+		Title:    subject,
+		Body:     summary,
+		Payload:  map[string]interface{}{"saved_search_id": search.ID, "kind": search.Kind, "count": count, "ids": ids},
+		DeepLink: fmt.Sprintf("/searches/%d", search.ID),
+	})
+
+	name := search.User.FirstName
+	if name == "" {
+		name = search.User.Email
+	}
+	var list strings.Builder
+	for _, title := range titles {
+		fmt.Fprintf(&list, "<li>%s</li>", html.EscapeString(title))
+	}
+	if count > len(matches) {
+		fmt.Fprintf(&list, "<li>and %d more</li>", count-len(matches))
+	}
+	htmlBody := fmt.Sprintf("<h1>Hi %s,</h1><p>%s:</p><ul>%s</ul><p>Please log in to see the details.</p><p>Thank you,<br/>The Platform Team</p>",
+		html.EscapeString(name), html.EscapeString(subject), list.String())
+	item := models.DigestItem{Title: subject, Summary: summary, SourceType: "SavedSearch", SourceID: search.ID}
+	if _, err := deliverNotificationEmail(h.db, h.emailService, h.cfg, search.User, models.NotificationCategorySavedSearches, subject, htmlBody, item); err != nil {
+		log.Printf("Saved searches: failed to email user %d about search %d: %v", search.UserID, search.ID, err)
+	}
+}
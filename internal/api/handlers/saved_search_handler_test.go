@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mwc_backend/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeSearchFilters(t *testing.T) {
+	cases := []struct {
+		name    string
+		kind    models.SavedSearchKind
+		filters map[string]interface{}
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{"school filters are trimmed and lists normalized", models.SavedSearchSchools,
+			map[string]interface{}{"q": "  casa ", "city": " Rome", "country_code": "IT ", "programs": []interface{}{"Primary", "primary, elementary"}, "languages": []interface{}{"EN"}},
+			map[string]interface{}{"q": "casa", "city": "Rome", "country_code": "IT", "programs": []interface{}{"primary", "elementary"}, "languages": []interface{}{"en"}}, ""},
+		{"location filters are kept", models.SavedSearchSchools,
+			map[string]interface{}{"lat": 41.9, "lng": 12.5, "radius_km": 10, "age_months": 36},
+			map[string]interface{}{"lat": 41.9, "lng": 12.5, "radius_km": 10.0, "age_months": 36.0}, ""},
+		{"antimeridian box", models.SavedSearchSchools,
+			map[string]interface{}{"bbox": []interface{}{170, -20, -170, -10}},
+			map[string]interface{}{"bbox": []interface{}{170.0, -20.0, -170.0, -10.0}}, ""},
+		{"no filters", models.SavedSearchSchools, nil, map[string]interface{}{}, ""},
+		{"empty values are dropped", models.SavedSearchSchools,
+			map[string]interface{}{"q": "   ", "programs": []interface{}{" , "}}, map[string]interface{}{}, ""},
+		{"job filters are trimmed", models.SavedSearchJobs,
+			map[string]interface{}{"q": " guide ", "location": " Accra ", "employment_type": "Full-time"},
+			map[string]interface{}{"q": "guide", "location": "Accra", "employment_type": "Full-time"}, ""},
+
+		{"unknown school filter", models.SavedSearchSchools, map[string]interface{}{"colour": "blue"}, nil, "unknown field"},
+		{"paging is not a filter", models.SavedSearchSchools, map[string]interface{}{"page": 2}, nil, "unknown field"},
+		{"job filter on a school search", models.SavedSearchSchools, map[string]interface{}{"location": "Accra"}, nil, "unknown field"},
+		{"school filter on a job search", models.SavedSearchJobs, map[string]interface{}{"programs": []interface{}{"primary"}}, nil, "unknown field"},
+		{"wrong type", models.SavedSearchSchools, map[string]interface{}{"lat": "north"}, nil, "invalid school search filters"},
+		{"unknown program", models.SavedSearchSchools, map[string]interface{}{"programs": []interface{}{"kindergarten"}}, nil, "program must be one or more of"},
+		{"invalid language", models.SavedSearchSchools, map[string]interface{}{"languages": []interface{}{"english"}}, nil, "language must be"},
+		{"negative age", models.SavedSearchSchools, map[string]interface{}{"age_months": -1}, nil, "age_months"},
+		{"radius without a location", models.SavedSearchSchools, map[string]interface{}{"radius_km": 10}, nil, "radius_km requires lat and lng"},
+		{"latitude alone", models.SavedSearchSchools, map[string]interface{}{"lat": 41.9}, nil, "latitude and longitude must be given together"},
+		{"box with south above north", models.SavedSearchSchools, map[string]interface{}{"bbox": []interface{}{12.3, 42, 12.6, 41.8}}, nil, "bbox"},
+		{"unknown kind", models.SavedSearchKind("teachers"), map[string]interface{}{}, nil, "kind must be schools or jobs"},
+	}
+	for _, tc := range cases {
+		got, err := normalizeSearchFilters(tc.kind, tc.filters)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("%s: error = %v, want %q", tc.name, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+		// Stored filters normalize to themselves
+		if again, err := normalizeSearchFilters(tc.kind, got); err != nil || !reflect.DeepEqual(again, got) {
+			t.Errorf("%s: normalizing again gave %v, %v", tc.name, again, err)
+		}
+	}
+}
+
+func TestValidateSavedSearch(t *testing.T) {
+	req := SavedSearchRequest{Name: "  Schools near Rome ", Kind: models.SavedSearchSchools, Filters: map[string]interface{}{"city": " Rome "}}
+	if fieldErrors := validateSavedSearch(&req); len(fieldErrors) > 0 {
+		t.Fatalf("validateSavedSearch: %v", fieldErrors)
+	}
+	if req.Name != "Schools near Rome" || req.Frequency != models.SavedSearchDaily || req.Filters["city"] != "Rome" {
+		t.Errorf("validated request = %+v", req)
+	}
+
+	req = SavedSearchRequest{Name: "Jobs", Kind: models.SavedSearchJobs, Filters: map[string]interface{}{"city": "Rome"}}
+	if fieldErrors := validateSavedSearch(&req); len(fieldErrors) != 1 || fieldErrors[0].Field != "filters" {
+		t.Errorf("a school filter on a job search gave %v, want a filters error", fieldErrors)
+	}
+	for _, req := range []SavedSearchRequest{
+		{Name: "   ", Kind: models.SavedSearchSchools},
+		{Name: "Schools", Kind: "teachers"},
+		{Name: "Schools", Kind: models.SavedSearchSchools, Frequency: "hourly"},
+	} {
+		if fieldErrors := validateSavedSearch(&req); len(fieldErrors) == 0 {
+			t.Errorf("validateSavedSearch(%+v) succeeded, want errors", req)
+		}
+	}
+}
+
+func TestSavedSearchNewMatches(t *testing.T) {
+	db := openTestDB(t)
+	h := &SavedSearchHandler{db: db}
+	user := createTestUser(t, db, models.ParentRole)
+	savedAt := time.Now().Add(-time.Hour)
+	// A unique city keeps the schools apart from any already in the database
+	city := fmt.Sprintf("Alertville%d", time.Now().UnixNano())
+
+	existing := models.School{GormModel: models.GormModel{CreatedAt: savedAt.Add(-time.Hour)}, Name: "Existing School", City: city, CountryCode: "IT"}
+	added := models.School{Name: "New School", City: city, CountryCode: "IT"}
+	elsewhere := models.School{Name: "New School Elsewhere", City: "Somewhere else", CountryCode: "IT"}
+	for _, school := range []*models.School{&existing, &added, &elsewhere} {
+		if err := db.Create(school).Error; err != nil {
+			t.Fatalf("creating %s: %v", school.Name, err)
+		}
+	}
+	search := models.SavedSearch{GormModel: models.GormModel{CreatedAt: savedAt}, UserID: user.ID, Name: "Alertville",
+		Kind: models.SavedSearchSchools, Filters: map[string]interface{}{"city": city}, Frequency: models.SavedSearchDaily}
+	if err := db.Create(&search).Error; err != nil {
+		t.Fatalf("creating the saved search: %v", err)
+	}
+
+	count, matches, err := h.newMatches(db, search, time.Now())
+	if err != nil {
+		t.Fatalf("newMatches: %v", err)
+	}
+	if count != 1 || len(matches) != 1 || matches[0].ID != added.ID || matches[0].Title != "New School" {
+		t.Errorf("newMatches = %d, %+v, want only the school added after the search was saved", count, matches)
+	}
+
+	// Schools are alerted about once, and edits to schools that existed before do not count as new
+	if err := db.Model(&existing).Update("name", "Existing School Renamed").Error; err != nil {
+		t.Fatal(err)
+	}
+	if count, matches, err = h.newMatches(db, search, time.Now()); err != nil || count != 0 {
+		t.Errorf("second run = %d, %+v, %v, want no new matches", count, matches, err)
+	}
+
+	// Jobs follow the same rule
+	institution := createTestUser(t, db, models.InstitutionRole)
+	profile := models.InstitutionProfile{UserID: institution.ID, InstitutionName: "Casa dei Bambini"}
+	if err := db.Create(&profile).Error; err != nil {
+		t.Fatalf("creating the institution profile: %v", err)
+	}
+	oldJob := models.Job{GormModel: models.GormModel{CreatedAt: savedAt.Add(-time.Hour)}, InstitutionProfileID: profile.ID, Title: "Primary Guide", Location: city, IsActive: true}
+	newJob := models.Job{InstitutionProfileID: profile.ID, Title: "Elementary Guide", Location: city, IsActive: true}
+	for _, job := range []*models.Job{&oldJob, &newJob} {
+		if err := db.Create(job).Error; err != nil {
+			t.Fatalf("creating job %s: %v", job.Title, err)
+		}
+	}
+	jobSearch := models.SavedSearch{GormModel: models.GormModel{CreatedAt: savedAt}, UserID: user.ID, Name: "Guides",
+		Kind: models.SavedSearchJobs, Filters: map[string]interface{}{"location": city}, Frequency: models.SavedSearchDaily}
+	if err := db.Create(&jobSearch).Error; err != nil {
+		t.Fatalf("creating the saved job search: %v", err)
+	}
+	count, matches, err = h.newMatches(db, jobSearch, time.Now())
+	if err != nil || count != 1 || len(matches) != 1 || matches[0].ID != newJob.ID || matches[0].Title != "Elementary Guide" {
+		t.Errorf("job newMatches = %d, %+v, %v, want only the job posted after the search was saved", count, matches, err)
+	}
+}
+
+func TestCreateSavedSearchLimit(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, models.ParentRole)
+	other := createTestUser(t, db, models.ParentRole)
+	newSearch := func(userID uint, i int) *models.SavedSearch {
+		return &models.SavedSearch{UserID: userID, Name: fmt.Sprintf("Search %d", i), Kind: models.SavedSearchSchools,
+			Filters: map[string]interface{}{}, Frequency: models.SavedSearchDaily}
+	}
+
+	for i := 0; i < maxSavedSearches; i++ {
+		if err := createSavedSearch(db, newSearch(user.ID, i)); err != nil {
+			t.Fatalf("saving search %d: %v", i, err)
+		}
+	}
+	if err := createSavedSearch(db, newSearch(user.ID, maxSavedSearches)); !errors.Is(err, errTooManySavedSearches) {
+		t.Errorf("saving one search too many: %v, want errTooManySavedSearches", err)
+	}
+	if err := createSavedSearch(db, newSearch(other.ID, 0)); err != nil {
+		t.Errorf("the limit of one user applied to another: %v", err)
+	}
+	var count int64
+	if err := db.Model(&models.SavedSearch{}).Where("user_id = ?", user.ID).Count(&count).Error; err != nil || count != maxSavedSearches {
+		t.Errorf("user has %d saved searches, %v, want %d", count, err, maxSavedSearches)
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"mwc_backend/internal/models"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// schoolProgramAgeMonths is the usual age range of each program in months, used when a school gives programs
+// without an age range
+var schoolProgramAgeMonths = map[models.SchoolProgram][2]int{
+	models.ProgramToddler:    {18, 36},
+	models.ProgramPrimary:    {36, 72},
+	models.ProgramElementary: {72, 144},
+	models.ProgramAdolescent: {144, 216},
+}
+
+// Values accepted by the school attribute filters
+var (
+	schoolPrograms       = []string{string(models.ProgramToddler), string(models.ProgramPrimary), string(models.ProgramElementary), string(models.ProgramAdolescent)}
+	schoolSchedules      = []string{string(models.ScheduleHalfDay), string(models.ScheduleFullDay)}
+	schoolTuitionBands   = []string{string(models.TuitionFree), string(models.TuitionLow), string(models.TuitionMedium), string(models.TuitionHigh)}
+	schoolAccreditations = []string{string(models.AccreditationAMI), string(models.AccreditationAMS), string(models.AccreditationIMC), string(models.AccreditationMSA), string(models.AccreditationOther)}
+)
+
+// schoolAttributeColumns are the columns written when a school's attributes are updated
+var schoolAttributeColumns = []string{"programs", "age_min_months", "age_max_months", "languages", "tuition_band", "schedules", "accreditations"}
+
+// SchoolAttributesRequest is the Montessori profile of a school. It replaces all attributes; omitted lists are
+// cleared. Without an age range, the range is derived from the programs.
+type SchoolAttributesRequest struct {
+	Programs       []models.SchoolProgram       `json:"programs" validate:"unique,dive,oneof=toddler primary elementary adolescent"`
+	AgeMinMonths   *int                         `json:"age_min_months,omitempty" validate:"omitempty,min=0,max=240"`
+	AgeMaxMonths   *int                         `json:"age_max_months,omitempty" validate:"omitempty,min=0,max=240"`
+	Languages      []string                     `json:"languages" validate:"unique,dive,len=2,alpha"` // ISO 639-1 codes, e.g. "en"
+	TuitionBand    models.SchoolTuitionBand     `json:"tuition_band" validate:"omitempty,oneof=free low medium high"`
+	Schedules      []models.SchoolSchedule      `json:"schedules" validate:"unique,dive,oneof=half_day full_day"`
+	Accreditations []models.SchoolAccreditation `json:"accreditations" validate:"unique,dive,oneof=ami ams imc msa other"`
+}
+
+// validateSchoolAttributes normalizes the request and returns its field errors
+func validateSchoolAttributes(req *SchoolAttributesRequest) []FieldError {
+	for i, language := range req.Languages {
+		req.Languages[i] = strings.ToLower(strings.TrimSpace(language))
+	}
+	fieldErrors := validationErrors(req)
+	if req.AgeMinMonths != nil && req.AgeMaxMonths != nil && *req.AgeMinMonths > *req.AgeMaxMonths {
+		fieldErrors = append(fieldErrors, FieldError{Field: "age_max_months", Message: "must be at least age_min_months"})
+	}
+	if (req.AgeMinMonths == nil) != (req.AgeMaxMonths == nil) {
+		fieldErrors = append(fieldErrors, FieldError{Field: "age_min_months", Message: "must be given together with age_max_months"})
+	}
+	return fieldErrors
+}
+
+// applySchoolAttributes copies the attributes onto the school. Lists are never nil so they are stored as [].
+func applySchoolAttributes(school *models.School, req SchoolAttributesRequest) {
+	school.Programs = append([]models.SchoolProgram{}, req.Programs...)
+	school.Languages = append([]string{}, req.Languages...)
+	school.TuitionBand = req.TuitionBand
+	school.Schedules = append([]models.SchoolSchedule{}, req.Schedules...)
+	school.Accreditations = append([]models.SchoolAccreditation{}, req.Accreditations...)
+	school.AgeMinMonths, school.AgeMaxMonths = req.AgeMinMonths, req.AgeMaxMonths
+
+	if school.AgeMinMonths == nil && len(school.Programs) > 0 {
+		ageMin, ageMax := 240, 0
+		for _, program := range school.Programs {
+			ages := schoolProgramAgeMonths[program]
+			ageMin, ageMax = min(ageMin, ages[0]), max(ageMax, ages[1])
+		}
+		school.AgeMinMonths, school.AgeMaxMonths = &ageMin, &ageMax
+	}
+}
+
+// updateSchoolAttributes validates and saves the attributes of a school
+func updateSchoolAttributes(db *gorm.DB, school *models.School, req SchoolAttributesRequest) ([]FieldError, error) {
+	if fieldErrors := validateSchoolAttributes(&req); len(fieldErrors) > 0 {
+		return fieldErrors, nil
+	}
+	applySchoolAttributes(school, req)
+	return nil, db.Model(school).Select(schoolAttributeColumns).Updates(school).Error
+}
+
+// parseAttributeFilter reads a comma-separated filter and checks every value against the allowed ones
+func parseAttributeFilter(name, value string, allowed []string) ([]string, error) {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" {
+			continue
+		}
+		if allowed != nil && !slices.Contains(allowed, item) {
+			return nil, fmt.Errorf("%s must be one or more of %s", name, strings.Join(allowed, ", "))
+		}
+		if !slices.Contains(values, item) {
+			values = append(values, item)
+		}
+	}
+	return values, nil
+}
+
+// jsonbContainsAny matches rows whose JSON array column holds at least one of the values. Each value is
+// matched with @> so the column's GIN index is used.
+func jsonbContainsAny(column string, values []string) (string, []interface{}) {
+	conditions := make([]string, 0, len(values))
+	vars := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		encoded, _ := json.Marshal([]string{value})
+		conditions = append(conditions, column+" @> ?::jsonb")
+		vars = append(vars, string(encoded))
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", vars
+}
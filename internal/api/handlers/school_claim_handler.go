@@ -0,0 +1,602 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"mime/multipart"
+	"mwc_backend/config"
+	"mwc_backend/internal/email"
+	"mwc_backend/internal/models"
+	"mwc_backend/internal/storage"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaxSchoolClaimDocuments is the number of verification documents a claim may include
+const MaxSchoolClaimDocuments = 5
+
+// schoolClaimDocumentTypes are the detected content types accepted as verification documents
+var schoolClaimDocumentTypes = []string{"application/pdf", "image/jpeg", "image/png"}
+
+var (
+	errSchoolClaimReviewed = errors.New("claim has already been reviewed")
+	errSchoolClaimTaken    = errors.New("school is already managed by another verified institution")
+)
+
+// SchoolClaimHandler handles institutions' claims to manage a school and their review by admins
+type SchoolClaimHandler struct {
+	db           *gorm.DB
+	cfg          *config.Config
+	store        storage.Storage // Nil when the storage backend could not be initialized
+	scanner      storage.Scanner
+	notifier     *Notifier
+	emailService email.EmailService
+}
+
+// NewSchoolClaimHandler creates a new SchoolClaimHandler. store may be nil, in which case claims cannot be submitted.
+func NewSchoolClaimHandler(db *gorm.DB, cfg *config.Config, store storage.Storage, scanner storage.Scanner, notifier *Notifier, emailService email.EmailService) *SchoolClaimHandler {
+	if scanner == nil {
+		scanner = storage.NoopScanner{}
+	}
+	return &SchoolClaimHandler{db: db, cfg: cfg, store: store, scanner: scanner, notifier: notifier, emailService: emailService}
+}
+
+// ReviewSchoolClaimRequest is the admin's decision on a school claim
+type ReviewSchoolClaimRequest struct {
+	Status models.SchoolClaimStatus `json:"status" validate:"required,oneof=approved rejected"`
+	Notes  string                   `json:"notes"` // Shown to the institution
+}
+
+// refreshSchoolOwnership recomputes the ownership status of a school from its claims
+func refreshSchoolOwnership(db *gorm.DB, schoolID uint) error {
+	return db.Exec("UPDATE schools SET ownership_status = "+models.SchoolOwnershipStatusSQL+" WHERE id = ?", schoolID).Error
+}
+
+// SubmitSchoolClaim lets an institution claim a school
+// @Summary Claim a school
+// @Description Submits a claim to manage a school, with verification documents as multipart form fields `documents` (1 to 5 PDF, JPEG or PNG files of at most ATTACHMENT_MAX_SIZE_MB each) and an optional `message` to the reviewer. The school is pending until an admin approves or rejects the claim; on approval the institution is mapped to the school and marked verified. An institution can have one pending claim at a time.
+// @Tags institution,schools
+// @Accept multipart/form-data
+// @Produce json
+// @Param school_id path int true "School ID"
+// @Param documents formData file true "Verification documents"
+// @Param message formData string false "Message to the reviewer"
+// @Success 201 {object} models.SchoolClaim "Claim submitted"
+// @Failure 400 {object} map[string]string "Invalid school ID or missing documents"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Institution profile or school not found"
+// @Failure 409 {object} map[string]string "Institution already has a school or a pending claim, or the school is already verified"
+// @Failure 413 {object} map[string]string "Document too large"
+// @Failure 415 {object} map[string]string "Document type not allowed"
+// @Failure 422 {object} map[string]string "Document rejected by the virus scanner"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "File storage unavailable"
+// @Security BearerAuth
+// @Router /api/v1/institution/schools/{school_id}/claims [post]
+func (h *SchoolClaimHandler) SubmitSchoolClaim(c *fiber.Ctx) error {
+	actorUserID, _ := c.Locals("user_id").(uint)
+	schoolID, err := strconv.ParseUint(c.Params("school_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid school ID format"})
+	}
+	schoolID = uint64(resolveSchoolID(h.db, uint(schoolID))) // Claim the surviving school of a merged school
+	if h.store == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "File storage is not available"})
+	}
+
+	var profile models.InstitutionProfile
+	if err := h.db.Where("user_id = ?", actorUserID).First(&profile).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Institution profile not found."})
+	}
+	if profile.SchoolID != nil && *profile.SchoolID != 0 {
+		LogUserAction(h.db, actorUserID, "SCHOOL_CLAIM_FAIL_ALREADY_MAPPED", uint(schoolID), "School", "Institution already has a school", c)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Institution already has a school."})
+	}
+
+	var school models.School
+	if err := h.db.First(&school, uint(schoolID)).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "School not found."})
+	}
+	if school.OwnershipStatus == models.SchoolOwnershipVerified {
+		LogUserAction(h.db, actorUserID, "SCHOOL_CLAIM_FAIL_VERIFIED", school.ID, "School", "School already verified", c)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "This school is already managed by a verified institution."})
+	}
+	var pendingClaims int64
+	h.db.Model(&models.SchoolClaim{}).Where("institution_profile_id = ? AND status = ?", profile.ID, models.SchoolClaimPending).Count(&pendingClaims)
+	if pendingClaims > 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "You already have a pending claim. Withdraw it before claiming another school."})
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["documents"]) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "At least one verification document must be uploaded in the 'documents' form field"})
+	}
+	files := form.File["documents"]
+	if len(files) > MaxSchoolClaimDocuments {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("At most %d documents can be uploaded", MaxSchoolClaimDocuments)})
+	}
+
+	documents := make([]models.SchoolClaimDocument, 0, len(files))
+	for _, fileHeader := range files {
+		document, uploadErr := h.storeDocument(c.Context(), actorUserID, fileHeader)
+		if uploadErr != nil {
+			h.deleteDocuments(documents)
+			LogUserAction(h.db, actorUserID, "SCHOOL_CLAIM_FAIL_DOCUMENT", school.ID, "School", uploadErr.Message, c)
+			return c.Status(uploadErr.Code).JSON(fiber.Map{"error": uploadErr.Message})
+		}
+		documents = append(documents, document)
+	}
+
+	claim := models.SchoolClaim{
+		SchoolID:             school.ID,
+		InstitutionProfileID: profile.ID,
+		Status:               models.SchoolClaimPending,
+		Message:              strings.TrimSpace(c.FormValue("message")),
+		Documents:            documents,
+	}
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&claim).Error; err != nil {
+			return err
+		}
+		return refreshSchoolOwnership(tx, school.ID)
+	})
+	if err != nil {
+		h.deleteDocuments(documents)
+		LogUserAction(h.db, actorUserID, "SCHOOL_CLAIM_FAIL_DB", school.ID, "School", err.Error(), c)
+		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "You already have a pending claim. Withdraw it before claiming another school."})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to submit claim: " + err.Error()})
+	}
+
+	LogUserAction(h.db, actorUserID, "SCHOOL_CLAIM_SUBMITTED", claim.ID, "SchoolClaim", fmt.Sprintf("Claim of school %d with %d document(s)", school.ID, len(documents)), c)
+	h.notifyAdminsOfClaim(claim, school, profile)
+	school.OwnershipStatus = models.SchoolOwnershipPending
+	claim.School = school
+	return c.Status(fiber.StatusCreated).JSON(claim)
+}
+
+// storeDocument checks an uploaded verification document and stores it
+func (h *SchoolClaimHandler) storeDocument(ctx context.Context, userID uint, fileHeader *multipart.FileHeader) (models.SchoolClaimDocument, *fiber.Error) {
+	maxSize := int64(h.cfg.AttachmentMaxSizeMB) * 1024 * 1024
+	fileName := sanitizeFileName(fileHeader.Filename)
+	tooLarge := fiber.NewError(fiber.StatusRequestEntityTooLarge, fmt.Sprintf("%s is larger than %d MB", fileName, h.cfg.AttachmentMaxSizeMB))
+	if fileHeader.Size > maxSize {
+		return models.SchoolClaimDocument{}, tooLarge
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return models.SchoolClaimDocument{}, fiber.NewError(fiber.StatusBadRequest, "Cannot read uploaded file: "+err.Error())
+	}
+	defer file.Close()
+	// Read at most one byte more than allowed so a wrong size header cannot bypass the limit
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return models.SchoolClaimDocument{}, fiber.NewError(fiber.StatusBadRequest, "Cannot read uploaded file: "+err.Error())
+	}
+	if int64(len(data)) > maxSize {
+		return models.SchoolClaimDocument{}, tooLarge
+	}
+	if len(data) == 0 {
+		return models.SchoolClaimDocument{}, fiber.NewError(fiber.StatusBadRequest, fileName+" is empty")
+	}
+
+	// PDF, JPEG and PNG are all recognised from their content, the file name is never trusted
+	contentType := sniffContentType(data)
+	if !slices.Contains(schoolClaimDocumentTypes, contentType) {
+		return models.SchoolClaimDocument{}, fiber.NewError(fiber.StatusUnsupportedMediaType, fmt.Sprintf("%s is not a PDF, JPEG or PNG file", fileName))
+	}
+	if err := h.scanner.Scan(ctx, fileName, bytes.NewReader(data)); err != nil {
+		if errors.Is(err, storage.ErrInfected) {
+			return models.SchoolClaimDocument{}, fiber.NewError(fiber.StatusUnprocessableEntity, fileName+" was rejected by the virus scanner")
+		}
+		log.Printf("Error scanning claim document from user %d: %v", userID, err)
+		return models.SchoolClaimDocument{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to scan file")
+	}
+
+	document := models.SchoolClaimDocument{
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  fmt.Sprintf("school-claims/%s/%s%s", time.Now().UTC().Format("2006/01"), uuid.NewString(), strings.ToLower(filepath.Ext(fileName))),
+	}
+	if err := h.store.Put(ctx, document.StorageKey, bytes.NewReader(data), document.Size, contentType); err != nil {
+		log.Printf("Error storing claim document from user %d: %v", userID, err)
+		return models.SchoolClaimDocument{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to store file")
+	}
+	return document, nil
+}
+
+// deleteDocuments removes stored documents of a claim that could not be saved
+func (h *SchoolClaimHandler) deleteDocuments(documents []models.SchoolClaimDocument) {
+	for _, document := range documents {
+		if err := h.store.Delete(context.Background(), document.StorageKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Error deleting claim document %s: %v", document.StorageKey, err)
+		}
+	}
+}
+
+// GetMySchoolClaims lists the claims of the logged-in institution
+// @Summary List my school claims
+// @Description Retrieves the institution's school claims with their review status, newest first
+// @Tags institution,schools
+// @Produce json
+// @Success 200 {array} models.SchoolClaim "School claims"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Institution profile not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/institution/claims [get]
+func (h *SchoolClaimHandler) GetMySchoolClaims(c *fiber.Ctx) error {
+	actorUserID, _ := c.Locals("user_id").(uint)
+
+	var profile models.InstitutionProfile
+	if err := h.db.Where("user_id = ?", actorUserID).First(&profile).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Institution profile not found."})
+	}
+
+	var claims []models.SchoolClaim
+	if err := h.db.Preload("School").Preload("Documents").Where("institution_profile_id = ?", profile.ID).
+		Order("created_at desc").Find(&claims).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve claims: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(claims)
+}
+
+// WithdrawSchoolClaim lets an institution withdraw its pending claim
+// @Summary Withdraw a school claim
+// @Description Withdraws a claim of the logged-in institution that has not been reviewed yet
+// @Tags institution,schools
+// @Produce json
+// @Param claim_id path int true "Claim ID"
+// @Success 200 {object} models.SchoolClaim "Withdrawn claim"
+// @Failure 400 {object} map[string]string "Invalid claim ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Institution profile or claim not found"
+// @Failure 409 {object} map[string]string "Claim already reviewed"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/institution/claims/{claim_id} [delete]
+func (h *SchoolClaimHandler) WithdrawSchoolClaim(c *fiber.Ctx) error {
+	actorUserID, _ := c.Locals("user_id").(uint)
+	claimID, err := strconv.ParseUint(c.Params("claim_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid claim ID format"})
+	}
+
+	var profile models.InstitutionProfile
+	if err := h.db.Where("user_id = ?", actorUserID).First(&profile).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Institution profile not found."})
+	}
+
+	var claim models.SchoolClaim
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND institution_profile_id = ?", uint(claimID), profile.ID).First(&claim).Error; err != nil {
+			return err
+		}
+		if claim.Status != models.SchoolClaimPending {
+			return errSchoolClaimReviewed
+		}
+		claim.Status = models.SchoolClaimWithdrawn
+		if err := tx.Model(&claim).Update("status", claim.Status).Error; err != nil {
+			return err
+		}
+		// An institution mapped before claims were reviewed gives up the school with its claim
+		if profile.SchoolID != nil && *profile.SchoolID == claim.SchoolID {
+			if err := tx.Model(&profile).Update("school_id", nil).Error; err != nil {
+				return err
+			}
+		}
+		return refreshSchoolOwnership(tx, claim.SchoolID)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Claim not found"})
+		case errors.Is(err, errSchoolClaimReviewed):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Claim has already been reviewed"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to withdraw claim: " + err.Error()})
+	}
+
+	LogUserAction(h.db, actorUserID, "SCHOOL_CLAIM_WITHDRAWN", claim.ID, "SchoolClaim", fmt.Sprintf("Claim of school %d withdrawn", claim.SchoolID), c)
+	return c.Status(fiber.StatusOK).JSON(claim)
+}
+
+// GetSchoolClaims lists school claims for review (admin only)
+// @Summary List school claims
+// @Description Retrieves school claims with their school, institution and documents, oldest first so claims are reviewed in order
+// @Tags admin,schools
+// @Produce json
+// @Param status query string false "Filter by status (pending, approved, rejected, withdrawn)"
+// @Param school_id query int false "Filter by school"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} map[string]interface{} "School claims with pagination metadata"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/school-claims [get]
+func (h *SchoolClaimHandler) GetSchoolClaims(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	query := h.db.Model(&models.SchoolClaim{})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if schoolID := c.QueryInt("school_id"); schoolID > 0 {
+		query = query.Where("school_id = ?", schoolID)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var claims []models.SchoolClaim
+	if err := query.Preload("School").Preload("InstitutionProfile").Preload("Documents").
+		Order("created_at asc").Offset((page - 1) * limit).Limit(limit).Find(&claims).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve claims: " + err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": claims,
+		"meta": fiber.Map{
+			"total":     total,
+			"page":      page,
+			"limit":     limit,
+			"last_page": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// DownloadSchoolClaimDocument downloads a verification document of a claim (admin only)
+// @Summary Download a school claim document
+// @Description Downloads a verification document uploaded with a school claim
+// @Tags admin,schools
+// @Produce octet-stream
+// @Param claim_id path int true "Claim ID"
+// @Param document_id path int true "Document ID"
+// @Success 200 {file} file "Document content"
+// @Failure 400 {object} map[string]string "Invalid claim or document ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Document not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "File storage unavailable"
+// @Security BearerAuth
+// @Router /api/v1/admin/school-claims/{claim_id}/documents/{document_id} [get]
+func (h *SchoolClaimHandler) DownloadSchoolClaimDocument(c *fiber.Ctx) error {
+	claimID, err := strconv.ParseUint(c.Params("claim_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid claim ID format"})
+	}
+	documentID, err := strconv.ParseUint(c.Params("document_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid document ID format"})
+	}
+	if h.store == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "File storage is not available"})
+	}
+
+	var document models.SchoolClaimDocument
+	if err := h.db.Where("id = ? AND claim_id = ?", uint(documentID), uint(claimID)).First(&document).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Document not found"})
+	}
+	reader, err := h.store.Get(c.Context(), document.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Document not found"})
+		}
+		log.Printf("Error reading claim document %d: %v", document.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read document"})
+	}
+	c.Set(fiber.HeaderContentType, document.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, document.FileName))
+	// fasthttp closes the reader once the body has been sent
+	return c.Status(fiber.StatusOK).SendStream(reader)
+}
+
+// ReviewSchoolClaim approves or rejects a school claim (admin only)
+// @Summary Review a school claim
+// @Description Approves or rejects a pending school claim. Approving maps the institution to the school, marks the institution verified and the school verified, and rejects the other pending claims of the school. The institutions are notified of the decision.
+// @Tags admin,schools
+// @Accept json
+// @Produce json
+// @Param claim_id path int true "Claim ID"
+// @Param review body ReviewSchoolClaimRequest true "Decision and optional notes for the institution"
+// @Success 200 {object} models.SchoolClaim "Reviewed claim"
+// @Failure 400 {object} map[string]string "Invalid claim ID or request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Claim not found"
+// @Failure 409 {object} map[string]string "Claim already reviewed or school managed by another verified institution"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/school-claims/{claim_id}/review [put]
+func (h *SchoolClaimHandler) ReviewSchoolClaim(c *fiber.Ctx) error {
+	adminID, _ := c.Locals("user_id").(uint)
+	claimID, err := strconv.ParseUint(c.Params("claim_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid claim ID format"})
+	}
+	var req ReviewSchoolClaimRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+	}
+	if fieldErrors := validationErrors(req); len(fieldErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": validationErrorsMessage(fieldErrors), "fields": fieldErrors})
+	}
+
+	var claim models.SchoolClaim
+	var rejectedCompetitors []models.SchoolClaim
+	now := time.Now()
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&claim, uint(claimID)).Error; err != nil {
+			return err
+		}
+		if claim.Status != models.SchoolClaimPending {
+			return errSchoolClaimReviewed
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&claim.InstitutionProfile, claim.InstitutionProfileID).Error; err != nil {
+			return err
+		}
+		profile := &claim.InstitutionProfile
+
+		claim.Status = req.Status
+		claim.ReviewedBy = &adminID
+		claim.ReviewedAt = &now
+		claim.ReviewNotes = strings.TrimSpace(req.Notes)
+		if err := tx.Model(&claim).Select("status", "reviewed_by", "reviewed_at", "review_notes").Updates(&claim).Error; err != nil {
+			return err
+		}
+
+		if claim.Status == models.SchoolClaimRejected {
+			// An institution mapped before claims were reviewed loses the school with its rejected claim
+			if profile.SchoolID != nil && *profile.SchoolID == claim.SchoolID {
+				if err := tx.Model(profile).Update("school_id", nil).Error; err != nil {
+					return err
+				}
+				profile.SchoolID = nil
+			}
+			return refreshSchoolOwnership(tx, claim.SchoolID)
+		}
+
+		var holder models.InstitutionProfile
+		err := tx.Where("school_id = ? AND id <> ?", claim.SchoolID, profile.ID).Take(&holder).Error
+		if err == nil {
+			var approved int64
+			if err := tx.Model(&models.SchoolClaim{}).Where("institution_profile_id = ? AND school_id = ? AND status = ?",
+				holder.ID, claim.SchoolID, models.SchoolClaimApproved).Count(&approved).Error; err != nil {
+				return err
+			}
+			if approved > 0 {
+				return errSchoolClaimTaken
+			}
+			// The school was selected by another institution before claims were reviewed
+			if err := tx.Model(&holder).Update("school_id", nil).Error; err != nil {
+				return err
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		profile.SchoolID = &claim.SchoolID
+		profile.IsVerified = true
+		profile.VerifiedAt = &now
+		if err := tx.Model(profile).Select("school_id", "is_verified", "verified_at").Updates(profile).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("school_id = ? AND status = ?", claim.SchoolID, models.SchoolClaimPending).Find(&rejectedCompetitors).Error; err != nil {
+			return err
+		}
+		for i := range rejectedCompetitors {
+			rejectedCompetitors[i].Status = models.SchoolClaimRejected
+			rejectedCompetitors[i].ReviewedBy = &adminID
+			rejectedCompetitors[i].ReviewedAt = &now
+			rejectedCompetitors[i].ReviewNotes = "Another institution's claim to this school was approved."
+			if err := tx.Model(&rejectedCompetitors[i]).Select("status", "reviewed_by", "reviewed_at", "review_notes").
+				Updates(&rejectedCompetitors[i]).Error; err != nil {
+				return err
+			}
+		}
+		return refreshSchoolOwnership(tx, claim.SchoolID)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Claim not found"})
+		case errors.Is(err, errSchoolClaimReviewed), errors.Is(err, errSchoolClaimTaken):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		LogUserAction(h.db, adminID, "SCHOOL_CLAIM_REVIEW_FAIL", uint(claimID), "SchoolClaim", err.Error(), c)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to review claim: " + err.Error()})
+	}
+
+	h.db.First(&claim.School, claim.SchoolID)
+	LogUserAction(h.db, adminID, "SCHOOL_CLAIM_REVIEWED", claim.ID, "SchoolClaim", fmt.Sprintf("Claim of school %d %s", claim.SchoolID, claim.Status), c)
+	h.notifyInstitutionOfReview(claim, claim.School)
+	for _, competitor := range rejectedCompetitors {
+		h.notifyInstitutionOfReview(competitor, claim.School)
+	}
+	return c.Status(fiber.StatusOK).JSON(claim)
+}
+
+// notifyAdminsOfClaim tells every active admin that a claim awaits review
+func (h *SchoolClaimHandler) notifyAdminsOfClaim(claim models.SchoolClaim, school models.School, profile models.InstitutionProfile) {
+	var adminIDs []uint
+	if err := h.db.Model(&models.User{}).Where("role = ? AND is_active = ?", models.AdminRole, true).Pluck("id", &adminIDs).Error; err != nil {
+		log.Printf("Failed to load admins to notify about school claim %d: %v", claim.ID, err)
+		return
+	}
+	h.notifier.NotifyUsers(adminIDs, models.Notification{
+		Category: models.NotificationCategorySchoolClaims,
+		Type:     models.NotificationTypeSchoolClaimSubmitted,
+		Title:    fmt.Sprintf("%s claimed %s", profile.InstitutionName, school.Name),
+		Body:     truncateMessage(claim.Message, 100),
+		Payload:  map[string]interface{}{"claim_id": claim.ID, "school_id": school.ID, "institution_profile_id": profile.ID},
+		DeepLink: "/admin/school-claims",
+	})
+}
+
+// notifyInstitutionOfReview tells an institution whether its claim was approved or rejected, in the app and by
+// email. Email failures are logged and do not fail the review.
+func (h *SchoolClaimHandler) notifyInstitutionOfReview(claim models.SchoolClaim, school models.School) {
+	var profile models.InstitutionProfile
+	if err := h.db.Preload("User").First(&profile, claim.InstitutionProfileID).Error; err != nil {
+		log.Printf("Failed to load institution to notify about school claim %d: %v", claim.ID, err)
+		return
+	}
+
+	subject := fmt.Sprintf("Your claim of %s was %s", school.Name, claim.Status)
+	body := "Your institution is now verified and manages this school."
+	if claim.Status == models.SchoolClaimRejected {
+		body = "Your claim could not be verified."
+	}
+	if claim.ReviewNotes != "" {
+		body += " Reviewer notes: " + claim.ReviewNotes
+	}
+	h.notifier.Notify(models.Notification{
+		UserID:   profile.UserID,
+		Category: models.NotificationCategorySchoolClaims,
+		Type:     models.NotificationTypeSchoolClaimReviewed,
+		Title:    subject,
+		Body:     body,
+		Payload:  map[string]interface{}{"claim_id": claim.ID, "school_id": claim.SchoolID, "status": claim.Status},
+		DeepLink: "/institution/claims",
+	})
+
+	htmlBody := fmt.Sprintf("<h1>Hi %s,</h1><p>%s</p><p>%s</p><p>Thank you,<br/>The Platform Team</p>",
+		html.EscapeString(profile.InstitutionName), html.EscapeString(subject), html.EscapeString(body))
+	item := models.DigestItem{
+		Title:      subject,
+		Summary:    body,
+		SourceType: "SchoolClaim",
+		SourceID:   claim.ID,
+	}
+	if _, err := deliverNotificationEmail(h.db, h.emailService, h.cfg, profile.User, models.NotificationCategorySchoolClaims, subject, htmlBody, item); err != nil {
+		log.Printf("Failed to email institution %d about school claim %d: %v", profile.ID, claim.ID, err)
+	}
+}
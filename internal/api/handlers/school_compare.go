@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"mwc_backend/internal/geo"
+	"mwc_backend/internal/models"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Number of schools that can be compared at once
+const (
+	minCompareSchools = 2
+	maxCompareSchools = 5
+)
+
+// SchoolRatingSummary is the average and distribution of a school's approved review ratings
+type SchoolRatingSummary struct {
+	Average      *float64         `json:"average"` // Rounded to two decimals, null without reviews
+	Count        int64            `json:"count"`
+	Distribution map[string]int64 `json:"distribution"` // Number of reviews per rating, "1" to "5", always all five
+}
+
+// SchoolComparison is one school of a comparison. Every school has every field, so they can be shown side by side.
+type SchoolComparison struct {
+	School         models.School       `json:"school"` // Profile and Montessori attributes, with logo and cover
+	Rating         SchoolRatingSummary `json:"rating"`
+	UpcomingEvents int64               `json:"upcoming_events"` // Published events of the school's institution that have not started yet
+	ActiveJobs     int64               `json:"active_jobs"`     // Open job postings of the school's institution, a proxy for staff turnover
+	DistanceKm     *float64            `json:"distance_km"`     // From the given location, null without one or without coordinates
+}
+
+// parseCompareSchoolIDs reads the comma-separated school IDs to compare
+func parseCompareSchoolIDs(value string) ([]uint, error) {
+	var ids []uint
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(item, 10, 32)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("ids must be school IDs, got %q", item)
+		}
+		ids = append(ids, uint(id))
+	}
+	if len(ids) < minCompareSchools || len(ids) > maxCompareSchools {
+		return nil, fmt.Errorf("ids must list %d to %d schools", minCompareSchools, maxCompareSchools)
+	}
+	return ids, nil
+}
+
+// schoolCount is a count per school, read from grouped queries
+type schoolCount struct {
+	SchoolID uint
+	Count    int64
+}
+
+// countBySchool runs a grouped count query and returns the counts by school ID
+func countBySchool(query *gorm.DB) (map[uint]int64, error) {
+	var rows []schoolCount
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.SchoolID] = row.Count
+	}
+	return counts, nil
+}
+
+// compareSchools loads the comparison of the schools in the order of ids. IDs of merged schools are resolved to
+// the surviving school. origin may be nil.
+func compareSchools(db *gorm.DB, ids []uint, origin *geo.Point) ([]SchoolComparison, error) {
+	resolved := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id = resolveSchoolID(db, id); !slices.Contains(resolved, id) {
+			resolved = append(resolved, id)
+		}
+	}
+	if len(resolved) < minCompareSchools {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("ids must list %d to %d different schools", minCompareSchools, maxCompareSchools))
+	}
+
+	var schools []models.School
+	if err := db.Preload("Media", "kind IN ?", []models.MediaKind{models.MediaLogo, models.MediaCover}).
+		Where("id IN ?", resolved).Find(&schools).Error; err != nil {
+		return nil, err
+	}
+	if len(schools) != len(resolved) {
+		for _, id := range resolved {
+			if !slices.ContainsFunc(schools, func(school models.School) bool { return school.ID == id }) {
+				return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("School %d not found", id))
+			}
+		}
+	}
+
+	var ratings []struct {
+		SchoolID uint
+		Rating   int
+		Count    int64
+	}
+	if err := db.Model(&models.Review{}).
+		Select("school_id, rating, COUNT(*) AS count").
+		Where("school_id IN ? AND status = ?", resolved, models.ReviewApproved).
+		Group("school_id, rating").Scan(&ratings).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	upcomingEvents, err := countBySchool(db.Model(&models.Event{}).
+		Select("institution_profiles.school_id AS school_id, COUNT(*) AS count").
+		Joins("JOIN institution_profiles ON institution_profiles.id = events.institution_id AND institution_profiles.deleted_at IS NULL").
+		Where("institution_profiles.school_id IN ? AND events.is_published = ? AND events.start_date > ?", resolved, true, now).
+		Group("institution_profiles.school_id"))
+	if err != nil {
+		return nil, err
+	}
+	activeJobs, err := countBySchool(db.Model(&models.Job{}).
+		Select("institution_profiles.school_id AS school_id, COUNT(*) AS count").
+		Joins("JOIN institution_profiles ON institution_profiles.id = jobs.institution_profile_id AND institution_profiles.deleted_at IS NULL").
+		Where("institution_profiles.school_id IN ? AND jobs.is_active = ?", resolved, true).
+		Where("jobs.expires_at IS NULL OR jobs.expires_at > ?", now).
+		Group("institution_profiles.school_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	comparisons := make([]SchoolComparison, 0, len(resolved))
+	for _, id := range resolved {
+		index := slices.IndexFunc(schools, func(school models.School) bool { return school.ID == id })
+		comparison := SchoolComparison{
+			School:         schools[index],
+			Rating:         SchoolRatingSummary{Distribution: map[string]int64{"1": 0, "2": 0, "3": 0, "4": 0, "5": 0}},
+			UpcomingEvents: upcomingEvents[id],
+			ActiveJobs:     activeJobs[id],
+		}
+
+		var total int64
+		for _, rating := range ratings {
+			if rating.SchoolID != id || rating.Rating < 1 || rating.Rating > 5 {
+				continue
+			}
+			comparison.Rating.Distribution[strconv.Itoa(rating.Rating)] += rating.Count
+			comparison.Rating.Count += rating.Count
+			total += int64(rating.Rating) * rating.Count
+		}
+		if comparison.Rating.Count > 0 {
+			average := math.Round(float64(total)/float64(comparison.Rating.Count)*100) / 100
+			comparison.Rating.Average = &average
+		}
+
+		school := comparison.School
+		if origin != nil && school.Latitude != nil && school.Longitude != nil {
+			distance := math.Round(geo.Distance(*origin, geo.Point{Lat: *school.Latitude, Lng: *school.Longitude})*100) / 100
+			comparison.DistanceKm = &distance
+		}
+		comparisons = append(comparisons, comparison)
+	}
+	return comparisons, nil
+}
+
+// CompareSchools allows anyone to compare schools side by side.
+// @Summary Compare schools
+// @Description Compares 2 to 5 schools, e.g. the schools a parent saved. Each school comes with its profile and Montessori attributes, the average and distribution of its approved review ratings, the number of upcoming events and active job postings of its institution, and its distance from lat/lng when given. Schools are returned in the order of ids; IDs of merged schools are replaced by the surviving school.
+// @Tags schools
+// @Produce json
+// @Param ids query string true "Comma-separated IDs of 2 to 5 schools"
+// @Param lat query number false "Latitude to measure distances from"
+// @Param lng query number false "Longitude to measure distances from"
+// @Success 200 {object} map[string][]SchoolComparison "Compared schools"
+// @Failure 400 {object} map[string]string "Invalid school IDs or location"
+// @Failure 404 {object} map[string]string "School not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/schools/compare [get]
+func CompareSchools(db *gorm.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ids, err := parseCompareSchoolIDs(c.Query("ids"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		var origin *geo.Point
+		if c.Query("lat") != "" || c.Query("lng") != "" {
+			lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+			lng, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+			if latErr != nil || lngErr != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "lat and lng must both be numbers"})
+			}
+			if err := validateCoordinates(&lat, &lng); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+			origin = &geo.Point{Lat: lat, Lng: lng}
+		}
+
+		comparisons, err := compareSchools(db, ids, origin)
+		if err != nil {
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				return c.Status(fiberErr.Code).JSON(fiber.Map{"error": fiberErr.Message})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error while comparing schools: " + err.Error()})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": comparisons})
+	}
+}
@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mwc_backend/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errSchoolMergeSame         = errors.New("a school cannot be merged into itself")
+	errSchoolMergeInstitutions = errors.New("both schools are mapped to an institution, unlink one of them before merging")
+)
+
+// SchoolDuplicateHandler handles the admin review of possibly duplicate schools
+type SchoolDuplicateHandler struct {
+	db *gorm.DB
+}
+
+// NewSchoolDuplicateHandler creates a new SchoolDuplicateHandler.
+func NewSchoolDuplicateHandler(db *gorm.DB) *SchoolDuplicateHandler {
+	return &SchoolDuplicateHandler{db: db}
+}
+
+// MergeSchoolDuplicateRequest chooses which school of a pair is kept
+type MergeSchoolDuplicateRequest struct {
+	KeepSchoolID uint `json:"keep_school_id"` // Defaults to the older school of the pair
+}
+
+// MergeSchoolsRequest names the school merged into the school in the path
+type MergeSchoolsRequest struct {
+	MergeSchoolID uint `json:"merge_school_id"`
+}
+
+// mergeSchools moves everything that refers to the merged school onto the surviving school: reviews, saved-school
+// links of educators and parents, and the institution mapping with its claims. Events belong to institutions, so they
+// move with the institution mapping. Fields missing on the surviving school are copied from the merged one. The merged school is
+// deleted and its ID redirects to the surviving school.
+func mergeSchools(db *gorm.DB, survivorID, mergedID, adminUserID uint) (*models.School, error) {
+	if survivorID == mergedID {
+		return nil, errSchoolMergeSame
+	}
+	var survivor models.School
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var schools []models.School
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", []uint{survivorID, mergedID}).Find(&schools).Error; err != nil {
+			return err
+		}
+		if len(schools) != 2 {
+			return gorm.ErrRecordNotFound
+		}
+		merged := schools[0]
+		survivor = schools[1]
+		if merged.ID == survivorID {
+			merged, survivor = survivor, merged
+		}
+
+		var mappedInstitutions int64
+		if err := tx.Model(&models.InstitutionProfile{}).Where("school_id IN ?", []uint{survivorID, mergedID}).Count(&mappedInstitutions).Error; err != nil {
+			return err
+		}
+		if mappedInstitutions > 1 {
+			return errSchoolMergeInstitutions
+		}
+		if err := tx.Model(&models.InstitutionProfile{}).Where("school_id = ?", mergedID).Update("school_id", survivorID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SchoolClaim{}).Where("school_id = ?", mergedID).Update("school_id", survivorID).Error; err != nil {
+			return err
+		}
+		if err := refreshSchoolOwnership(tx, survivorID); err != nil {
+			return err
+		}
+
+		// The gallery is appended to the survivor's; the logo and cover move only where the survivor has none
+		if err := tx.Exec(`UPDATE media SET school_id = ?, position = position + (SELECT COALESCE(MAX(position), -1) + 1 FROM media WHERE school_id = ? AND kind = ?)
+			WHERE school_id = ? AND kind = ?`, survivorID, survivorID, models.MediaGallery, mergedID, models.MediaGallery).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Media{}).Where("school_id = ? AND kind IN ?", mergedID, []models.MediaKind{models.MediaLogo, models.MediaCover}).
+			Where("kind NOT IN (?)", tx.Model(&models.Media{}).Select("kind").Where("school_id = ?", survivorID)).
+			Update("school_id", survivorID).Error; err != nil {
+			return err
+		}
+
+		// A user may review a school only once, so reviews of users who reviewed both schools are dropped
+		if err := tx.Where("school_id = ? AND reviewer_id IN (?)", mergedID,
+			tx.Model(&models.Review{}).Select("reviewer_id").Where("school_id = ?", survivorID)).
+			Delete(&models.Review{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Review{}).Where("school_id = ?", mergedID).Update("school_id", survivorID).Error; err != nil {
+			return err
+		}
+
+		for _, savedSchools := range []struct{ table, profileColumn string }{
+			{"educator_saved_schools", "educator_profile_id"},
+			{"parent_saved_schools", "parent_profile_id"},
+		} {
+			if err := tx.Exec(fmt.Sprintf("INSERT INTO %s (%s, school_id) SELECT %s, ? FROM %s WHERE school_id = ? ON CONFLICT DO NOTHING",
+				savedSchools.table, savedSchools.profileColumn, savedSchools.profileColumn, savedSchools.table), survivorID, mergedID).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE school_id = ?", savedSchools.table), mergedID).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, field := range []struct {
+			survivor *string
+			merged   string
+		}{
+			{&survivor.Address, merged.Address},
+			{&survivor.City, merged.City},
+			{&survivor.State, merged.State},
+			{&survivor.ZipCode, merged.ZipCode},
+			{&survivor.ContactEmail, merged.ContactEmail},
+			{&survivor.ContactPhone, merged.ContactPhone},
+			{&survivor.Website, merged.Website},
+		} {
+			if *field.survivor == "" {
+				*field.survivor = field.merged
+			}
+		}
+		if survivor.Latitude == nil && merged.Latitude != nil {
+			survivor.Latitude, survivor.Longitude, survivor.GeocodedAt = merged.Latitude, merged.Longitude, merged.GeocodedAt
+		}
+		survivor.DuplicatesCheckedAt = nil // Compare the combined record again
+		if err := tx.Model(&survivor).Select("address", "city", "state", "zip_code", "contact_email", "contact_phone", "website",
+			"latitude", "longitude", "geocoded_at", "duplicates_checked_at").Updates(&survivor).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.SchoolRedirect{}).Where("to_school_id = ?", mergedID).Update("to_school_id", survivorID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.SchoolRedirect{FromSchoolID: mergedID, ToSchoolID: survivorID, MergedBy: adminUserID}).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.SchoolDuplicate{}).
+			Where("school_id = ? AND duplicate_id = ?", min(survivorID, mergedID), max(survivorID, mergedID)).
+			Updates(map[string]interface{}{"status": models.SchoolDuplicateMerged, "reviewed_by": adminUserID, "reviewed_at": now}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("status = ? AND (school_id = ? OR duplicate_id = ?)", models.SchoolDuplicatePending, mergedID, mergedID).
+			Delete(&models.SchoolDuplicate{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&merged).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &survivor, nil
+}
+
+// mergeErrorResponse maps a merge error to a response
+func mergeErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errSchoolMergeSame):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, errSchoolMergeInstitutions):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "School not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to merge schools: " + err.Error()})
+}
+
+// GetSchoolDuplicates lists possible duplicate schools for review (admin only)
+// @Summary List possible duplicate schools
+// @Description Retrieves pairs of schools that may be the same school, most similar first. Pairs are found in the background by comparing the normalized name, address and website of new and changed schools within a country.
+// @Tags admin,schools
+// @Produce json
+// @Param status query string false "Filter by status (pending, merged, dismissed, all)" default(pending)
+// @Param country_code query string false "Filter by country code"
+// @Param min_score query number false "Only pairs scoring at least this, from 0 to 1"
+// @Param page query int false "Page number for pagination" default(1)
+// @Param limit query int false "Number of items per page" default(20)
+// @Success 200 {object} map[string]interface{} "List of school pairs with pagination metadata"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/school-duplicates [get]
+func (h *SchoolDuplicateHandler) GetSchoolDuplicates(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	status := c.Query("status", string(models.SchoolDuplicatePending))
+	query := h.db.Model(&models.SchoolDuplicate{})
+	if status != "all" {
+		query = query.Where("school_duplicates.status = ?", status)
+	}
+	query = query.
+		Joins("JOIN schools AS first_school ON first_school.id = school_duplicates.school_id").
+		Joins("JOIN schools AS second_school ON second_school.id = school_duplicates.duplicate_id")
+	// A pending pair only needs review while both schools exist
+	if status == string(models.SchoolDuplicatePending) {
+		query = query.Where("first_school.deleted_at IS NULL AND second_school.deleted_at IS NULL")
+	}
+	if countryCode := c.Query("country_code"); countryCode != "" {
+		query = query.Where("LOWER(first_school.country_code) = LOWER(?)", countryCode)
+	}
+	if minScore, err := strconv.ParseFloat(c.Query("min_score"), 64); err == nil {
+		query = query.Where("school_duplicates.score >= ?", minScore)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var pairs []models.SchoolDuplicate
+	if err := query.Preload("School").Preload("Duplicate").
+		Order("school_duplicates.score DESC, school_duplicates.id").Offset((page - 1) * limit).Limit(limit).Find(&pairs).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve duplicate schools: " + err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": pairs,
+		"meta": fiber.Map{
+			"total":     total,
+			"page":      page,
+			"limit":     limit,
+			"last_page": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// findPendingPair loads the pending pair named by the pair_id route parameter
+func (h *SchoolDuplicateHandler) findPendingPair(c *fiber.Ctx) (*models.SchoolDuplicate, *fiber.Error) {
+	pairID, err := strconv.ParseUint(c.Params("pair_id"), 10, 32)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid pair ID")
+	}
+	var pair models.SchoolDuplicate
+	if err := h.db.First(&pair, uint(pairID)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Duplicate pair not found")
+		}
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Database error: "+err.Error())
+	}
+	if pair.Status != models.SchoolDuplicatePending {
+		return nil, fiber.NewError(fiber.StatusConflict, fmt.Sprintf("Duplicate pair has already been %s", pair.Status))
+	}
+	return &pair, nil
+}
+
+// DismissSchoolDuplicate marks a pair as different schools (admin only)
+// @Summary Dismiss a possible duplicate
+// @Description Marks a pair of schools as different schools. The pair is not suggested again.
+// @Tags admin,schools
+// @Produce json
+// @Param pair_id path int true "Duplicate pair ID"
+// @Success 200 {object} models.SchoolDuplicate "Pair dismissed"
+// @Failure 400 {object} map[string]string "Invalid pair ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Pair not found"
+// @Failure 409 {object} map[string]string "Pair already reviewed"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/school-duplicates/{pair_id}/dismiss [post]
+func (h *SchoolDuplicateHandler) DismissSchoolDuplicate(c *fiber.Ctx) error {
+	adminUserID, _ := c.Locals("user_id").(uint)
+	pair, lookupErr := h.findPendingPair(c)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+
+	now := time.Now()
+	pair.Status, pair.ReviewedBy, pair.ReviewedAt = models.SchoolDuplicateDismissed, &adminUserID, &now
+	if err := h.db.Model(pair).Select("status", "reviewed_by", "reviewed_at").Updates(pair).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to dismiss duplicate pair: " + err.Error()})
+	}
+
+	LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_DUPLICATE_DISMISS", pair.ID, "SchoolDuplicate", fmt.Sprintf("Schools %d and %d are different schools", pair.SchoolID, pair.DuplicateID), c)
+	return c.Status(fiber.StatusOK).JSON(pair)
+}
+
+// MergeSchoolDuplicate merges a pair of duplicate schools (admin only)
+// @Summary Merge a duplicate pair
+// @Description Merges the two schools of a pair. Reviews, saved-school links and the institution mapping (with its events) move to the kept school, empty fields of the kept school are filled from the other one, and the other school's ID redirects to the kept school. Fails if both schools are mapped to an institution.
+// @Tags admin,schools
+// @Accept json
+// @Produce json
+// @Param pair_id path int true "Duplicate pair ID"
+// @Param merge body MergeSchoolDuplicateRequest false "School to keep, the older school by default"
+// @Success 200 {object} models.School "Kept school"
+// @Failure 400 {object} map[string]string "Invalid pair ID or school to keep"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Pair or school not found"
+// @Failure 409 {object} map[string]string "Pair already reviewed or both schools mapped to an institution"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/school-duplicates/{pair_id}/merge [post]
+func (h *SchoolDuplicateHandler) MergeSchoolDuplicate(c *fiber.Ctx) error {
+	adminUserID, _ := c.Locals("user_id").(uint)
+	pair, lookupErr := h.findPendingPair(c)
+	if lookupErr != nil {
+		return c.Status(lookupErr.Code).JSON(fiber.Map{"error": lookupErr.Message})
+	}
+
+	var req MergeSchoolDuplicateRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+		}
+	}
+	survivorID, mergedID := pair.SchoolID, pair.DuplicateID
+	switch req.KeepSchoolID {
+	case 0, pair.SchoolID:
+	case pair.DuplicateID:
+		survivorID, mergedID = pair.DuplicateID, pair.SchoolID
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "keep_school_id must be one of the schools of the pair"})
+	}
+
+	survivor, err := mergeSchools(h.db, survivorID, mergedID, adminUserID)
+	if err != nil {
+		LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_MERGE_FAIL", mergedID, "School", err.Error(), c)
+		return mergeErrorResponse(c, err)
+	}
+	LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_MERGE", mergedID, "School", fmt.Sprintf("School %d merged into school %d", mergedID, survivorID), c)
+	return c.Status(fiber.StatusOK).JSON(survivor)
+}
+
+// MergeSchools merges another school into a school (admin only)
+// @Summary Merge schools
+// @Description Merges the school merge_school_id into the school in the path, whether or not the pair was detected as a duplicate. Reviews, saved-school links and the institution mapping (with its events) move to the kept school, empty fields are filled from the merged school, and the merged school's ID redirects to the kept school. Fails if both schools are mapped to an institution.
+// @Tags admin,schools
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the school to keep"
+// @Param merge body MergeSchoolsRequest true "School to merge into it"
+// @Success 200 {object} models.School "Kept school"
+// @Failure 400 {object} map[string]string "Invalid school ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "School not found"
+// @Failure 409 {object} map[string]string "Both schools mapped to an institution"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/schools/{id}/merge [post]
+func (h *SchoolDuplicateHandler) MergeSchools(c *fiber.Ctx) error {
+	adminUserID, _ := c.Locals("user_id").(uint)
+	survivorID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid school ID format"})
+	}
+	var req MergeSchoolsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON: " + err.Error()})
+	}
+	if req.MergeSchoolID == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "merge_school_id is required"})
+	}
+
+	survivor, err := mergeSchools(h.db, uint(survivorID), req.MergeSchoolID, adminUserID)
+	if err != nil {
+		LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_MERGE_FAIL", req.MergeSchoolID, "School", err.Error(), c)
+		return mergeErrorResponse(c, err)
+	}
+	LogUserAction(h.db, adminUserID, "ADMIN_SCHOOL_MERGE", req.MergeSchoolID, "School", fmt.Sprintf("School %d merged into school %d", req.MergeSchoolID, survivorID), c)
+	return c.Status(fiber.StatusOK).JSON(survivor)
+}
@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"mwc_backend/internal/geo"
+	"mwc_backend/internal/models"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	schoolDuplicateThreshold   = 0.75 // Pairs scoring at least this are queued for review
+	schoolDuplicateCandidates  = 20   // Schools with the most similar names compared to each school
+	schoolDuplicateBatchSize   = 500  // Schools checked per scheduled run
+	schoolDuplicateMaxDistance = 10.0 // Kilometres between geocoded schools beyond which they are different schools
+)
+
+// schoolNameStopWords are ignored when comparing school names
+var schoolNameStopWords = map[string]bool{"the": true, "a": true, "an": true, "of": true, "and": true}
+
+// schoolAddressAbbreviations maps street words to the abbreviation they are compared as
+var schoolAddressAbbreviations = map[string]string{
+	"street": "st", "avenue": "ave", "road": "rd", "boulevard": "blvd", "drive": "dr", "lane": "ln",
+	"place": "pl", "court": "ct", "highway": "hwy", "suite": "ste", "north": "n", "south": "s", "east": "e", "west": "w",
+	"strasse": "str", "straße": "str",
+}
+
+// sharedWebsiteHosts host pages of many schools, so the first path segment is part of the website identity
+var sharedWebsiteHosts = map[string]bool{
+	"facebook.com": true, "instagram.com": true, "sites.google.com": true, "linktr.ee": true,
+}
+
+// normalizeWords lowercases text, drops apostrophes, splits it into words of letters and digits and applies
+// replace to each word. Words replaced with "" are dropped.
+func normalizeWords(text string, replace func(word string) string) string {
+	text = strings.NewReplacer("'", "", "’", "").Replace(strings.ToLower(text))
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, word := range words {
+		if replace != nil {
+			word = replace(word)
+		}
+		if word != "" {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+func normalizeSchoolName(name string) string {
+	return normalizeWords(name, func(word string) string {
+		if schoolNameStopWords[word] {
+			return ""
+		}
+		return word
+	})
+}
+
+func normalizeSchoolAddress(address string) string {
+	return normalizeWords(address, func(word string) string {
+		if abbreviation, ok := schoolAddressAbbreviations[word]; ok {
+			return abbreviation
+		}
+		return word
+	})
+}
+
+// normalizeWebsite reduces a website to its host without "www.", plus the first path segment on shared hosts.
+func normalizeWebsite(website string) string {
+	website = strings.TrimSpace(strings.ToLower(website))
+	if website == "" {
+		return ""
+	}
+	if !strings.Contains(website, "://") {
+		website = "http://" + website
+	}
+	parsed, err := url.Parse(website)
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(parsed.Hostname(), "www.")
+	if sharedWebsiteHosts[host] {
+		segment, _, _ := strings.Cut(strings.Trim(parsed.Path, "/"), "/")
+		return host + "/" + segment
+	}
+	return host
+}
+
+// trigrams returns the set of trigrams of a normalized text the way pg_trgm builds them: every word is padded
+// with two spaces in front and one behind.
+func trigrams(text string) map[string]bool {
+	result := make(map[string]bool)
+	for _, word := range strings.Fields(text) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			result[string(runes[i:i+3])] = true
+		}
+	}
+	return result
+}
+
+// trigramSimilarity is the share of trigrams two texts have in common, from 0 to 1, like pg_trgm's similarity().
+func trigramSimilarity(a, b string) float64 {
+	aTrigrams, bTrigrams := trigrams(a), trigrams(b)
+	if len(aTrigrams) == 0 || len(bTrigrams) == 0 {
+		return 0
+	}
+	common := 0
+	for trigram := range aTrigrams {
+		if bTrigrams[trigram] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(aTrigrams)+len(bTrigrams)-common)
+}
+
+// scoreSchoolPair compares two schools and returns the pair if they are likely the same school.
+// The score weighs name similarity 0.6 and address similarity 0.4; without addresses on both sides it is
+// based on the name alone and slightly discounted. A shared website raises the score to at least 0.6, while
+// different cities or locations far apart rule a pair out unless the website is shared.
+func scoreSchoolPair(a, b *models.School) (models.SchoolDuplicate, bool) {
+	pair := models.SchoolDuplicate{
+		SchoolID:       min(a.ID, b.ID),
+		DuplicateID:    max(a.ID, b.ID),
+		NameSimilarity: trigramSimilarity(normalizeSchoolName(a.Name), normalizeSchoolName(b.Name)),
+		Status:         models.SchoolDuplicatePending,
+	}
+	aWebsite := normalizeWebsite(a.Website)
+	pair.SameWebsite = aWebsite != "" && aWebsite == normalizeWebsite(b.Website)
+
+	if !pair.SameWebsite {
+		aCity, bCity := normalizeWords(a.City, nil), normalizeWords(b.City, nil)
+		if aCity != "" && bCity != "" && aCity != bCity {
+			return pair, false
+		}
+		if a.Latitude != nil && a.Longitude != nil && b.Latitude != nil && b.Longitude != nil &&
+			geo.Distance(geo.Point{Lat: *a.Latitude, Lng: *a.Longitude}, geo.Point{Lat: *b.Latitude, Lng: *b.Longitude}) > schoolDuplicateMaxDistance {
+			return pair, false
+		}
+	}
+
+	aAddress, bAddress := normalizeSchoolAddress(a.Address), normalizeSchoolAddress(b.Address)
+	if aAddress != "" && bAddress != "" {
+		addressSimilarity := trigramSimilarity(aAddress, bAddress)
+		pair.AddressSimilarity = &addressSimilarity
+		pair.Score = 0.6*pair.NameSimilarity + 0.4*addressSimilarity
+	} else {
+		pair.Score = 0.9 * pair.NameSimilarity
+	}
+	if pair.SameWebsite {
+		pair.Score = max(pair.Score, 0.6+0.4*pair.NameSimilarity)
+	}
+	return pair, pair.Score >= schoolDuplicateThreshold
+}
+
+// detectSchoolDuplicates compares a school with the schools of its country that have similar names or the same
+// website, and with the schools it already has pending pairs with, and queues the likely duplicates for review.
+// Pending pairs that no longer match are removed; merged and dismissed pairs are kept as they are.
+func detectSchoolDuplicates(db *gorm.DB, school *models.School) (int, error) {
+	var candidates []models.School
+	if err := db.Where("id <> ? AND LOWER(country_code) = LOWER(?)", school.ID, school.CountryCode).
+		Where("schools.name % ?", school.Name).
+		Order(clause.Expr{SQL: "similarity(schools.name, ?) DESC", Vars: []interface{}{school.Name}}).
+		Limit(schoolDuplicateCandidates).Find(&candidates).Error; err != nil {
+		return 0, err
+	}
+	// Schools with the same website are compared whatever their names. LIKE only narrows them down; the
+	// normalized websites must be equal.
+	if website := normalizeWebsite(school.Website); website != "" {
+		var sameWebsite []models.School
+		if err := db.Where("id <> ? AND LOWER(country_code) = LOWER(?)", school.ID, school.CountryCode).
+			Where("LOWER(schools.website) LIKE ?", "%"+escapeLike(website)+"%").
+			Find(&sameWebsite).Error; err != nil {
+			return 0, err
+		}
+		for _, other := range sameWebsite {
+			if normalizeWebsite(other.Website) == website && !containsSchool(candidates, other.ID) {
+				candidates = append(candidates, other)
+			}
+		}
+	}
+
+	var pending []models.SchoolDuplicate
+	if err := db.Where("status = ? AND (school_id = ? OR duplicate_id = ?)", models.SchoolDuplicatePending, school.ID, school.ID).
+		Find(&pending).Error; err != nil {
+		return 0, err
+	}
+	var pairedIDs []uint
+	for _, pair := range pending {
+		pairedIDs = append(pairedIDs, pair.SchoolID+pair.DuplicateID-school.ID)
+	}
+	if len(pairedIDs) > 0 {
+		var paired []models.School
+		if err := db.Where("id IN ?", pairedIDs).Find(&paired).Error; err != nil {
+			return 0, err
+		}
+		for _, other := range paired {
+			if !containsSchool(candidates, other.ID) {
+				candidates = append(candidates, other)
+			}
+		}
+	}
+
+	matched := make(map[uint]bool)
+	for i := range candidates {
+		pair, ok := scoreSchoolPair(school, &candidates[i])
+		if !ok {
+			continue
+		}
+		if err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "school_id"}, {Name: "duplicate_id"}},
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Table: "school_duplicates", Name: "status"}, Value: models.SchoolDuplicatePending}}},
+			DoUpdates: clause.AssignmentColumns([]string{"updated_at", "score", "name_similarity", "address_similarity", "same_website"}),
+		}).Create(&pair).Error; err != nil {
+			return len(matched), err
+		}
+		matched[candidates[i].ID] = true
+	}
+
+	for _, pair := range pending {
+		if !matched[pair.SchoolID+pair.DuplicateID-school.ID] {
+			if err := db.Unscoped().Delete(&pair).Error; err != nil {
+				return len(matched), err
+			}
+		}
+	}
+	return len(matched), nil
+}
+
+func containsSchool(schools []models.School, schoolID uint) bool {
+	for _, school := range schools {
+		if school.ID == schoolID {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectPendingSchoolDuplicates checks schools that are new or changed since they were last checked for duplicates.
+// It runs as a scheduled task.
+func DetectPendingSchoolDuplicates(db *gorm.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var schools []models.School
+		if err := db.Where("duplicates_checked_at IS NULL").Order("id").Limit(schoolDuplicateBatchSize).Find(&schools).Error; err != nil {
+			return err
+		}
+
+		pairs := 0
+		for i := range schools {
+			if ctx.Err() != nil {
+				break
+			}
+			found, err := detectSchoolDuplicates(db, &schools[i])
+			if err != nil {
+				log.Printf("Error detecting duplicates of school %d: %v", schools[i].ID, err)
+				continue
+			}
+			pairs += found
+			if err := db.Model(&schools[i]).UpdateColumn("duplicates_checked_at", time.Now()).Error; err != nil {
+				log.Printf("Error marking school %d as checked for duplicates: %v", schools[i].ID, err)
+			}
+		}
+		if len(schools) > 0 {
+			log.Printf("[Deduplication] Checked %d school(s), %d possible duplicate pair(s) found", len(schools), pairs)
+		}
+		return nil
+	}
+}
+
+// resolveSchoolID follows the redirect of a merged school to the school it was merged into
+func resolveSchoolID(db *gorm.DB, schoolID uint) uint {
+	var redirect models.SchoolRedirect
+	if err := db.Where("from_school_id = ?", schoolID).Take(&redirect).Error; err != nil {
+		return schoolID
+	}
+	return redirect.ToSchoolID
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"mwc_backend/internal/models"
+	"testing"
+	"time"
+)
+
+func TestNormalizeWebsite(t *testing.T) {
+	cases := map[string]string{
+		"":                   "",
+		"   ":                "",
+		"montessori.org":     "montessori.org",
+		"  Montessori.ORG  ": "montessori.org",
+		"www.montessori.org": "montessori.org",
+		"https://www.montessori.org/about?lang=it": "montessori.org",
+		"http://montessori.org:8080/":              "montessori.org",
+		"https://info@montessori.org":              "montessori.org",
+		"https://school.montessori.org":            "school.montessori.org",
+		"https://www.facebook.com/CasaBambini/":    "facebook.com/casabambini",
+		"facebook.com/casabambini/photos":          "facebook.com/casabambini",
+		"https://instagram.com/casa":               "instagram.com/casa",
+		"not a website":                            "",
+		"http://":                                  "",
+	}
+	for website, want := range cases {
+		if got := normalizeWebsite(website); got != want {
+			t.Errorf("normalizeWebsite(%q) = %q, want %q", website, got, want)
+		}
+	}
+}
+
+func TestTrigramSimilarity(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 0},
+		{"montessori", "", 0},
+		{"montessori", "montessori", 1},
+		{"casa bambini", "bambini casa", 1}, // Word order does not matter
+		{"montessori", "montesori", 9.0 / 12},
+		{"ab", "ba", 0},
+		{"a", "a b", 2.0 / 4},
+	}
+	for _, tc := range cases {
+		got := trigramSimilarity(tc.a, tc.b)
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("trigramSimilarity(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+		if reversed := trigramSimilarity(tc.b, tc.a); math.Abs(got-reversed) > 1e-9 {
+			t.Errorf("trigramSimilarity(%q, %q) = %v but reversed is %v", tc.a, tc.b, got, reversed)
+		}
+	}
+}
+
+func TestScoreSchoolPair(t *testing.T) {
+	at := func(lat, lng float64) (*float64, *float64) { return &lat, &lng }
+	romeLat, romeLng := at(41.9, 12.5)
+	nearLat, nearLng := at(41.92, 12.52)
+	farLat, farLng := at(42.4, 12.5)
+
+	cases := []struct {
+		name        string
+		a, b        models.School
+		want        bool
+		sameWebsite bool
+		score       float64 // Checked when not negative
+		withAddress bool
+	}{
+		{"same name and address",
+			models.School{Name: "Casa dei Bambini", Address: "12 Main Street", City: "Rome"},
+			models.School{Name: "casa dei bambini", Address: "12 main st.", City: "ROME"},
+			true, false, 1, true},
+		{"stop words and punctuation are ignored",
+			models.School{Name: "The Montessori School", City: "Rome"},
+			models.School{Name: "Montessori School!", City: "Rome"},
+			true, false, 0.9, false},
+		{"same name without addresses is discounted",
+			models.School{Name: "Casa dei Bambini"},
+			models.School{Name: "Casa dei Bambini", Address: "12 Main Street"},
+			true, false, 0.9, false},
+		{"same name at different addresses",
+			models.School{Name: "Casa dei Bambini", Address: "12 Main Street", City: "Rome"},
+			models.School{Name: "Casa dei Bambini", Address: "99 Oak Avenue", City: "Rome"},
+			false, false, -1, true},
+		{"different cities",
+			models.School{Name: "Casa dei Bambini", City: "Rome"},
+			models.School{Name: "Casa dei Bambini", City: "Milan"},
+			false, false, -1, false},
+		{"nearby locations",
+			models.School{Name: "Casa dei Bambini", Latitude: romeLat, Longitude: romeLng},
+			models.School{Name: "Casa dei Bambini", Latitude: nearLat, Longitude: nearLng},
+			true, false, 0.9, false},
+		{"locations far apart",
+			models.School{Name: "Casa dei Bambini", Latitude: romeLat, Longitude: romeLng},
+			models.School{Name: "Casa dei Bambini", Latitude: farLat, Longitude: farLng},
+			false, false, -1, false},
+		{"a shared website overrides different cities",
+			models.School{Name: "Casa dei Bambini", City: "Rome", Website: "https://www.casabambini.it"},
+			models.School{Name: "Casa dei Bambini", City: "Milan", Website: "casabambini.it/contact"},
+			true, true, 1, false},
+		{"a shared website alone is not enough",
+			models.School{Name: "Casa dei Bambini", Website: "casabambini.it"},
+			models.School{Name: "Little Oak Academy", Website: "casabambini.it"},
+			false, true, -1, false},
+		{"pages on a shared host are different websites",
+			models.School{Name: "Casa dei Bambini", City: "Rome", Website: "facebook.com/casarome"},
+			models.School{Name: "Casa dei Bambini", City: "Milan", Website: "facebook.com/casamilan"},
+			false, false, -1, false},
+	}
+	for _, tc := range cases {
+		tc.a.ID, tc.b.ID = 7, 3
+		pair, ok := scoreSchoolPair(&tc.a, &tc.b)
+		if ok != tc.want {
+			t.Errorf("%s: duplicate = %v (score %v), want %v", tc.name, ok, pair.Score, tc.want)
+		}
+		if pair.SameWebsite != tc.sameWebsite {
+			t.Errorf("%s: same website = %v, want %v", tc.name, pair.SameWebsite, tc.sameWebsite)
+		}
+		if tc.score >= 0 && math.Abs(pair.Score-tc.score) > 1e-9 {
+			t.Errorf("%s: score = %v, want %v", tc.name, pair.Score, tc.score)
+		}
+		if pair.Score < 0 || pair.Score > 1 || pair.NameSimilarity < 0 || pair.NameSimilarity > 1 {
+			t.Errorf("%s: score %v or name similarity %v out of range", tc.name, pair.Score, pair.NameSimilarity)
+		}
+		if (pair.AddressSimilarity != nil) != tc.withAddress {
+			t.Errorf("%s: address similarity = %v, want set %v", tc.name, pair.AddressSimilarity, tc.withAddress)
+		}
+		// The pair is keyed by the lower ID first, so both directions give the same pair
+		reversed, reversedOK := scoreSchoolPair(&tc.b, &tc.a)
+		if pair.SchoolID != 3 || pair.DuplicateID != 7 || reversed.SchoolID != 3 || reversedOK != ok || math.Abs(reversed.Score-pair.Score) > 1e-9 {
+			t.Errorf("%s: pair %d-%d scored %v, reversed %d-%d scored %v", tc.name, pair.SchoolID, pair.DuplicateID, pair.Score, reversed.SchoolID, reversed.DuplicateID, reversed.Score)
+		}
+	}
+}
+
+func TestDetectSchoolDuplicatesByWebsite(t *testing.T) {
+	db := openTestDB(t)
+	// A made-up country keeps the schools apart from any already in the database
+	country := fmt.Sprintf("T%d", time.Now().UnixNano()%1000)
+	schools := []models.School{
+		{Name: "Casa dei Bambini", City: "Rome", CountryCode: country, Website: "https://www.montessori-casa.org"},
+		{Name: "Casa dei Bambini", City: "Milan", CountryCode: country, Website: "montessori-casa.org/contact"},
+		{Name: "Casa dei Bambini", City: "Turin", CountryCode: country, Website: "https://notmontessori-casa.org"},
+		{Name: "Casa dei Bambini", City: "Naples", CountryCode: country, Website: "montessori-casa.org.uk"},
+		{Name: "Casa dei Bambini", City: "Bari", CountryCode: country, Website: "montessori_casa.org"},
+	}
+	for i := range schools {
+		if err := db.Create(&schools[i]).Error; err != nil {
+			t.Fatalf("creating school in %s: %v", schools[i].City, err)
+		}
+	}
+
+	found, err := detectSchoolDuplicates(db, &schools[0])
+	if err != nil {
+		t.Fatalf("detectSchoolDuplicates: %v", err)
+	}
+	var pairs []models.SchoolDuplicate
+	if err := db.Where("school_id = ? OR duplicate_id = ?", schools[0].ID, schools[0].ID).Find(&pairs).Error; err != nil {
+		t.Fatal(err)
+	}
+	if found != 1 || len(pairs) != 1 || pairs[0].SchoolID+pairs[0].DuplicateID-schools[0].ID != schools[1].ID || !pairs[0].SameWebsite {
+		t.Errorf("found %d pair(s) %+v, want only the school in Milan with the same website", found, pairs)
+	}
+}
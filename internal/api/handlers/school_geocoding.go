@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"mwc_backend/internal/geo"
+	"mwc_backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	schoolGeocodeTimeout   = 10 * time.Second // Geocoding a single school during a request
+	schoolGeocodeBatchSize = 100              // Schools geocoded per scheduled run
+)
+
+// SchoolGeocoder fills in the coordinates of schools from their address
+type SchoolGeocoder struct {
+	db       *gorm.DB
+	geocoder geo.Geocoder
+}
+
+// NewSchoolGeocoder creates a new SchoolGeocoder. A nil geocoder disables geocoding.
+func NewSchoolGeocoder(db *gorm.DB, geocoder geo.Geocoder) *SchoolGeocoder {
+	if geocoder == nil {
+		geocoder = geo.NoopGeocoder{}
+	}
+	return &SchoolGeocoder{db: db, geocoder: geocoder}
+}
+
+// Enabled reports whether a geocoder is configured
+func (g *SchoolGeocoder) Enabled() bool {
+	if g == nil {
+		return false
+	}
+	_, disabled := g.geocoder.(geo.NoopGeocoder)
+	return !disabled
+}
+
+func schoolAddress(school *models.School) geo.Address {
+	return geo.Address{
+		Street:      school.Address,
+		City:        school.City,
+		State:       school.State,
+		PostalCode:  school.ZipCode,
+		CountryCode: school.CountryCode,
+	}
+}
+
+// Locate sets the school's coordinates from its address. It does not save the school. Coordinates are
+// cleared if the address cannot be found, so a changed address never keeps the old location.
+// Without a geocoder the school is left unchanged.
+func (g *SchoolGeocoder) Locate(ctx context.Context, school *models.School) {
+	if !g.Enabled() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, schoolGeocodeTimeout)
+	defer cancel()
+
+	now := time.Now()
+	school.GeocodedAt = &now
+	point, err := g.geocoder.Geocode(ctx, schoolAddress(school))
+	if err != nil {
+		if !errors.Is(err, geo.ErrNoResult) {
+			log.Printf("Error geocoding school %d (%s): %v", school.ID, schoolAddress(school), err)
+			school.GeocodedAt = nil // Retry on the next scheduled run
+		}
+		school.Latitude, school.Longitude = nil, nil
+		return
+	}
+	school.Latitude, school.Longitude = &point.Lat, &point.Lng
+}
+
+// GeocodePending geocodes schools that have never been geocoded, such as batch uploads. It runs as a scheduled task.
+func (g *SchoolGeocoder) GeocodePending(ctx context.Context) error {
+	if !g.Enabled() {
+		return nil
+	}
+	var schools []models.School
+	if err := g.db.Where("geocoded_at IS NULL AND latitude IS NULL").
+		Order("id").Limit(schoolGeocodeBatchSize).Find(&schools).Error; err != nil {
+		return err
+	}
+
+	located := 0
+	for i := range schools {
+		if ctx.Err() != nil {
+			break
+		}
+		school := &schools[i]
+		g.Locate(ctx, school)
+		if school.GeocodedAt == nil {
+			continue
+		}
+		if err := g.db.Model(school).Select("latitude", "longitude", "geocoded_at").Updates(school).Error; err != nil {
+			log.Printf("Error saving coordinates of school %d: %v", school.ID, err)
+			continue
+		}
+		if school.Latitude != nil {
+			located++
+		}
+	}
+	if len(schools) > 0 {
+		log.Printf("[Geocoding] Geocoded %d of %d pending school(s)", located, len(schools))
+	}
+	return nil
+}
+
+// setSchoolLocation uses the coordinates given in a request if there are any, and otherwise geocodes the
+// address when it is new or changed.
+func (g *SchoolGeocoder) setSchoolLocation(ctx context.Context, school *models.School, latitude, longitude *float64, addressChanged bool) error {
+	if latitude != nil || longitude != nil {
+		if err := validateCoordinates(latitude, longitude); err != nil {
+			return err
+		}
+		now := time.Now()
+		school.Latitude, school.Longitude, school.GeocodedAt = latitude, longitude, &now
+		return nil
+	}
+	if addressChanged {
+		g.Locate(ctx, school)
+	}
+	return nil
+}
+
+func validateCoordinates(latitude, longitude *float64) error {
+	if latitude == nil || longitude == nil {
+		return errors.New("latitude and longitude must be given together")
+	}
+	if !(geo.Point{Lat: *latitude, Lng: *longitude}).Valid() {
+		return errors.New("latitude must be between -90 and 90 and longitude between -180 and 180")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"mwc_backend/internal/geo"
+	"mwc_backend/internal/models"
+	"testing"
+	"time"
+)
+
+func testSchoolGeocoder() *SchoolGeocoder {
+	return NewSchoolGeocoder(nil, geo.NewStaticGeocoder([]geo.StaticEntry{
+		{CountryCode: "IT", State: "Lazio", City: "Rome", Point: geo.Point{Lat: 41.9, Lng: 12.5}},
+		{CountryCode: "IT", PostalCode: "20121", Point: geo.Point{Lat: 45.47, Lng: 9.19}},
+	}))
+}
+
+func TestSchoolGeocoderEnabled(t *testing.T) {
+	var missing *SchoolGeocoder
+	if missing.Enabled() || NewSchoolGeocoder(nil, nil).Enabled() || NewSchoolGeocoder(nil, geo.NoopGeocoder{}).Enabled() {
+		t.Error("a geocoder without a provider reports itself enabled")
+	}
+	if !testSchoolGeocoder().Enabled() {
+		t.Error("a static geocoder reports itself disabled")
+	}
+}
+
+func TestSchoolGeocoderLocate(t *testing.T) {
+	g := testSchoolGeocoder()
+	ctx := context.Background()
+
+	school := models.School{City: "Rome", State: "Lazio", CountryCode: "IT"}
+	g.Locate(ctx, &school)
+	if school.Latitude == nil || *school.Latitude != 41.9 || *school.Longitude != 12.5 || school.GeocodedAt == nil {
+		t.Errorf("Locate(Rome) = %v, %v at %v", school.Latitude, school.Longitude, school.GeocodedAt)
+	}
+
+	school = models.School{City: "Milano", ZipCode: "20121", CountryCode: "IT"}
+	g.Locate(ctx, &school)
+	if school.Latitude == nil || *school.Latitude != 45.47 {
+		t.Errorf("Locate(20121) = %v", school.Latitude)
+	}
+
+	// An address that cannot be found clears the old coordinates but counts as geocoded, so it is not retried
+	lat, lng := 41.9, 12.5
+	school = models.School{City: "Atlantis", CountryCode: "IT", Latitude: &lat, Longitude: &lng}
+	g.Locate(ctx, &school)
+	if school.Latitude != nil || school.Longitude != nil || school.GeocodedAt == nil {
+		t.Errorf("Locate(Atlantis) = %v, %v at %v, want no coordinates and a geocoding time", school.Latitude, school.Longitude, school.GeocodedAt)
+	}
+
+	// Without a geocoder the school is left unchanged
+	school = models.School{City: "Rome", State: "Lazio", CountryCode: "IT", Latitude: &lat, Longitude: &lng}
+	NewSchoolGeocoder(nil, nil).Locate(ctx, &school)
+	if school.Latitude != &lat || school.Longitude != &lng || school.GeocodedAt != nil {
+		t.Errorf("disabled Locate changed the school to %v, %v at %v", school.Latitude, school.Longitude, school.GeocodedAt)
+	}
+}
+
+func TestSetSchoolLocation(t *testing.T) {
+	g := testSchoolGeocoder()
+	ctx := context.Background()
+	float := func(v float64) *float64 { return &v }
+
+	// Coordinates given in the request win over the address
+	school := models.School{City: "Rome", State: "Lazio", CountryCode: "IT"}
+	if err := g.setSchoolLocation(ctx, &school, float(40.85), float(14.27), true); err != nil {
+		t.Fatalf("setSchoolLocation with coordinates: %v", err)
+	}
+	if *school.Latitude != 40.85 || *school.Longitude != 14.27 || school.GeocodedAt == nil {
+		t.Errorf("setSchoolLocation with coordinates = %v, %v at %v", *school.Latitude, *school.Longitude, school.GeocodedAt)
+	}
+
+	for _, coordinates := range [][2]*float64{{float(40.85), nil}, {nil, float(14.27)}, {float(91), float(14.27)}, {float(40.85), float(-181)}} {
+		school := models.School{}
+		if err := g.setSchoolLocation(ctx, &school, coordinates[0], coordinates[1], true); err == nil {
+			t.Errorf("setSchoolLocation(%v, %v) succeeded, want an error", coordinates[0], coordinates[1])
+		}
+		if school.Latitude != nil || school.Longitude != nil {
+			t.Errorf("setSchoolLocation(%v, %v) set coordinates despite the error", coordinates[0], coordinates[1])
+		}
+	}
+
+	// A changed address is geocoded
+	school = models.School{City: "Rome", State: "Lazio", CountryCode: "IT"}
+	if err := g.setSchoolLocation(ctx, &school, nil, nil, true); err != nil {
+		t.Fatalf("setSchoolLocation with a changed address: %v", err)
+	}
+	if school.Latitude == nil || *school.Latitude != 41.9 {
+		t.Errorf("setSchoolLocation with a changed address = %v", school.Latitude)
+	}
+
+	// An unchanged address keeps the stored location
+	geocodedAt := time.Now().Add(-time.Hour)
+	school = models.School{City: "Rome", State: "Lazio", CountryCode: "IT", Latitude: float(41.0), Longitude: float(12.0), GeocodedAt: &geocodedAt}
+	if err := g.setSchoolLocation(ctx, &school, nil, nil, false); err != nil {
+		t.Fatalf("setSchoolLocation with an unchanged address: %v", err)
+	}
+	if *school.Latitude != 41.0 || *school.Longitude != 12.0 || school.GeocodedAt != &geocodedAt {
+		t.Errorf("setSchoolLocation with an unchanged address = %v, %v at %v", *school.Latitude, *school.Longitude, school.GeocodedAt)
+	}
+}
@@ -0,0 +1,421 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mwc_backend/internal/models"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	maxSchoolImportRows     = 200000 // Rows accepted in one import file
+	schoolImportInsertBatch = 100    // Schools inserted per statement
+)
+
+// Supported school import file formats
+const (
+	SchoolImportJSON = "json"
+	SchoolImportCSV  = "csv"
+	SchoolImportXLSX = "xlsx"
+)
+
+// schoolImportAliases maps normalized column headers to SchoolUploadData JSON field names
+var schoolImportAliases = map[string]string{
+	"name": "name", "school": "name", "school_name": "name",
+	"address": "address", "street": "address", "street_address": "address", "address_line_1": "address",
+	"city": "city", "town": "city",
+	"state": "state", "region": "state", "province": "state", "county": "state",
+	"country_code": "country_code", "country": "country_code",
+	"zip_code": "zip_code", "zip": "zip_code", "postal_code": "zip_code", "postcode": "zip_code",
+	"contact_email": "contact_email", "email": "contact_email",
+	"contact_phone": "contact_phone", "phone": "contact_phone", "telephone": "contact_phone",
+	"website": "website", "url": "website", "web": "website",
+	"latitude": "latitude", "lat": "latitude",
+	"longitude": "longitude", "lng": "longitude", "lon": "longitude",
+}
+
+// SchoolImportError is one entry of the import report. Row is the line in the file for CSV and XLSX
+// (the header is row 1) and the position in the array for JSON.
+type SchoolImportError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// schoolImportRow is a parsed row of an import file
+type schoolImportRow struct {
+	Row    int
+	Data   SchoolUploadData
+	Errors []SchoolImportError
+}
+
+// SchoolImportResult summarizes an import
+type SchoolImportResult struct {
+	TotalRows    int                 `json:"total_rows"`
+	CreatedCount int                 `json:"created_count"`
+	FailedCount  int                 `json:"failed_count"`
+	Errors       []SchoolImportError `json:"errors"`
+}
+
+// schoolImportFormat detects the file format from the file name, falling back to the content type.
+func schoolImportFormat(fileName, contentType string) (string, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		return SchoolImportJSON, nil
+	case ".csv":
+		return SchoolImportCSV, nil
+	case ".xlsx":
+		return SchoolImportXLSX, nil
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch strings.TrimSpace(mediaType) {
+	case "application/json":
+		return SchoolImportJSON, nil
+	case "text/csv", "application/csv":
+		return SchoolImportCSV, nil
+	case "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+		return SchoolImportXLSX, nil
+	}
+	return "", errors.New("unsupported file type, upload a .json, .csv or .xlsx file")
+}
+
+// parseColumnMapping reads a column mapping such as {"name": "School Name", "zip_code": "PLZ"} that names the
+// file column of each field. Fields that are not mapped are matched by their header.
+func parseColumnMapping(value string) (map[string]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var mapping map[string]string
+	if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+		return nil, fmt.Errorf("column_mapping must be a JSON object of field to column name: %w", err)
+	}
+	for field := range mapping {
+		if !knownSchoolImportField(field) {
+			return nil, fmt.Errorf("column_mapping: unknown field %q", field)
+		}
+	}
+	return mapping, nil
+}
+
+func knownSchoolImportField(field string) bool {
+	for _, known := range schoolImportAliases {
+		if known == field {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+	return strings.NewReplacer(" ", "_", "-", "_", ".", "_").Replace(header)
+}
+
+// parseSchoolImport reads and validates the rows of an import file.
+func parseSchoolImport(format string, data []byte, mapping map[string]string) ([]schoolImportRow, error) {
+	var rows []schoolImportRow
+	switch format {
+	case SchoolImportJSON:
+		var items []SchoolUploadData
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON file: %w", err)
+		}
+		for i, item := range items {
+			rows = append(rows, schoolImportRow{Row: i + 1, Data: item})
+		}
+	case SchoolImportCSV, SchoolImportXLSX:
+		var records [][]string
+		var err error
+		if format == SchoolImportCSV {
+			records, err = readCSVRecords(data)
+		} else {
+			records, err = readXLSXRecords(data)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if rows, err = tabularSchoolRows(records, mapping); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("no school data found in the file")
+	}
+	if len(rows) > maxSchoolImportRows {
+		return nil, fmt.Errorf("the file has %d rows, at most %d can be imported at once", len(rows), maxSchoolImportRows)
+	}
+	for i := range rows {
+		rows[i].validate()
+	}
+	return rows, nil
+}
+
+// readCSVRecords parses CSV with a comma or semicolon delimiter, as exported by spreadsheet applications.
+func readCSVRecords(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV file: %w", err)
+	}
+	return records, nil
+}
+
+// readXLSXRecords reads the rows of the first worksheet.
+func readXLSXRecords(data []byte) ([][]string, error) {
+	file, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XLSX file: %w", err)
+	}
+	defer file.Close()
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("the XLSX file has no worksheets")
+	}
+	records, err := file.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read XLSX worksheet %q: %w", sheets[0], err)
+	}
+	return records, nil
+}
+
+// tabularSchoolRows maps CSV or XLSX records to rows using the header row and the column mapping.
+func tabularSchoolRows(records [][]string, mapping map[string]string) ([]schoolImportRow, error) {
+	if len(records) == 0 {
+		return nil, errors.New("the file is empty")
+	}
+
+	columns := make(map[string]int) // Field to column index
+	for index, header := range records[0] {
+		if field, ok := schoolImportAliases[normalizeHeader(header)]; ok {
+			if _, taken := columns[field]; !taken {
+				columns[field] = index
+			}
+		}
+	}
+	for field, column := range mapping {
+		index := -1
+		for i, header := range records[0] {
+			if normalizeHeader(header) == normalizeHeader(column) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("column_mapping: column %q for field %q is not in the header", column, field)
+		}
+		columns[field] = index
+	}
+	for _, required := range []string{"name", "country_code"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("the file has no column for %s; add a header or a column_mapping", required)
+		}
+	}
+
+	var rows []schoolImportRow
+	for i, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue // Blank line
+		}
+		row := schoolImportRow{Row: i + 2}
+		cell := func(field string) string {
+			if index, ok := columns[field]; ok && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+		row.Data = SchoolUploadData{
+			Name:         cell("name"),
+			Address:      cell("address"),
+			City:         cell("city"),
+			State:        cell("state"),
+			CountryCode:  strings.ToUpper(cell("country_code")),
+			ZipCode:      cell("zip_code"),
+			ContactEmail: cell("contact_email"),
+			ContactPhone: cell("contact_phone"),
+			Website:      cell("website"),
+		}
+		for _, coordinate := range []struct {
+			field  string
+			target **float64
+		}{{"latitude", &row.Data.Latitude}, {"longitude", &row.Data.Longitude}} {
+			value := cell(coordinate.field)
+			if value == "" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+			if err != nil {
+				row.Errors = append(row.Errors, SchoolImportError{Row: row.Row, Field: coordinate.field, Value: value, Message: "must be a number"})
+				continue
+			}
+			*coordinate.target = &parsed
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// validate checks the row against the SchoolUploadData validation tags
+func (r *schoolImportRow) validate() {
+	for _, fieldError := range validationErrors(r.Data) {
+		r.Errors = append(r.Errors, SchoolImportError{
+			Row:     r.Row,
+			Field:   fieldError.Field,
+			Value:   schoolUploadFieldValue(r.Data, fieldError.Field),
+			Message: fieldError.Message,
+		})
+	}
+	if (r.Data.Latitude == nil) != (r.Data.Longitude == nil) && !r.hasError("latitude") && !r.hasError("longitude") {
+		r.Errors = append(r.Errors, SchoolImportError{Row: r.Row, Field: "latitude", Message: "latitude and longitude must be given together"})
+	}
+}
+
+func (r *schoolImportRow) hasError(field string) bool {
+	for _, rowError := range r.Errors {
+		if rowError.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// schoolUploadFieldValue returns the value of a field by its JSON name, for the report
+func schoolUploadFieldValue(data SchoolUploadData, field string) string {
+	value := reflect.ValueOf(data)
+	for i := 0; i < value.NumField(); i++ {
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("json"), ",")
+		if name != field {
+			continue
+		}
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				return ""
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		return fmt.Sprint(fieldValue.Interface())
+	}
+	return ""
+}
+
+// newImportedSchool creates the school of a valid import row
+func newImportedSchool(data SchoolUploadData, adminUserID uint) models.School {
+	school := models.School{
+		Name:            data.Name,
+		Address:         data.Address,
+		City:            data.City,
+		State:           data.State,
+		CountryCode:     data.CountryCode,
+		ZipCode:         data.ZipCode,
+		ContactEmail:    data.ContactEmail,
+		ContactPhone:    data.ContactPhone,
+		Website:         data.Website,
+		UploadedByAdmin: true,
+		CreatedByUserID: &adminUserID, // Link to the admin who uploaded
+	}
+	// Schools without coordinates are geocoded by the scheduled geocoding task
+	if data.Latitude != nil && data.Longitude != nil {
+		now := time.Now()
+		school.Latitude, school.Longitude, school.GeocodedAt = data.Latitude, data.Longitude, &now
+	}
+	return school
+}
+
+// errImportCancelled stops an import whose job was cancelled
+var errImportCancelled = errors.New("import cancelled")
+
+// importSchools inserts the valid rows in batches and reports invalid rows and rows the database rejected.
+// If a batch fails, its rows are inserted one by one so only the failing rows are reported.
+// After every batch progress is called with the number of rows processed so far. If it returns an error the
+// import stops and the error is returned with the result so far; schools already inserted are kept.
+func importSchools(db *gorm.DB, rows []schoolImportRow, adminUserID uint, progress func(processed int, result SchoolImportResult) error) (SchoolImportResult, error) {
+	result := SchoolImportResult{TotalRows: len(rows), Errors: []SchoolImportError{}}
+	var batch []models.School
+	var batchRows []int
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := db.Create(&batch).Error; err == nil {
+			result.CreatedCount += len(batch)
+		} else {
+			for i := range batch {
+				school := batch[i]
+				if err := db.Create(&school).Error; err != nil {
+					result.Errors = append(result.Errors, SchoolImportError{Row: batchRows[i], Message: "failed to save: " + err.Error()})
+					result.FailedCount++
+					continue
+				}
+				result.CreatedCount++
+			}
+		}
+		batch, batchRows = batch[:0], batchRows[:0]
+	}
+
+	for i, row := range rows {
+		if len(row.Errors) > 0 {
+			result.Errors = append(result.Errors, row.Errors...)
+			result.FailedCount++
+		} else {
+			batch = append(batch, newImportedSchool(row.Data, adminUserID))
+			batchRows = append(batchRows, row.Row)
+		}
+		// Batches are cut every schoolImportInsertBatch rows, so progress advances evenly even through invalid rows
+		if processed := i + 1; processed%schoolImportInsertBatch == 0 || processed == len(rows) {
+			flush()
+			if progress != nil {
+				if err := progress(processed, result); err != nil {
+					return result, err
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// writeSchoolImportReport writes the import errors as CSV. Values come from the uploaded file, so cells that
+// a spreadsheet would run as a formula are escaped.
+func writeSchoolImportReport(w io.Writer, importErrors []SchoolImportError) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"row", "field", "value", "error"}); err != nil {
+		return err
+	}
+	for _, importError := range importErrors {
+		if err := writer.Write([]string{strconv.Itoa(importError.Row), csvSafeCell(importError.Field), csvSafeCell(importError.Value), csvSafeCell(importError.Message)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSafeCell prefixes a value starting with a formula character with a quote, so spreadsheets show it as text
+func csvSafeCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestWriteSchoolImportReportEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeSchoolImportReport(&buf, []SchoolImportError{
+		{Row: 2, Field: "name", Value: "=HYPERLINK(\"http://example.com\")", Message: "invalid"},
+		{Row: 3, Field: "phone", Value: "+1 555 0100", Message: "invalid"},
+		{Row: 4, Field: "email", Value: "@SUM(A1)", Message: "invalid"},
+		{Row: 5, Field: "zip", Value: "-2+3", Message: "invalid"},
+		{Row: 6, Field: "city", Value: "Accra", Message: "invalid"},
+	})
+	if err != nil {
+		t.Fatalf("writing the report: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading the report: %v", err)
+	}
+	want := []string{"'=HYPERLINK(\"http://example.com\")", "'+1 555 0100", "'@SUM(A1)", "'-2+3", "Accra"}
+	for i, value := range want {
+		if got := records[i+1][2]; got != value {
+			t.Errorf("row %d value = %q, want %q", i+1, got, value)
+		}
+	}
+}
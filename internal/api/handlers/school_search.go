@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"mwc_backend/internal/geo"
+	"mwc_backend/internal/models"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxSchoolFacetValues limits how many values each facet returns
+const maxSchoolFacetValues = 50
+
+// SchoolSearchParams are the filters of a school search
+type SchoolSearchParams struct {
+	Query       string `json:"q,omitempty"`            // Free text over name, city, state and address
+	City        string `json:"city,omitempty"`         // Case-insensitive part of the city
+	State       string `json:"state,omitempty"`        // Case-insensitive exact state
+	CountryCode string `json:"country_code,omitempty"` // ISO country code
+	Sort        string `json:"sort,omitempty"`         // distance (default with a location), relevance (default with a query) or name
+	// Montessori attributes. Each list matches schools with any of its values.
+	Programs       []string `json:"programs,omitempty"`
+	AgeMonths      *int     `json:"age_months,omitempty"` // Age of the child, matched against the school's age range
+	Languages      []string `json:"languages,omitempty"`
+	TuitionBands   []string `json:"tuition_bands,omitempty"`
+	Schedules      []string `json:"schedules,omitempty"`
+	Accreditations []string `json:"accreditations,omitempty"`
+	// Location search. With Latitude and Longitude results are sorted by distance and RadiusKm limits how far
+	// they may be; BoundingBox limits them to a map area as west, south, east and north edges.
+	Latitude    *float64    `json:"lat,omitempty"`
+	Longitude   *float64    `json:"lng,omitempty"`
+	RadiusKm    float64     `json:"radius_km,omitempty"`
+	BoundingBox *[4]float64 `json:"bbox,omitempty"`
+	Page        int         `json:"-"`
+	Limit       int         `json:"-"`
+}
+
+// maxSchoolSearchRadiusKm is the largest accepted search radius
+const maxSchoolSearchRadiusKm = 500
+
+// FacetCount is the number of matching schools with a facet value
+type FacetCount struct {
+	Value       string `json:"value"`
+	CountryCode string `json:"country_code,omitempty"` // Country of a state value, as state names repeat across countries
+	Count       int64  `json:"count"`
+}
+
+// SchoolSearchFacets are the counts of matching schools by country, state and program
+type SchoolSearchFacets struct {
+	Countries []FacetCount `json:"countries"`
+	States    []FacetCount `json:"states"`
+	Programs  []FacetCount `json:"programs"`
+}
+
+// SchoolSearchResult is a page of matching schools with the total and facets
+type SchoolSearchResult struct {
+	Schools []models.School
+	Total   int64
+	Facets  SchoolSearchFacets
+}
+
+// schoolSearchParamsFromQuery reads the search parameters from the query string. name is accepted as an alias of q.
+func schoolSearchParamsFromQuery(c *fiber.Ctx) (SchoolSearchParams, error) {
+	params := SchoolSearchParams{
+		Query:       strings.TrimSpace(c.Query("q", c.Query("name"))),
+		City:        strings.TrimSpace(c.Query("city")),
+		State:       strings.TrimSpace(c.Query("state")),
+		CountryCode: strings.TrimSpace(c.Query("country_code")),
+		Sort:        c.Query("sort"),
+		// Comma-separated lists, split by validateAttributes
+		Programs:       []string{c.Query("program")},
+		Languages:      []string{c.Query("language")},
+		TuitionBands:   []string{c.Query("tuition")},
+		Schedules:      []string{c.Query("schedule")},
+		Accreditations: []string{c.Query("accreditation")},
+	}
+	params.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	params.Limit, _ = strconv.Atoi(c.Query("limit", "10"))
+	params.normalize()
+
+	if err := params.validateAttributes(); err != nil {
+		return params, err
+	}
+	if age := c.Query("age_months"); age != "" {
+		parsed, err := strconv.Atoi(age)
+		if err != nil || parsed < 0 {
+			return params, errors.New("age_months must be a positive whole number")
+		}
+		params.AgeMonths = &parsed
+	}
+
+	if c.Query("lat") != "" || c.Query("lng") != "" {
+		lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+		lng, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+		if latErr != nil || lngErr != nil {
+			return params, errors.New("lat and lng must both be numbers")
+		}
+		params.Latitude, params.Longitude = &lat, &lng
+	}
+	if radius := c.Query("radius_km"); radius != "" {
+		parsed, err := strconv.ParseFloat(radius, 64)
+		if err != nil {
+			return params, errors.New("radius_km must be a number")
+		}
+		params.RadiusKm = parsed
+	}
+	if bbox := c.Query("bbox"); bbox != "" {
+		edges := strings.Split(bbox, ",")
+		if len(edges) != 4 {
+			return params, errors.New("bbox must be west,south,east,north")
+		}
+		var box [4]float64
+		for i, edge := range edges {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(edge), 64)
+			if err != nil {
+				return params, errors.New("bbox must be west,south,east,north")
+			}
+			box[i] = parsed
+		}
+		params.BoundingBox = &box
+	}
+	return params, params.validateLocation()
+}
+
+// validateAttributes normalizes the Montessori attribute filters and checks their values. List items may hold
+// comma-separated values.
+func (p *SchoolSearchParams) validateAttributes() error {
+	var err error
+	for _, filter := range []struct {
+		name    string
+		allowed []string
+		values  *[]string
+	}{
+		{"program", schoolPrograms, &p.Programs},
+		{"language", nil, &p.Languages},
+		{"tuition", schoolTuitionBands, &p.TuitionBands},
+		{"schedule", schoolSchedules, &p.Schedules},
+		{"accreditation", schoolAccreditations, &p.Accreditations},
+	} {
+		if *filter.values, err = parseAttributeFilter(filter.name, strings.Join(*filter.values, ","), filter.allowed); err != nil {
+			return err
+		}
+	}
+	for _, language := range p.Languages {
+		if len(language) != 2 {
+			return errors.New("language must be one or more ISO 639-1 codes, e.g. en,it")
+		}
+	}
+	if p.AgeMonths != nil && *p.AgeMonths < 0 {
+		return errors.New("age_months must be a positive whole number")
+	}
+	return nil
+}
+
+// validateLocation checks the location parameters
+func (p SchoolSearchParams) validateLocation() error {
+	if p.Latitude != nil || p.Longitude != nil {
+		if err := validateCoordinates(p.Latitude, p.Longitude); err != nil {
+			return err
+		}
+	}
+	if p.RadiusKm != 0 {
+		if p.Latitude == nil {
+			return errors.New("radius_km requires lat and lng")
+		}
+		if p.RadiusKm < 0 || p.RadiusKm > maxSchoolSearchRadiusKm {
+			return fmt.Errorf("radius_km must be between 0 and %d", maxSchoolSearchRadiusKm)
+		}
+	}
+	if p.BoundingBox != nil {
+		west, south, east, north := p.BoundingBox[0], p.BoundingBox[1], p.BoundingBox[2], p.BoundingBox[3]
+		if !(geo.Point{Lat: south, Lng: west}).Valid() || !(geo.Point{Lat: north, Lng: east}).Valid() || south > north {
+			return errors.New("bbox must be west,south,east,north within valid coordinates")
+		}
+	}
+	return nil
+}
+
+// schoolDistanceSQL is the distance in metres from a point to a school, using the idx_schools_location expression
+const schoolDistanceSQL = "earth_distance(ll_to_earth(?, ?), ll_to_earth(schools.latitude, schools.longitude))"
+
+// normalize applies the default page and page size
+func (p *SchoolSearchParams) normalize() {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.Limit < 1 || p.Limit > 100 {
+		p.Limit = 10
+	}
+}
+
+// prefixTSQuery turns free text into a to_tsquery expression matching every word as a prefix, so
+// "montes rom" finds "Montessori School Rome". Only letters and digits are kept, leaving no query operators.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// apply adds the filters to the query. skip leaves out one filter so its facet counts the other values.
+func (p SchoolSearchParams) apply(query *gorm.DB, skip string) *gorm.DB {
+	if p.Query != "" {
+		// Full-text prefix match, or a trigram match on name or city to tolerate typos
+		if tsQuery := prefixTSQuery(p.Query); tsQuery != "" {
+			query = query.Where("("+models.SchoolSearchDocument+" @@ to_tsquery('simple', ?) OR ? <% schools.name OR ? <% schools.city)", tsQuery, p.Query, p.Query)
+		} else {
+			query = query.Where("(? <% schools.name OR ? <% schools.city)", p.Query, p.Query)
+		}
+	}
+	if p.City != "" && skip != "city" {
+		query = query.Where("schools.city ILIKE ?", "%"+escapeLike(p.City)+"%")
+	}
+	if p.State != "" && skip != "state" {
+		query = query.Where("LOWER(schools.state) = LOWER(?)", p.State)
+	}
+	if p.CountryCode != "" && skip != "country" {
+		query = query.Where("schools.country_code = ?", p.CountryCode)
+	}
+	for _, filter := range []struct {
+		facet, column string
+		values        []string
+	}{
+		{"program", "schools.programs", p.Programs},
+		{"language", "schools.languages", p.Languages},
+		{"schedule", "schools.schedules", p.Schedules},
+		{"accreditation", "schools.accreditations", p.Accreditations},
+	} {
+		if len(filter.values) > 0 && skip != filter.facet {
+			condition, vars := jsonbContainsAny(filter.column, filter.values)
+			query = query.Where(condition, vars...)
+		}
+	}
+	if len(p.TuitionBands) > 0 {
+		query = query.Where("schools.tuition_band IN ?", p.TuitionBands)
+	}
+	if p.AgeMonths != nil {
+		query = query.Where("schools.age_min_months <= ? AND schools.age_max_months >= ?", *p.AgeMonths, *p.AgeMonths)
+	}
+	if p.Latitude != nil && p.RadiusKm > 0 {
+		// earth_box finds candidates with the index; it is a cube, so the exact distance is checked as well
+		radius := p.RadiusKm * 1000
+		query = query.Where("earth_box(ll_to_earth(?, ?), ?) @> ll_to_earth(schools.latitude, schools.longitude) AND "+schoolDistanceSQL+" <= ?",
+			*p.Latitude, *p.Longitude, radius, *p.Latitude, *p.Longitude, radius)
+	}
+	if box := p.BoundingBox; box != nil {
+		query = query.Where("schools.latitude BETWEEN ? AND ?", box[1], box[3])
+		if box[0] <= box[2] {
+			query = query.Where("schools.longitude BETWEEN ? AND ?", box[0], box[2])
+		} else { // The box crosses the antimeridian
+			query = query.Where("(schools.longitude >= ? OR schools.longitude <= ?)", box[0], box[2])
+		}
+	}
+	return query
+}
+
+// order sorts by distance when a location is given, by relevance when there is a query (full-text rank plus
+// trigram similarity of the name) and by name otherwise. sort chooses another of these orders.
+func (p SchoolSearchParams) order(query *gorm.DB) *gorm.DB {
+	tsQuery := prefixTSQuery(p.Query)
+	if p.Latitude != nil && (p.Sort == "" || p.Sort == "distance") {
+		return query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                schoolDistanceSQL + " ASC NULLS LAST, schools.id ASC",
+			Vars:               []interface{}{*p.Latitude, *p.Longitude},
+			WithoutParentheses: true,
+		}})
+	}
+	if p.Query == "" || p.Sort == "name" {
+		return query.Order("schools.name ASC, schools.id ASC")
+	}
+	rank := "word_similarity(?, schools.name)"
+	vars := []interface{}{p.Query}
+	if tsQuery != "" {
+		rank = "ts_rank(" + models.SchoolSearchDocument + ", to_tsquery('simple', ?)) + " + rank
+		vars = []interface{}{tsQuery, p.Query}
+	}
+	return query.Order(clause.OrderBy{Expression: clause.Expr{SQL: rank + " DESC, schools.name ASC, schools.id ASC", Vars: vars, WithoutParentheses: true}})
+}
+
+// searchSchools runs a school search and computes the total and the facets for the same filters.
+func searchSchools(db *gorm.DB, params SchoolSearchParams) (SchoolSearchResult, error) {
+	var result SchoolSearchResult
+	params.normalize()
+
+	if err := params.apply(db.Model(&models.School{}), "").Count(&result.Total).Error; err != nil {
+		return result, err
+	}
+	// Results show the logo and cover only; the gallery comes with the school itself
+	if err := params.order(params.apply(db.Model(&models.School{}), "")).
+		Preload("Media", "kind IN ?", []models.MediaKind{models.MediaLogo, models.MediaCover}).
+		Offset((params.Page - 1) * params.Limit).Limit(params.Limit).
+		Find(&result.Schools).Error; err != nil {
+		return result, err
+	}
+
+	if err := params.apply(db.Model(&models.School{}), "country").
+		Select("schools.country_code AS value, COUNT(*) AS count").
+		Group("schools.country_code").Order("count DESC, value ASC").Limit(maxSchoolFacetValues).
+		Scan(&result.Facets.Countries).Error; err != nil {
+		return result, err
+	}
+	if err := params.apply(db.Model(&models.School{}), "state").
+		Where("schools.state <> ''").
+		Select("schools.state AS value, schools.country_code AS country_code, COUNT(*) AS count").
+		Group("schools.state, schools.country_code").Order("count DESC, value ASC").Limit(maxSchoolFacetValues).
+		Scan(&result.Facets.States).Error; err != nil {
+		return result, err
+	}
+	if err := params.apply(db.Model(&models.School{}), "program").
+		Joins("CROSS JOIN LATERAL jsonb_array_elements_text(COALESCE(schools.programs, '[]')) AS program(value)").
+		Select("program.value AS value, COUNT(*) AS count").
+		Group("program.value").Order("count DESC, value ASC").
+		Scan(&result.Facets.Programs).Error; err != nil {
+		return result, err
+	}
+	if params.Latitude != nil {
+		origin := geo.Point{Lat: *params.Latitude, Lng: *params.Longitude}
+		for i := range result.Schools {
+			school := &result.Schools[i]
+			if school.Latitude != nil && school.Longitude != nil {
+				distance := math.Round(geo.Distance(origin, geo.Point{Lat: *school.Latitude, Lng: *school.Longitude})*100) / 100
+				school.DistanceKm = &distance
+			}
+		}
+	}
+	if result.Facets.Countries == nil {
+		result.Facets.Countries = []FacetCount{}
+	}
+	if result.Facets.States == nil {
+		result.Facets.States = []FacetCount{}
+	}
+	if result.Facets.Programs == nil {
+		result.Facets.Programs = []FacetCount{}
+	}
+	return result, nil
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"fmt"
+	"mwc_backend/internal/models"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseSchoolSearchQuery runs schoolSearchParamsFromQuery on a query string
+func parseSchoolSearchQuery(t *testing.T, query string) (SchoolSearchParams, error) {
+	t.Helper()
+	var params SchoolSearchParams
+	var parseErr error
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		params, parseErr = schoolSearchParamsFromQuery(c)
+		return nil
+	})
+	if _, err := app.Test(httptest.NewRequest("GET", "/?"+query, nil), -1); err != nil {
+		t.Fatalf("GET /?%s: %v", query, err)
+	}
+	return params, parseErr
+}
+
+func TestSchoolSearchLocationParams(t *testing.T) {
+	cases := []struct {
+		query   string
+		wantErr string
+		check   func(SchoolSearchParams) bool
+	}{
+		{"lat=41.9&lng=12.5", "", func(p SchoolSearchParams) bool {
+			return p.Latitude != nil && *p.Latitude == 41.9 && *p.Longitude == 12.5 && p.RadiusKm == 0
+		}},
+		{"lat=41.9&lng=12.5&radius_km=25", "", func(p SchoolSearchParams) bool { return p.RadiusKm == 25 }},
+		{"lat=41.9&lng=12.5&radius_km=500", "", func(p SchoolSearchParams) bool { return p.RadiusKm == 500 }},
+		{"lat=41.9", "lat and lng must both be numbers", nil},
+		{"lat=north&lng=12.5", "lat and lng must both be numbers", nil},
+		{"lat=91&lng=12.5", "latitude must be between", nil},
+		{"lat=41.9&lng=-181", "latitude must be between", nil},
+		{"radius_km=10", "radius_km requires lat and lng", nil},
+		{"lat=41.9&lng=12.5&radius_km=far", "radius_km must be a number", nil},
+		{"lat=41.9&lng=12.5&radius_km=-1", "radius_km must be between", nil},
+		{"lat=41.9&lng=12.5&radius_km=501", "radius_km must be between", nil},
+		{"bbox=12.3,41.8,12.6,42.0", "", func(p SchoolSearchParams) bool {
+			return p.BoundingBox != nil && *p.BoundingBox == [4]float64{12.3, 41.8, 12.6, 42.0}
+		}},
+		{"bbox=+12.3,+41.8,+12.6,+42.0", "", func(p SchoolSearchParams) bool { return p.BoundingBox != nil }},
+		// A box crossing the antimeridian has its west edge east of its east edge
+		{"bbox=170,-20,-170,-10", "", func(p SchoolSearchParams) bool {
+			return p.BoundingBox != nil && *p.BoundingBox == [4]float64{170, -20, -170, -10}
+		}},
+		{"bbox=12.3,41.8,12.6", "bbox must be west,south,east,north", nil},
+		{"bbox=12.3,41.8,12.6,42.0,1", "bbox must be west,south,east,north", nil},
+		{"bbox=west,41.8,12.6,42.0", "bbox must be west,south,east,north", nil},
+		{"bbox=12.3,42.0,12.6,41.8", "bbox must be west,south,east,north within valid coordinates", nil},
+		{"bbox=-181,41.8,12.6,42.0", "bbox must be west,south,east,north within valid coordinates", nil},
+		{"bbox=12.3,-91,12.6,42.0", "bbox must be west,south,east,north within valid coordinates", nil},
+	}
+	for _, tc := range cases {
+		params, err := parseSchoolSearchQuery(t, tc.query)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("%s: error = %v, want %q", tc.query, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tc.query, err)
+			continue
+		}
+		if !tc.check(params) {
+			t.Errorf("%s: parsed %+v", tc.query, params)
+		}
+	}
+}
+
+func TestValidateLocation(t *testing.T) {
+	lat, lng := 41.9, 12.5
+	cases := []struct {
+		name   string
+		params SchoolSearchParams
+		valid  bool
+	}{
+		{"no location", SchoolSearchParams{}, true},
+		{"point", SchoolSearchParams{Latitude: &lat, Longitude: &lng}, true},
+		{"latitude alone", SchoolSearchParams{Latitude: &lat}, false},
+		{"longitude alone", SchoolSearchParams{Longitude: &lng}, false},
+		{"radius with a point", SchoolSearchParams{Latitude: &lat, Longitude: &lng, RadiusKm: 10}, true},
+		{"radius without a point", SchoolSearchParams{RadiusKm: 10}, false},
+		{"radius too large", SchoolSearchParams{Latitude: &lat, Longitude: &lng, RadiusKm: maxSchoolSearchRadiusKm + 1}, false},
+		{"box", SchoolSearchParams{BoundingBox: &[4]float64{12.3, 41.8, 12.6, 42.0}}, true},
+		{"box across the antimeridian", SchoolSearchParams{BoundingBox: &[4]float64{170, -20, -170, -10}}, true},
+		{"box over the whole world", SchoolSearchParams{BoundingBox: &[4]float64{-180, -90, 180, 90}}, true},
+		{"box with south above north", SchoolSearchParams{BoundingBox: &[4]float64{12.3, 42.0, 12.6, 41.8}}, false},
+		{"box outside valid coordinates", SchoolSearchParams{BoundingBox: &[4]float64{12.3, 41.8, 190, 42.0}}, false},
+	}
+	for _, tc := range cases {
+		if err := tc.params.validateLocation(); (err == nil) != tc.valid {
+			t.Errorf("%s: validateLocation() = %v, want valid %v", tc.name, err, tc.valid)
+		}
+	}
+}
+
+func TestPrefixTSQuery(t *testing.T) {
+	cases := map[string]string{
+		"":                          "",
+		"   ":                       "",
+		"montes":                    "montes:*",
+		"Montes ROM":                "montes:* & rom:*",
+		"  casa   dei  bambini ":    "casa:* & dei:* & bambini:*",
+		"St. Mary's":                "st:* & mary:* & s:*",
+		"Zürich Kita":               "zürich:* & kita:*",
+		"school 42":                 "school:* & 42:*",
+		"a & b | !c <-> d:* (e)":    "a:* & b:* & c:* & d:* & e:*",
+		"'quoted' \"text\"\\ :*":    "quoted:* & text:*",
+		"&|!():*<->":                "",
+		"montessori-school/rome,it": "montessori:* & school:* & rome:* & it:*",
+	}
+	for text, want := range cases {
+		if got := prefixTSQuery(text); got != want {
+			t.Errorf("prefixTSQuery(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestSchoolSearchParamsFromQuery(t *testing.T) {
+	params, err := parseSchoolSearchQuery(t, "name=+Casa+&city=+Rome+&state=Lazio&country_code=IT&sort=name"+
+		"&program=primary,elementary&language=en,it&tuition=low&schedule=full_day&accreditation=ami&age_months=36&page=3&limit=20")
+	if err != nil {
+		t.Fatalf("parsing a full query: %v", err)
+	}
+	if params.Query != "Casa" || params.City != "Rome" || params.State != "Lazio" || params.CountryCode != "IT" || params.Sort != "name" {
+		t.Errorf("text filters = %+v", params)
+	}
+	if !slices.Equal(params.Programs, []string{"primary", "elementary"}) || !slices.Equal(params.Languages, []string{"en", "it"}) ||
+		!slices.Equal(params.TuitionBands, []string{"low"}) || !slices.Equal(params.Schedules, []string{"full_day"}) ||
+		!slices.Equal(params.Accreditations, []string{"ami"}) {
+		t.Errorf("attribute filters = %+v", params)
+	}
+	if params.AgeMonths == nil || *params.AgeMonths != 36 || params.Page != 3 || params.Limit != 20 {
+		t.Errorf("age and paging = %v, %d, %d", params.AgeMonths, params.Page, params.Limit)
+	}
+
+	// q wins over its alias name, and paging falls back to the defaults
+	params, err = parseSchoolSearchQuery(t, "q=montessori&name=casa&page=0&limit=1000")
+	if err != nil || params.Query != "montessori" || params.Page != 1 || params.Limit != 10 {
+		t.Errorf("q with name and bad paging = %+v, %v", params, err)
+	}
+	params, err = parseSchoolSearchQuery(t, "page=x&limit=y")
+	if err != nil || params.Page != 1 || params.Limit != 10 {
+		t.Errorf("non-numeric paging = %d, %d, %v", params.Page, params.Limit, err)
+	}
+
+	for query, wantErr := range map[string]string{
+		"program=kindergarten": "program",
+		"tuition=cheap":        "tuition",
+		"language=english":     "language must be one or more ISO 639-1 codes",
+		"age_months=-1":        "age_months must be a positive whole number",
+		"age_months=two":       "age_months must be a positive whole number",
+	} {
+		if _, err := parseSchoolSearchQuery(t, query); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("%s: error = %v, want %q", query, err, wantErr)
+		}
+	}
+}
+
+func TestSchoolSearchCityFilter(t *testing.T) {
+	db := dryRunDB(t, nil)
+	var schools []models.School
+	stmt := SchoolSearchParams{City: "san_100%"}.apply(db.Model(&models.School{}), "").Find(&schools).Statement
+	if sql := stmt.SQL.String(); !strings.Contains(sql, "schools.city ILIKE $1") {
+		t.Errorf("city filter SQL = %s", sql)
+	}
+	if len(stmt.Vars) != 1 || stmt.Vars[0] != `%san\_100\%%` {
+		t.Errorf("city filter vars = %v, want the escaped city as a contains pattern", stmt.Vars)
+	}
+
+	stmt = SchoolSearchParams{City: "Rome"}.apply(db.Model(&models.School{}), "city").Find(&schools).Statement
+	if strings.Contains(stmt.SQL.String(), "city") {
+		t.Errorf("the city facet kept its own filter: %s", stmt.SQL.String())
+	}
+}
+
+func TestSearchSchoolsTotalAndFacets(t *testing.T) {
+	db := openTestDB(t)
+	// A unique city keeps the schools apart from any already in the database
+	city := fmt.Sprintf("Testville%d", time.Now().UnixNano())
+	for _, school := range []models.School{
+		{Name: "Casa dei Bambini", City: city + " North", State: "Lazio", CountryCode: "IT", Programs: []models.SchoolProgram{models.ProgramPrimary}},
+		{Name: "Montessori Roma", City: city + " South", State: "Lazio", CountryCode: "IT", Programs: []models.SchoolProgram{models.ProgramPrimary, models.ProgramElementary}},
+		{Name: "Montessori Milano", City: city, State: "Lombardia", CountryCode: "IT", Programs: []models.SchoolProgram{models.ProgramElementary}},
+		{Name: "Montessori Zürich", City: city, State: "Zürich", CountryCode: "CH", Programs: []models.SchoolProgram{models.ProgramToddler}},
+	} {
+		if err := db.Create(&school).Error; err != nil {
+			t.Fatalf("creating school %s: %v", school.Name, err)
+		}
+	}
+	facet := func(counts []FacetCount, value string) int64 {
+		for _, count := range counts {
+			if count.Value == value {
+				return count.Count
+			}
+		}
+		return 0
+	}
+
+	// The city matches case-insensitively anywhere in the name
+	result, err := searchSchools(db, SchoolSearchParams{City: strings.ToUpper(city)})
+	if err != nil {
+		t.Fatalf("searching by city: %v", err)
+	}
+	if result.Total != 4 || len(result.Schools) != 4 {
+		t.Errorf("city search: total %d with %d schools, want 4", result.Total, len(result.Schools))
+	}
+	if facet(result.Facets.Countries, "IT") != 3 || facet(result.Facets.Countries, "CH") != 1 || facet(result.Facets.Programs, "primary") != 2 {
+		t.Errorf("city search facets = %+v", result.Facets)
+	}
+
+	// Each facet ignores its own filter but follows the others
+	result, err = searchSchools(db, SchoolSearchParams{City: city, CountryCode: "IT", Programs: []string{"elementary"}, Limit: 1})
+	if err != nil {
+		t.Fatalf("searching with filters: %v", err)
+	}
+	if result.Total != 2 || len(result.Schools) != 1 {
+		t.Errorf("filtered search: total %d with %d schools, want 2 with 1 on the page", result.Total, len(result.Schools))
+	}
+	if facet(result.Facets.Countries, "IT") != 2 || facet(result.Facets.Countries, "CH") != 0 {
+		t.Errorf("country facet = %+v, want the program filter applied", result.Facets.Countries)
+	}
+	if facet(result.Facets.Programs, "primary") != 2 || facet(result.Facets.Programs, "elementary") != 2 || facet(result.Facets.Programs, "toddler") != 0 {
+		t.Errorf("program facet = %+v, want the country filter applied", result.Facets.Programs)
+	}
+	if facet(result.Facets.States, "Lazio") != 1 || facet(result.Facets.States, "Lombardia") != 1 {
+		t.Errorf("state facet = %+v", result.Facets.States)
+	}
+
+	// A city with LIKE wildcards matches only literally
+	result, err = searchSchools(db, SchoolSearchParams{City: city + "%"})
+	if err != nil || result.Total != 0 {
+		t.Errorf("city with a wildcard: total %d, %v, want 0", result.Total, err)
+	}
+}
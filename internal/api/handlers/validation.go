@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate checks request structs against their `validate` tags. Field errors use the JSON field names.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// FieldError describes why a request field failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// validationErrors validates s and returns one FieldError per invalid field, or nil if s is valid.
+func validationErrors(s interface{}) []FieldError {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return []FieldError{{Message: err.Error()}}
+	}
+	result := make([]FieldError, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		result = append(result, FieldError{Field: fieldError.Field(), Message: validationMessage(fieldError)})
+	}
+	return result
+}
+
+// validationMessage explains a failed validation tag in plain words
+func validationMessage(fieldError validator.FieldError) string {
+	param := fieldError.Param()
+	isString := fieldError.Kind() == reflect.String
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "alpha":
+		return "must contain only letters"
+	case "len":
+		return fmt.Sprintf("must be %s characters long", param)
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(param, " ", ", ")
+	case "unique":
+		return "must not contain duplicates"
+	case "min":
+		if isString {
+			return fmt.Sprintf("must be at least %s characters long", param)
+		}
+		return "must be at least " + param
+	case "max":
+		if isString {
+			return fmt.Sprintf("must be at most %s characters long", param)
+		}
+		return "must be at most " + param
+	}
+	return fmt.Sprintf("failed the %s check", fieldError.Tag())
+}
+
+// validationErrorsMessage joins field errors into one error message
+func validationErrorsMessage(fieldErrors []FieldError) string {
+	messages := make([]string, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		messages = append(messages, strings.TrimSpace(fieldError.Field+" "+fieldError.Message))
+	}
+	return strings.Join(messages, "; ")
+}
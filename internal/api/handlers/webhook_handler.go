@@ -5,22 +5,23 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 	"log"
+	"mwc_backend/config"
 	"mwc_backend/internal/email"
 	"mwc_backend/internal/models"
 )
 
 // @Summary Webhook for Unread Message Notification
-// @Description Receives a payload from the message queue system to process and send email notifications for unread messages. This endpoint is intended for internal system use and should be secured.
+// @Description Receives a payload from the message queue system to process and send email notifications for unread messages. Recipients with a digest frequency other than immediate get the message in their next digest instead. This endpoint is intended for internal system use and should be secured.
 // @Tags webhooks,notifications
 // @Accept json
 // @Produce json
 // @Param payload body UnreadMessagePayload true "Details of the unread message to be processed for notification"
-// @Success 200 {object} map[string]string "Notification processed successfully (email sent or message already read)"
+// @Success 200 {object} map[string]string "Notification processed successfully (email sent, queued for digest, message already read, or recipient opted out)"
 // @Failure 400 {object} map[string]string "Bad request or invalid payload"
 // @Failure 401 {object} map[string]string "Unauthorized access (if webhook security is implemented)"
 // @Failure 500 {object} map[string]string "Internal server error (e.g., database error, email sending failure)"
 // @Router /webhooks/notify-unread-message [post]
-func HandleUnreadMessageNotification(db *gorm.DB, emailService email.EmailService) fiber.Handler {
+func HandleUnreadMessageNotification(db *gorm.DB, emailService email.EmailService, cfg *config.Config) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// TODO: Implement robust webhook security. Example: Check a secret header.
 		// webhookSecret := os.Getenv("WEBHOOK_SECRET")
@@ -50,6 +51,11 @@ func HandleUnreadMessageNotification(db *gorm.DB, emailService email.EmailServic
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error fetching message details"}) // 500, might retry if consumer is set up for it.
 		}
 
+		if conversationMuted(db, message.ConversationID, message.RecipientID) {
+			log.Printf("[Webhook] Recipient %d muted the conversation of MessageID %d. No notification sent.", message.RecipientID, message.ID)
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Recipient muted the conversation, notification not sent."})
+		}
+
 		// If we found the message and it's still unread, send the email.
 		log.Printf("[Webhook] MessageID %d is confirmed unread. Sending email notification to Recipient: %s.", message.ID, message.Recipient.Email)
 
@@ -71,11 +77,26 @@ func HandleUnreadMessageNotification(db *gorm.DB, emailService email.EmailServic
 			truncateMessage(message.Content, 100), // Use the helper
 		)
 
-		if err := emailService.SendEmail(message.Recipient.Email, emailSubject, emailBody); err != nil {
+		item := models.DigestItem{
+			Title:      fmt.Sprintf("New message from %s", senderName),
+			Summary:    truncateMessage(message.Content, 100),
+			SourceType: "Message",
+			SourceID:   message.ID,
+		}
+		result, err := deliverNotificationEmail(db, emailService, cfg, message.Recipient, models.NotificationCategoryMessages, emailSubject, emailBody, item)
+		if err != nil {
 			log.Printf("[Webhook] Failed to send unread message email to %s for MessageID %d: %v", message.Recipient.Email, message.ID, err)
 			// This is an error in processing, might warrant a 5xx for retry by consumer.
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to send notification email"})
 		}
+		switch result {
+		case deliverySkipped:
+			log.Printf("[Webhook] Recipient %d has opted out of message emails. No notification sent for MessageID %d.", message.RecipientID, message.ID)
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Recipient has opted out of message emails, notification not sent."})
+		case deliveryQueued:
+			log.Printf("[Webhook] MessageID %d queued for the %s digest of Recipient %d.", message.ID, message.Recipient.DigestFrequency, message.RecipientID)
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Notification queued for the recipient's digest."})
+		}
 
 		log.Printf("[Webhook] Unread message email notification sent successfully to %s for MessageID %d.", message.Recipient.Email, message.ID)
 		LogUserAction(db, 0, "SYSTEM_UNREAD_MSG_EMAIL_SENT", message.ID, "Message", fmt.Sprintf("Email sent to %s", message.Recipient.Email), c)
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gofiber/websocket/v2"
+)
+
+const (
+	wsWriteWait      = 10 * time.Second    // Time allowed to write a message to the client
+	wsPongWait       = 60 * time.Second    // Time allowed between messages or pongs from the client
+	wsPingPeriod     = wsPongWait * 9 / 10 // Send pings this often, must be less than wsPongWait
+	wsSendBuffer     = 64                  // Messages queued per connection before it is evicted as a slow consumer
+	wsMaxMessageSize = 64 * 1024           // Largest message accepted from a client
+)
+
+// errWSClientClosed is returned when writing to a connection that was closed or evicted
+var errWSClientClosed = errors.New("websocket connection closed")
+
+// wsClient is a single WebSocket connection of a user. Only its writePump goroutine writes to the
+// connection; everyone else queues messages on the bounded send buffer.
+type wsClient struct {
+	id          string
+	userID      uint
+	conn        *websocket.Conn
+	connectedAt time.Time
+	topics      map[string]struct{} // Subscribed topics, guarded by WebSocketHandler.topicsMux
+
+	send      chan []byte
+	done      chan struct{} // Closed to stop the writer
+	stopped   chan struct{} // Closed once the writer has stopped using the connection
+	closeOnce sync.Once
+	closeMux  sync.RWMutex // Guards enqueueing against close
+	closed    bool
+}
+
+func newWSClient(id string, userID uint, conn *websocket.Conn) *wsClient {
+	return &wsClient{
+		id:          id,
+		userID:      userID,
+		conn:        conn,
+		connectedAt: time.Now(),
+		topics:      make(map[string]struct{}),
+		send:        make(chan []byte, wsSendBuffer),
+		done:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+}
+
+// writeJSON queues a JSON message for the connection
+func (cl *wsClient) writeJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return cl.writeRaw(data)
+}
+
+// writeRaw queues an already encoded JSON message for the connection without blocking.
+// A client whose buffer is full is too slow to keep up and is disconnected.
+func (cl *wsClient) writeRaw(data []byte) error {
+	cl.closeMux.RLock()
+	defer cl.closeMux.RUnlock()
+	if cl.closed {
+		return errWSClientClosed
+	}
+
+	select {
+	case cl.send <- data:
+		return nil
+	default:
+		log.Printf("WebSocket: Evicting slow consumer: user %d, connection %s", cl.userID, cl.id)
+		go cl.close()
+		return errWSClientClosed
+	}
+}
+
+// writePump writes queued messages and periodic pings to the connection until the client is closed.
+func (cl *wsClient) writePump() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		// Closing the connection unblocks the read loop in HandleWebSocket, which unregisters the client.
+		cl.conn.Close()
+		close(cl.stopped)
+	}()
+
+	for {
+		select {
+		case data := <-cl.send:
+			cl.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := cl.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				log.Printf("WebSocket: Error writing to user %d on connection %s: %v", cl.userID, cl.id, err)
+				return
+			}
+		case <-ticker.C:
+			cl.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := cl.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				log.Printf("WebSocket: Error pinging user %d on connection %s: %v", cl.userID, cl.id, err)
+				return
+			}
+		case <-cl.done:
+			cl.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "connection closed by server"),
+				time.Now().Add(wsWriteWait))
+			return
+		}
+	}
+}
+
+// close stops the writer. It is safe to call more than once and from any goroutine.
+func (cl *wsClient) close() {
+	cl.closeOnce.Do(func() {
+		cl.closeMux.Lock()
+		cl.closed = true
+		cl.closeMux.Unlock()
+		close(cl.done)
+	})
+}
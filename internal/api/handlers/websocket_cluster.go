@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mwc_backend/internal/queue"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	WebSocketFanoutExchange = "websocket.fanout.exchange" // Fanout exchange every API node subscribes to
+	wsHeartbeatInterval     = 30 * time.Second            // How often a node publishes its presence snapshot
+	wsNodeTimeout           = 3 * wsHeartbeatInterval     // Nodes not heard from for this long are considered gone
+)
+
+// Kinds of cluster envelopes
+const (
+	wsEnvelopeDeliver  = "deliver"  // Deliver Message to UserIDs, or to everyone if Broadcast
+	wsEnvelopePresence = "presence" // Devices of UserID on the origin node changed
+	wsEnvelopeSnapshot = "snapshot" // Full presence of the origin node, sent periodically
+	wsEnvelopeSync     = "sync"     // A node started and asks the others for a snapshot
+	wsEnvelopeTopic    = "topic"    // Deliver Message to the subscribers of Topic
+)
+
+// wsEnvelope is published to the fanout exchange to reach WebSocket clients connected to other nodes.
+type wsEnvelope struct {
+	Kind      string          `json:"kind"`
+	Origin    string          `json:"origin"`
+	UserIDs   []uint          `json:"user_ids,omitempty"`
+	Broadcast bool            `json:"broadcast,omitempty"`
+	Topic     string          `json:"topic,omitempty"`
+	Message   json.RawMessage `json:"message,omitempty"`
+	UserID    uint            `json:"user_id,omitempty"`
+	Devices   int             `json:"devices,omitempty"`
+	Snapshot  map[uint]int    `json:"snapshot,omitempty"`
+}
+
+// remoteNode is the last known presence of another API node
+type remoteNode struct {
+	devices  map[uint]int
+	lastSeen time.Time
+}
+
+// wsCluster relays WebSocket messages and presence between API nodes through a RabbitMQ fanout exchange.
+// Each node consumes from its own exclusive queue, delivers to its local connections and ignores its own envelopes.
+type wsCluster struct {
+	h      *WebSocketHandler
+	mq     queue.MessageQueueService
+	nodeID string
+
+	mu     sync.RWMutex
+	remote map[string]*remoteNode
+}
+
+// newWSCluster declares the node's queue and starts relaying. It returns nil when RabbitMQ is not
+// available, in which case WebSocket delivery stays local to this node.
+func newWSCluster(h *WebSocketHandler, mq queue.MessageQueueService) *wsCluster {
+	if mq == nil || !mq.IsInitialized() {
+		log.Println("WebSocket: RabbitMQ not initialized, messages will only reach clients connected to this node.")
+		return nil
+	}
+
+	c := &wsCluster{h: h, mq: mq, nodeID: uuid.NewString(), remote: make(map[string]*remoteNode)}
+	if err := c.start(); err != nil {
+		log.Printf("WebSocket: Failed to set up cross-node delivery, messages will only reach clients connected to this node: %v", err)
+		return nil
+	}
+	return c
+}
+
+func (c *wsCluster) start() error {
+	if err := c.mq.DeclareExchange(WebSocketFanoutExchange, "fanout", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare exchange '%s': %w", WebSocketFanoutExchange, err)
+	}
+	queueName := "q.websocket.node." + c.nodeID
+	// Exclusive and auto-delete: the queue disappears with this node's connection.
+	if _, err := c.mq.DeclareQueue(queueName, false, true, true, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue '%s': %w", queueName, err)
+	}
+	if err := c.mq.BindQueue(queueName, "", WebSocketFanoutExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind queue '%s': %w", queueName, err)
+	}
+	if err := c.mq.Consume(queueName, "websocket-"+c.nodeID, c.handle); err != nil {
+		return err
+	}
+
+	c.publish(wsEnvelope{Kind: wsEnvelopeSync})
+	go c.heartbeat()
+	log.Printf("WebSocket: Cross-node delivery enabled, node %s", c.nodeID)
+	return nil
+}
+
+// heartbeat periodically publishes this node's presence and forgets nodes that stopped publishing.
+func (c *wsCluster) heartbeat() {
+	ticker := time.NewTicker(wsHeartbeatInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.publishSnapshot()
+
+		c.mu.Lock()
+		for nodeID, node := range c.remote {
+			if time.Since(node.lastSeen) > wsNodeTimeout {
+				log.Printf("WebSocket: Node %s timed out, dropping its presence", nodeID)
+				delete(c.remote, nodeID)
+			}
+		}
+		c.mu.Unlock()
+	}
+}
+
+// handle processes an envelope from the fanout exchange.
+func (c *wsCluster) handle(delivery amqp.Delivery) error {
+	var envelope wsEnvelope
+	if err := json.Unmarshal(delivery.Body, &envelope); err != nil {
+		return fmt.Errorf("invalid websocket envelope: %w", err)
+	}
+	if envelope.Origin == c.nodeID {
+		return nil // Already delivered locally
+	}
+
+	switch envelope.Kind {
+	case wsEnvelopeDeliver:
+		if envelope.Broadcast {
+			c.h.broadcastLocal(envelope.Message)
+			return nil
+		}
+		for _, userID := range envelope.UserIDs {
+			c.h.sendRawToUser(userID, envelope.Message)
+		}
+	case wsEnvelopeTopic:
+		c.h.publishLocal(envelope.Topic, envelope.Message)
+	case wsEnvelopePresence:
+		c.mu.Lock()
+		node := c.node(envelope.Origin)
+		if envelope.Devices > 0 {
+			node.devices[envelope.UserID] = envelope.Devices
+		} else {
+			delete(node.devices, envelope.UserID)
+		}
+		c.mu.Unlock()
+	case wsEnvelopeSnapshot:
+		c.mu.Lock()
+		node := c.node(envelope.Origin)
+		node.devices = envelope.Snapshot
+		if node.devices == nil {
+			node.devices = make(map[uint]int)
+		}
+		c.mu.Unlock()
+	case wsEnvelopeSync:
+		c.publishSnapshot()
+	}
+	return nil
+}
+
+// node returns the remote node, creating it if needed, and marks it as seen. c.mu must be held.
+func (c *wsCluster) node(nodeID string) *remoteNode {
+	node, ok := c.remote[nodeID]
+	if !ok {
+		node = &remoteNode{devices: make(map[uint]int)}
+		c.remote[nodeID] = node
+	}
+	node.lastSeen = time.Now()
+	return node
+}
+
+// remoteDeviceCount returns the number of connections a user has on other nodes.
+func (c *wsCluster) remoteDeviceCount(userID uint) int {
+	if c == nil {
+		return 0
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	devices := 0
+	for _, node := range c.remote {
+		devices += node.devices[userID]
+	}
+	return devices
+}
+
+// publishDeliver sends a message to the users' connections on other nodes. Users with no remote
+// connections are left out; a nil userIDs with broadcast reaches every connection.
+func (c *wsCluster) publishDeliver(userIDs []uint, broadcast bool, message []byte) {
+	if c == nil {
+		return
+	}
+	var remoteUsers []uint
+	for _, userID := range userIDs {
+		if c.remoteDeviceCount(userID) > 0 {
+			remoteUsers = append(remoteUsers, userID)
+		}
+	}
+	if !broadcast && len(remoteUsers) == 0 {
+		return
+	}
+	c.publish(wsEnvelope{Kind: wsEnvelopeDeliver, UserIDs: remoteUsers, Broadcast: broadcast, Message: message})
+}
+
+// publishTopic sends a topic message to the subscribers connected to other nodes. Subscriptions are
+// kept per node, so every node receives it and delivers to its own subscribers.
+func (c *wsCluster) publishTopic(topic string, message []byte) {
+	if c == nil {
+		return
+	}
+	c.publish(wsEnvelope{Kind: wsEnvelopeTopic, Topic: topic, Message: message})
+}
+
+// publishPresence announces the user's current number of connections on this node.
+func (c *wsCluster) publishPresence(userID uint, devices int) {
+	if c == nil {
+		return
+	}
+	c.publish(wsEnvelope{Kind: wsEnvelopePresence, UserID: userID, Devices: devices})
+}
+
+func (c *wsCluster) publishSnapshot() {
+	c.publish(wsEnvelope{Kind: wsEnvelopeSnapshot, Snapshot: c.h.localPresence()})
+}
+
+func (c *wsCluster) publish(envelope wsEnvelope) {
+	envelope.Origin = c.nodeID
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		log.Printf("WebSocket: Error marshalling %s envelope: %v", envelope.Kind, err)
+		return
+	}
+	if err := c.mq.Publish(context.Background(), WebSocketFanoutExchange, "", body, 0); err != nil {
+		log.Printf("WebSocket: Error publishing %s envelope: %v", envelope.Kind, err)
+	}
+}
@@ -1,15 +1,20 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"mwc_backend/config"
 	"mwc_backend/internal/models"
+	"mwc_backend/internal/queue"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -17,23 +22,42 @@ import (
 type WebSocketHandler struct {
 	db  *gorm.DB
 	cfg *config.Config
-	// Map of client connections by user ID
-	clients    map[uint]*websocket.Conn
+	// Connections by user ID, then by connection ID. A user has one connection per open tab or device.
+	clients    map[uint]map[string]*wsClient
 	clientsMux sync.RWMutex
+	// Subscribed local connections by topic
+	topics    map[string]map[*wsClient]struct{}
+	topicsMux sync.RWMutex
+	// Shared with the HTTP handlers, set with SetMessaging
+	notifier  *Notifier
+	messenger *Messenger
+	// Relays messages to clients connected to other API nodes; nil when RabbitMQ is unavailable
+	cluster *wsCluster
 }
 
 // NewWebSocketHandler creates a new WebSocketHandler
-func NewWebSocketHandler(db *gorm.DB, cfg *config.Config) *WebSocketHandler {
-	return &WebSocketHandler{
+func NewWebSocketHandler(db *gorm.DB, cfg *config.Config, mq queue.MessageQueueService) *WebSocketHandler {
+	h := &WebSocketHandler{
 		db:      db,
 		cfg:     cfg,
-		clients: make(map[uint]*websocket.Conn),
+		clients: make(map[uint]map[string]*wsClient),
+		topics:  make(map[string]map[*wsClient]struct{}),
 	}
+	h.cluster = newWSCluster(h, mq)
+	return h
+}
+
+// SetMessaging sets the Notifier and Messenger used for receipts and messages sent over the WebSocket.
+// The notifier pushes through this handler, so both can only be created once the handler exists.
+func (h *WebSocketHandler) SetMessaging(notifier *Notifier, messenger *Messenger) {
+	h.notifier = notifier
+	h.messenger = messenger
 }
 
 // WebSocketMessage represents a message sent over WebSocket
 type WebSocketMessage struct {
 	Type    string      `json:"type"`
+	Topic   string      `json:"topic,omitempty"` // Set on subscribe/unsubscribe requests and on messages published to a topic
 	Payload interface{} `json:"payload"`
 }
 
@@ -46,22 +70,31 @@ func (h *WebSocketHandler) HandleWebSocket(c *websocket.Conn) {
 		return
 	}
 
-	// Register client
-	h.clientsMux.Lock()
-	h.clients[userID] = c
-	h.clientsMux.Unlock()
-
-	log.Printf("WebSocket: Client connected: %d", userID)
+	// Register client and start its writer; all writes go through the client's send buffer
+	client := h.register(userID, c)
+	go client.writePump()
+	defer func() {
+		h.unregister(client)
+		<-client.stopped // The connection must not be used after this handler returns
+	}()
+
+	// Close the connection if the client stops answering pings
+	c.SetReadLimit(wsMaxMessageSize)
+	c.SetReadDeadline(time.Now().Add(wsPongWait))
+	c.SetPongHandler(func(string) error {
+		return c.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
 
 	// Send welcome message
 	welcomeMsg := WebSocketMessage{
 		Type: "welcome",
 		Payload: map[string]interface{}{
-			"message": "Welcome to Montessori World Connect WebSocket server",
-			"time":    time.Now(),
+			"message":       "Welcome to Montessori World Connect WebSocket server",
+			"time":          time.Now(),
+			"connection_id": client.id,
 		},
 	}
-	if err := c.WriteJSON(welcomeMsg); err != nil {
+	if err := client.writeJSON(welcomeMsg); err != nil {
 		log.Printf("WebSocket: Error sending welcome message: %v", err)
 	}
 
@@ -72,6 +105,7 @@ func (h *WebSocketHandler) HandleWebSocket(c *websocket.Conn) {
 			log.Printf("WebSocket: Error reading message: %v", err)
 			break
 		}
+		c.SetReadDeadline(time.Now().Add(wsPongWait)) // Any client message counts as activity
 
 		// Parse message
 		var wsMsg WebSocketMessage
@@ -90,110 +124,276 @@ func (h *WebSocketHandler) HandleWebSocket(c *websocket.Conn) {
 					"time": time.Now(),
 				},
 			}
-			if err := c.WriteJSON(pongMsg); err != nil {
+			if err := client.writeJSON(pongMsg); err != nil {
 				log.Printf("WebSocket: Error sending pong message: %v", err)
 			}
 		case "message":
 			// Handle direct message
-			h.handleDirectMessage(userID, wsMsg.Payload)
+			h.handleDirectMessage(client, wsMsg.Payload)
+		case "typing":
+			h.handleTyping(userID, wsMsg.Payload)
+		case "delivered":
+			h.handleDelivered(userID, wsMsg.Payload)
+		case "read":
+			h.handleRead(userID, wsMsg.Payload)
+		case "subscribe", "unsubscribe":
+			h.handleSubscription(client, wsMsg)
 		default:
 			log.Printf("WebSocket: Unknown message type: %s", wsMsg.Type)
 		}
 	}
+}
+
+// register adds a connection to the user's set of connections
+func (h *WebSocketHandler) register(userID uint, conn *websocket.Conn) *wsClient {
+	client := newWSClient(uuid.NewString(), userID, conn)
 
-	// Unregister client
 	h.clientsMux.Lock()
-	delete(h.clients, userID)
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[string]*wsClient)
+	}
+	h.clients[userID][client.id] = client
+	devices := len(h.clients[userID])
 	h.clientsMux.Unlock()
+	h.cluster.publishPresence(userID, devices)
+
+	log.Printf("WebSocket: Client connected: user %d, connection %s (%d device(s) online)", userID, client.id, devices)
+	return client
+}
+
+// unregister removes a single connection, keeping the user's other connections, and stops its writer
+func (h *WebSocketHandler) unregister(client *wsClient) {
+	client.close()
+	h.unsubscribeAll(client)
+	h.clientsMux.Lock()
+	connections := h.clients[client.userID]
+	delete(connections, client.id)
+	devices := len(connections)
+	if devices == 0 {
+		delete(h.clients, client.userID)
+	}
+	h.clientsMux.Unlock()
+	h.cluster.publishPresence(client.userID, devices)
+
+	log.Printf("WebSocket: Client disconnected: user %d, connection %s (%d device(s) still online)", client.userID, client.id, devices)
+}
+
+// connections returns a snapshot of the user's open connections
+func (h *WebSocketHandler) connections(userID uint) []*wsClient {
+	h.clientsMux.RLock()
+	defer h.clientsMux.RUnlock()
+
+	clients := make([]*wsClient, 0, len(h.clients[userID]))
+	for _, client := range h.clients[userID] {
+		clients = append(clients, client)
+	}
+	return clients
+}
 
-	log.Printf("WebSocket: Client disconnected: %d", userID)
+// deliver sends a message to every connection of the user, on this node and on other nodes.
+// It returns how many local connections received it.
+func (h *WebSocketHandler) deliver(userID uint, msg WebSocketMessage) int {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Error marshalling %s message: %v", msg.Type, err)
+		return 0
+	}
+	h.cluster.publishDeliver([]uint{userID}, false, data)
+	return h.sendRawToUser(userID, data)
+}
+
+// sendRawToUser writes an encoded message to every local connection of the user and returns how many received it
+func (h *WebSocketHandler) sendRawToUser(userID uint, data []byte) int {
+	delivered := 0
+	for _, client := range h.connections(userID) {
+		if err := client.writeRaw(data); err != nil {
+			log.Printf("WebSocket: Error sending to user %d on connection %s: %v", userID, client.id, err)
+			continue
+		}
+		delivered++
+	}
+	return delivered
 }
 
-// handleDirectMessage handles a direct message from one user to another
-func (h *WebSocketHandler) handleDirectMessage(senderID uint, payload interface{}) {
+// broadcastLocal writes an encoded message to every connection on this node
+func (h *WebSocketHandler) broadcastLocal(data []byte) {
+	h.clientsMux.RLock()
+	var clients []*wsClient
+	for _, connections := range h.clients {
+		for _, client := range connections {
+			clients = append(clients, client)
+		}
+	}
+	h.clientsMux.RUnlock()
+
+	for _, client := range clients {
+		if err := client.writeRaw(data); err != nil {
+			log.Printf("WebSocket: Error broadcasting to user %d on connection %s: %v", client.userID, client.id, err)
+		}
+	}
+}
+
+// localPresence returns the number of connections per user on this node
+func (h *WebSocketHandler) localPresence() map[uint]int {
+	h.clientsMux.RLock()
+	defer h.clientsMux.RUnlock()
+	presence := make(map[uint]int, len(h.clients))
+	for userID, connections := range h.clients {
+		presence[userID] = len(connections)
+	}
+	return presence
+}
+
+// OnlineDeviceCount returns the number of open connections of a user across all API nodes
+func (h *WebSocketHandler) OnlineDeviceCount(userID uint) int {
+	h.clientsMux.RLock()
+	local := len(h.clients[userID])
+	h.clientsMux.RUnlock()
+	return local + h.cluster.remoteDeviceCount(userID)
+}
+
+// GetPresence returns whether a user is online and on how many devices. Only users the caller shares a
+// conversation with or may message can be looked up, and never across a block.
+// @Summary Get user presence
+// @Description Returns whether a user currently has open WebSocket connections and how many devices are connected. Only available for users the caller shares a conversation with or may message, and not between users who blocked each other.
+// @Tags websocket
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "Presence of the user"
+// @Failure 400 {object} map[string]string "Invalid user ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Database error"
+// @Security BearerAuth
+// @Router /api/v1/presence/{user_id} [get]
+func (h *WebSocketHandler) GetPresence(c *fiber.Ctx) error {
+	viewerID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User not authenticated"})
+	}
+	userID, err := strconv.ParseUint(c.Params("user_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID format"})
+	}
+
+	// Hidden users look the same as missing ones, so presence lookups cannot probe for blocks or accounts
+	if h.messenger == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+	visible, err := h.messenger.canSeePresence(viewerID, uint(userID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error: " + err.Error()})
+	}
+	if !visible {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+
+	devices := h.OnlineDeviceCount(uint(userID))
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"user_id": uint(userID),
+		"online":  devices > 0,
+		"devices": devices,
+	})
+}
+
+// handleDirectMessage handles a direct message from one user to another. It goes through the same
+// messaging rules and rate limit as the HTTP endpoint; the sender gets a message_sent acknowledgement or an error.
+func (h *WebSocketHandler) handleDirectMessage(client *wsClient, payload interface{}) {
+	senderID := client.userID
 	// Parse payload
 	payloadMap, ok := payload.(map[string]interface{})
 	if !ok {
 		log.Printf("WebSocket: Invalid message payload format")
+		client.writeJSON(wsRequestError("message", "invalid payload"))
 		return
 	}
 
 	// Get recipient ID
 	recipientIDFloat, ok := payloadMap["recipient_id"].(float64)
-	if !ok {
+	if !ok || recipientIDFloat <= 0 {
 		log.Printf("WebSocket: Missing or invalid recipient_id in message payload")
+		client.writeJSON(wsRequestError("message", "recipient_id is required"))
 		return
 	}
 	recipientID := uint(recipientIDFloat)
 
-	// Get message content
-	content, ok := payloadMap["content"].(string)
-	if !ok || content == "" {
-		log.Printf("WebSocket: Missing or invalid content in message payload")
-		return
+	// Get message content and attachments
+	content, _ := payloadMap["content"].(string)
+	var attachmentIDs []uint
+	if list, ok := payloadMap["attachment_ids"].([]interface{}); ok {
+		for _, value := range list {
+			if id, ok := value.(float64); ok && id > 0 {
+				attachmentIDs = append(attachmentIDs, uint(id))
+			}
+		}
 	}
 
-	// Create message in database
-	message := models.Message{
-		SenderID:    senderID,
-		RecipientID: recipientID,
-		Content:     content,
-		SentAt:      time.Now(),
-		IsRead:      false,
+	if h.messenger == nil {
+		client.writeJSON(wsRequestError("message", "messaging is unavailable"))
+		return
 	}
 
-	if err := h.db.Create(&message).Error; err != nil {
-		log.Printf("WebSocket: Error creating message in database: %v", err)
+	// Store the message and notify the recipient
+	message, sender, err := h.messenger.Send(context.Background(), senderID, recipientID, content, attachmentIDs)
+	if err != nil {
+		_, errMsg := messageErrorStatus(err)
+		log.Printf("WebSocket: Message from user %d to user %d rejected: %v", senderID, recipientID, err)
+		LogUserAction(h.db, senderID, "WS_MSG_SEND_FAIL", recipientID, "User", err.Error(), nil)
+		client.writeJSON(wsRequestError("message", errMsg))
 		return
 	}
+	LogUserAction(h.db, senderID, "WS_MSG_SEND_SUCCESS", message.ID, "Message", fmt.Sprintf("Message sent to user %d", recipientID), nil)
+	client.writeJSON(WebSocketMessage{
+		Type: "message_sent",
+		Payload: map[string]interface{}{
+			"message_id":      message.ID,
+			"conversation_id": message.ConversationID,
+			"recipient_id":    recipientID,
+			"sent_at":         message.SentAt,
+		},
+	})
 
-	// Send message to recipient if online
-	h.clientsMux.RLock()
-	recipientConn, ok := h.clients[recipientID]
-	h.clientsMux.RUnlock()
-
-	if ok {
-		// Get sender details
-		var sender models.User
-		if err := h.db.Select("id, first_name, last_name, email").First(&sender, senderID).Error; err != nil {
-			log.Printf("WebSocket: Error getting sender details: %v", err)
-			return
-		}
-
-		// Send message to recipient
+	// Send message to recipient if online and they want message notifications over WebSocket
+	if conversationMuted(h.db, message.ConversationID, recipientID) ||
+		!NotificationEnabled(h.db, recipientID, models.NotificationCategoryMessages, models.NotificationChannelWebSocket) {
+		log.Printf("WebSocket: Recipient %d has muted or disabled WebSocket message notifications, message stored in database", recipientID)
+		return
+	}
+	if h.OnlineDeviceCount(recipientID) > 0 {
+		// Send message to all of the recipient's devices
 		notificationMsg := WebSocketMessage{
 			Type: "new_message",
 			Payload: map[string]interface{}{
-				"message_id": message.ID,
+				"message_id":      message.ID,
+				"conversation_id": message.ConversationID,
 				"sender": map[string]interface{}{
 					"id":         sender.ID,
 					"first_name": sender.FirstName,
 					"last_name":  sender.LastName,
 					"email":      sender.Email,
 				},
-				"content": content,
-				"sent_at": message.SentAt,
+				"content":     content,
+				"attachments": message.Attachments,
+				"sent_at":     message.SentAt,
 			},
 		}
 
-		if err := recipientConn.WriteJSON(notificationMsg); err != nil {
-			log.Printf("WebSocket: Error sending message to recipient: %v", err)
-		} else {
-			log.Printf("WebSocket: Message sent to recipient %d", recipientID)
-		}
+		delivered := h.deliver(recipientID, notificationMsg)
+		log.Printf("WebSocket: Message sent to recipient %d on %d local device(s)", recipientID, delivered)
 	} else {
 		log.Printf("WebSocket: Recipient %d is offline, message stored in database", recipientID)
 	}
 }
 
-// SendNotification sends a notification to a specific user
-func (h *WebSocketHandler) SendNotification(userID uint, notificationType string, payload interface{}) {
-	h.clientsMux.RLock()
-	conn, ok := h.clients[userID]
-	h.clientsMux.RUnlock()
+// wsRequestError builds the error reply to a client request of the given type
+func wsRequestError(request string, message string) WebSocketMessage {
+	return WebSocketMessage{Type: "error", Payload: map[string]interface{}{"message": message, "request": request}}
+}
 
-	if !ok {
-		log.Printf("WebSocket: User %d is not connected", userID)
+// SendNotification sends a notification to every connection of a specific user
+func (h *WebSocketHandler) SendNotification(userID uint, notificationType string, payload interface{}) {
+	if h.OnlineDeviceCount(userID) == 0 {
+		log.Printf("WebSocket: User %d is not connected, skipping real-time delivery", userID)
 		return
 	}
 
@@ -202,34 +402,31 @@ func (h *WebSocketHandler) SendNotification(userID uint, notificationType string
 		Payload: payload,
 	}
 
-	if err := conn.WriteJSON(notification); err != nil {
-		log.Printf("WebSocket: Error sending notification to user %d: %v", userID, err)
-	} else {
-		log.Printf("WebSocket: Notification sent to user %d", userID)
-	}
+	delivered := h.deliver(userID, notification)
+	log.Printf("WebSocket: Notification sent to user %d on %d local device(s)", userID, delivered)
 }
 
-// BroadcastNotification sends a notification to all connected users
+// BroadcastNotification sends a notification to all connected users on every node
 func (h *WebSocketHandler) BroadcastNotification(notificationType string, payload interface{}) {
 	notification := WebSocketMessage{
 		Type:    notificationType,
 		Payload: payload,
 	}
 
-	h.clientsMux.RLock()
-	for userID, conn := range h.clients {
-		if err := conn.WriteJSON(notification); err != nil {
-			log.Printf("WebSocket: Error broadcasting notification to user %d: %v", userID, err)
-		}
+	data, err := json.Marshal(notification)
+	if err != nil {
+		log.Printf("WebSocket: Error marshalling broadcast notification: %v", err)
+		return
 	}
-	h.clientsMux.RUnlock()
+	h.broadcastLocal(data)
+	h.cluster.publishDeliver(nil, true, data)
 
 	log.Printf("WebSocket: Notification broadcasted to all users")
 }
 
 // WebSocketUpgradeMiddleware is a middleware that upgrades HTTP connections to WebSocket
 // @Summary WebSocket connection upgrade
-// @Description Upgrades HTTP connection to WebSocket protocol for real-time communication
+// @Description Upgrades HTTP connection to WebSocket protocol for real-time communication. Clients send `message` and `typing` with a `recipient_id` (messages follow the same MESSAGING_RULES and rate limit as the HTTP endpoint and are acknowledged with `message_sent`; `attachment_ids` sends uploaded attachments), acknowledge received messages with `delivered` and mark them read with `read` (payload `message_id` or `message_ids`); senders receive `typing`, `delivered` and `read` events. Clients can also send `subscribe` and `unsubscribe` messages with a `topic` of `events`, `event:{id}`, `institution:{id}:applicants`, `school:{id}:reviews` or `blog:featured` to receive live updates.
 // @Tags websocket
 // @Success 101 {string} string "Switching Protocols"
 // @Failure 401 {object} map[string]string "User not authenticated"
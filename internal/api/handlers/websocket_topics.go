@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mwc_backend/internal/models"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Topics a WebSocket client can subscribe to with {"type": "subscribe", "topic": "..."}
+const (
+	EventsTopic       = "events"        // Newly published events
+	FeaturedBlogTopic = "blog:featured" // Newly featured blog posts
+
+	wsMaxSubscriptions = 50 // Topics a single connection may subscribe to
+)
+
+var (
+	errUnknownTopic      = errors.New("unknown topic")
+	errTopicForbidden    = errors.New("not allowed to subscribe to this topic")
+	errTooManyTopics     = fmt.Errorf("a connection can subscribe to at most %d topics", wsMaxSubscriptions)
+	errTopicNotAvailable = errors.New("topic not found")
+)
+
+// EventTopic carries updates of a single event
+func EventTopic(eventID uint) string {
+	return fmt.Sprintf("event:%d", eventID)
+}
+
+// InstitutionApplicantsTopic carries new applications to the jobs of an institution profile
+func InstitutionApplicantsTopic(institutionProfileID uint) string {
+	return fmt.Sprintf("institution:%d:applicants", institutionProfileID)
+}
+
+// SchoolReviewsTopic carries newly approved reviews of a school
+func SchoolReviewsTopic(schoolID uint) string {
+	return fmt.Sprintf("school:%d:reviews", schoolID)
+}
+
+// authorizeTopic checks that the user may subscribe to the topic.
+// Public feeds are open to every authenticated user; unpublished events only to their creator
+// and an institution's applicants only to the institution itself. Admins may subscribe to anything.
+func (h *WebSocketHandler) authorizeTopic(userID uint, topic string) error {
+	if topic == EventsTopic || topic == FeaturedBlogTopic {
+		return nil
+	}
+
+	parts := strings.Split(topic, ":")
+	if len(parts) < 2 {
+		return errUnknownTopic
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return errUnknownTopic
+	}
+
+	switch {
+	case len(parts) == 2 && parts[0] == "event":
+		var event models.Event
+		if err := h.db.Select("id, creator_id, is_published").First(&event, uint(id)).Error; err != nil {
+			return topicLookupError(err)
+		}
+		if event.IsPublished || event.CreatorID == userID || h.isAdmin(userID) {
+			return nil
+		}
+		return errTopicForbidden
+	case len(parts) == 3 && parts[0] == "institution" && parts[2] == "applicants":
+		var institution models.InstitutionProfile
+		if err := h.db.Select("id, user_id").First(&institution, uint(id)).Error; err != nil {
+			return topicLookupError(err)
+		}
+		if institution.UserID == userID || h.isAdmin(userID) {
+			return nil
+		}
+		return errTopicForbidden
+	case len(parts) == 3 && parts[0] == "school" && parts[2] == "reviews":
+		var count int64
+		if err := h.db.Model(&models.School{}).Where("id = ?", uint(id)).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return errTopicNotAvailable
+		}
+		return nil
+	}
+	return errUnknownTopic
+}
+
+func topicLookupError(err error) error {
+	if err == gorm.ErrRecordNotFound {
+		return errTopicNotAvailable
+	}
+	return err
+}
+
+func (h *WebSocketHandler) isAdmin(userID uint) bool {
+	var user models.User
+	return h.db.Select("id, role").First(&user, userID).Error == nil && user.Role == models.AdminRole
+}
+
+// subscribe authorizes the topic and adds it to the connection's subscriptions
+func (h *WebSocketHandler) subscribe(client *wsClient, topic string) error {
+	if err := h.authorizeTopic(client.userID, topic); err != nil {
+		return err
+	}
+
+	h.topicsMux.Lock()
+	defer h.topicsMux.Unlock()
+	if _, ok := client.topics[topic]; ok {
+		return nil
+	}
+	if len(client.topics) >= wsMaxSubscriptions {
+		return errTooManyTopics
+	}
+	client.topics[topic] = struct{}{}
+	if h.topics[topic] == nil {
+		h.topics[topic] = make(map[*wsClient]struct{})
+	}
+	h.topics[topic][client] = struct{}{}
+	return nil
+}
+
+// unsubscribe removes the topic from the connection's subscriptions
+func (h *WebSocketHandler) unsubscribe(client *wsClient, topic string) {
+	h.topicsMux.Lock()
+	defer h.topicsMux.Unlock()
+	h.removeSubscription(client, topic)
+}
+
+// unsubscribeAll removes every subscription of a closed connection
+func (h *WebSocketHandler) unsubscribeAll(client *wsClient) {
+	h.topicsMux.Lock()
+	defer h.topicsMux.Unlock()
+	for topic := range client.topics {
+		h.removeSubscription(client, topic)
+	}
+}
+
+// removeSubscription drops a single subscription. h.topicsMux must be held.
+func (h *WebSocketHandler) removeSubscription(client *wsClient, topic string) {
+	delete(client.topics, topic)
+	subscribers := h.topics[topic]
+	delete(subscribers, client)
+	if len(subscribers) == 0 {
+		delete(h.topics, topic)
+	}
+}
+
+// handleSubscription answers a subscribe or unsubscribe request of a client
+func (h *WebSocketHandler) handleSubscription(client *wsClient, msg WebSocketMessage) {
+	topic := strings.TrimSpace(msg.Topic)
+	if topic == "" {
+		client.writeJSON(wsRequestError(msg.Type, "topic is required"))
+		return
+	}
+
+	if msg.Type == "unsubscribe" {
+		h.unsubscribe(client, topic)
+		client.writeJSON(WebSocketMessage{Type: "unsubscribed", Topic: topic})
+		return
+	}
+
+	if err := h.subscribe(client, topic); err != nil {
+		if err != errUnknownTopic && err != errTopicForbidden && err != errTooManyTopics && err != errTopicNotAvailable {
+			log.Printf("WebSocket: Error authorizing topic %s for user %d: %v", topic, client.userID, err)
+			err = errors.New("failed to subscribe")
+		}
+		client.writeJSON(WebSocketMessage{Type: "error", Topic: topic, Payload: map[string]interface{}{"message": err.Error(), "request": msg.Type}})
+		return
+	}
+	client.writeJSON(WebSocketMessage{Type: "subscribed", Topic: topic})
+}
+
+// PublishToTopic sends a message to every connection subscribed to the topic, on this node and on other nodes
+func (h *WebSocketHandler) PublishToTopic(topic string, messageType string, payload interface{}) {
+	data, err := json.Marshal(WebSocketMessage{Type: messageType, Topic: topic, Payload: payload})
+	if err != nil {
+		log.Printf("WebSocket: Error marshalling %s message for topic %s: %v", messageType, topic, err)
+		return
+	}
+	delivered := h.publishLocal(topic, data)
+	h.cluster.publishTopic(topic, data)
+	log.Printf("WebSocket: %s published to topic %s, %d local subscriber(s)", messageType, topic, delivered)
+}
+
+// publishLocal writes an encoded message to the topic's subscribers on this node and returns how many received it
+func (h *WebSocketHandler) publishLocal(topic string, data []byte) int {
+	h.topicsMux.RLock()
+	subscribers := make([]*wsClient, 0, len(h.topics[topic]))
+	for client := range h.topics[topic] {
+		subscribers = append(subscribers, client)
+	}
+	h.topicsMux.RUnlock()
+
+	delivered := 0
+	for _, client := range subscribers {
+		if err := client.writeRaw(data); err != nil {
+			log.Printf("WebSocket: Error sending topic %s to user %d on connection %s: %v", topic, client.userID, client.id, err)
+			continue
+		}
+		delivered++
+	}
+	return delivered
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit returns a middleware that rejects request bodies larger than limit bytes. The app streams
+// bodies above its global BodyLimit; this reads such a body into memory once it is known to fit, so
+// handlers can use it as usual. Bodies within the global limit, or already read by an earlier BodyLimit,
+// pass through.
+func BodyLimit(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req := c.Request()
+		if !req.IsBodyStream() {
+			return c.Next()
+		}
+		if req.Header.ContentLength() > limit {
+			return bodyTooLarge(c)
+		}
+
+		// Chunked bodies have no length up front; read at most one byte more than allowed
+		body, err := io.ReadAll(io.LimitReader(req.BodyStream(), int64(limit)+1))
+		if err != nil {
+			c.Context().SetConnectionClose()
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot read request body"})
+		}
+		if len(body) > limit {
+			return bodyTooLarge(c)
+		}
+		req.SetBodyRaw(body)
+		return c.Next()
+	}
+}
+
+// bodyTooLarge rejects the request. The rest of the body is never read, so the connection is closed.
+func bodyTooLarge(c *fiber.Ctx) error {
+	c.Context().SetConnectionClose()
+	return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": "Request body too large"})
+}
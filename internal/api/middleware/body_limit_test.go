@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestBodyLimit(t *testing.T) {
+	app := fiber.New(fiber.Config{BodyLimit: 1024, StreamRequestBody: true, DisablePreParseMultipartForm: true})
+	app.Post("/upload", BodyLimit(4096))
+	app.Use(BodyLimit(1024))
+	echo := func(c *fiber.Ctx) error { return c.SendString(string(c.Body())) }
+	app.Post("/upload", echo)
+	app.Post("/json", echo)
+
+	cases := []struct {
+		path    string
+		size    int
+		chunked bool
+		want    int
+	}{
+		{"/json", 100, false, fiber.StatusOK},
+		{"/json", 1024, false, fiber.StatusOK},
+		{"/json", 1025, false, fiber.StatusRequestEntityTooLarge},
+		{"/json", 1000, true, fiber.StatusOK},
+		{"/json", 2000, true, fiber.StatusRequestEntityTooLarge},
+		{"/upload", 100, false, fiber.StatusOK},
+		{"/upload", 4096, false, fiber.StatusOK},
+		{"/upload", 4097, false, fiber.StatusRequestEntityTooLarge},
+		{"/upload", 3000, true, fiber.StatusOK},
+		{"/upload", 5000, true, fiber.StatusRequestEntityTooLarge},
+	}
+	for _, tc := range cases {
+		body := bytes.Repeat([]byte("a"), tc.size)
+		req := httptest.NewRequest("POST", tc.path, bytes.NewReader(body))
+		if tc.chunked {
+			req.ContentLength, req.TransferEncoding = -1, []string{"chunked"}
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("POST %s with %d bytes: %v", tc.path, tc.size, err)
+		}
+		if resp.StatusCode != tc.want {
+			t.Errorf("POST %s with %d bytes: status %d, want %d", tc.path, tc.size, resp.StatusCode, tc.want)
+			continue
+		}
+		if tc.want == fiber.StatusOK {
+			got, _ := io.ReadAll(resp.Body)
+			if !bytes.Equal(got, body) {
+				t.Errorf("POST %s with %d bytes: handler saw %d bytes", tc.path, tc.size, len(got))
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
@@ -10,8 +11,10 @@ import (
 	"mwc_backend/internal/api/handlers"
 	"mwc_backend/internal/api/middleware"
 	"mwc_backend/internal/email"
+	"mwc_backend/internal/geo"
 	"mwc_backend/internal/models"
 	"mwc_backend/internal/queue"
+	"mwc_backend/internal/storage"
 )
 
 // SetupRoutes initializes all the API routes.
@@ -20,51 +23,170 @@ func SetupRoutes(
 	db *gorm.DB,
 	mqService queue.MessageQueueService,
 	emailService email.EmailService,
+	fileStorage storage.Storage,
 	cfg *config.Config,
 ) {
+	// Never send to addresses that hard bounced or complained
+	emailService = email.NewSuppressingEmailService(emailService, handlers.EmailSuppressionCheck(db))
+
 	// Create instances of handlers, passing dependencies
+	websocketHandler := handlers.NewWebSocketHandler(db, cfg, mqService)
+	notifier := handlers.NewNotifier(db, websocketHandler)
+	messenger := handlers.NewMessenger(db, cfg, mqService, notifier)
+	websocketHandler.SetMessaging(notifier, messenger)
+	geocoder, err := geo.New(geo.Config{
+		Provider:   cfg.GeocoderProvider,
+		URL:        cfg.GeocoderURL,
+		UserAgent:  cfg.GeocoderUserAgent,
+		StaticFile: cfg.GeocoderStaticFile,
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to initialize %s geocoder: %v. School addresses will not be geocoded.", cfg.GeocoderProvider, err)
+	}
+	schoolGeocoder := handlers.NewSchoolGeocoder(db, geocoder)
 	authHandler := handlers.NewAuthHandler(db, cfg, emailService, mqService) // Pass full cfg
-	adminHandler := handlers.NewAdminHandler(db, mqService)
-	institutionHandler := handlers.NewInstitutionHandler(db, mqService)
-	educatorHandler := handlers.NewEducatorHandler(db, mqService)
-	parentHandler := handlers.NewParentHandler(db, mqService, emailService)
+	importJobHandler := handlers.NewImportJobHandler(db, cfg, mqService, fileStorage, notifier)
+	adminHandler := handlers.NewAdminHandler(db, mqService, schoolGeocoder, importJobHandler)
+	schoolDuplicateHandler := handlers.NewSchoolDuplicateHandler(db)
+	institutionHandler := handlers.NewInstitutionHandler(db, mqService, notifier, schoolGeocoder)
+	recommender := handlers.NewRecommender(db, mqService)
+	recommendationHandler := handlers.NewRecommendationHandler(db, recommender)
+	educatorHandler := handlers.NewEducatorHandler(db, mqService, emailService, cfg, notifier, recommender)
+	parentHandler := handlers.NewParentHandler(db, mqService, emailService, notifier, messenger, recommender)
 	subscriptionHandler := handlers.NewSubscriptionHandler(db, cfg, mqService)
-	websocketHandler := handlers.NewWebSocketHandler(db, cfg)
-	reviewHandler := handlers.NewReviewHandler(db, mqService)
-	eventHandler := handlers.NewEventHandler(db, cfg, mqService)
-	blogHandler := handlers.NewBlogHandler(db, cfg, mqService)
+	reviewHandler := handlers.NewReviewHandler(db, mqService, emailService, cfg, notifier)
+	eventHandler := handlers.NewEventHandler(db, cfg, mqService, notifier)
+	blogHandler := handlers.NewBlogHandler(db, cfg, mqService, notifier)
+	notificationPreferenceHandler := handlers.NewNotificationPreferenceHandler(db, cfg)
+	notificationHandler := handlers.NewNotificationHandler(db, notifier)
+	conversationHandler := handlers.NewConversationHandler(db, notifier)
+	messageHandler := handlers.NewMessageHandler(db, messenger)
+	var attachmentScanner storage.Scanner = storage.NoopScanner{}
+	if cfg.ClamAVAddress != "" {
+		attachmentScanner = storage.NewClamdScanner(cfg.ClamAVAddress)
+	}
+	attachmentHandler := handlers.NewAttachmentHandler(db, cfg, fileStorage, attachmentScanner)
+	schoolClaimHandler := handlers.NewSchoolClaimHandler(db, cfg, fileStorage, attachmentScanner, notifier, emailService)
+	mediaHandler := handlers.NewMediaHandler(db, cfg, fileStorage)
+	savedSearchHandler := handlers.NewSavedSearchHandler(db, cfg, notifier, emailService, recommender)
+	blockHandler := handlers.NewBlockHandler(db)
+	messageReportHandler := handlers.NewMessageReportHandler(db, cfg)
+	digestHandler := handlers.NewDigestHandler(db, emailService, cfg)
+	emailWebhookHandler := handlers.NewEmailWebhookHandler(db, cfg)
+
+	// Scheduled tasks, driven by delayed RabbitMQ messages
+	scheduler := queue.NewScheduler(mqService, db)
+	if err := scheduler.Register("notification-digest", time.Hour, digestHandler.SendDueDigests); err != nil {
+		log.Printf("Failed to schedule notification digests: %v", err)
+	}
+	if err := scheduler.Register("event-reminders", time.Hour, digestHandler.SendEventReminders); err != nil {
+		log.Printf("Failed to schedule event reminders: %v", err)
+	}
+	if err := scheduler.Register("saved-search-alerts", time.Hour, savedSearchHandler.RunDueSavedSearches); err != nil {
+		log.Printf("Failed to schedule saved search alerts: %v", err)
+	}
+	if err := scheduler.Register("recommendations-refresh", time.Hour, recommender.RefreshStale); err != nil {
+		log.Printf("Failed to schedule recommendation refreshes: %v", err)
+	}
+	if err := scheduler.Register("attachment-cleanup", time.Hour, attachmentHandler.DeleteUnsentAttachments); err != nil {
+		log.Printf("Failed to schedule attachment cleanup: %v", err)
+	}
+	if schoolGeocoder.Enabled() {
+		if err := scheduler.Register("school-geocoding", 10*time.Minute, schoolGeocoder.GeocodePending); err != nil {
+			log.Printf("Failed to schedule school geocoding: %v", err)
+		}
+	}
+	if err := scheduler.Register("school-deduplication", 5*time.Minute, handlers.DetectPendingSchoolDuplicates(db)); err != nil {
+		log.Printf("Failed to schedule school duplicate detection: %v", err)
+	}
+
+	// Background school imports, processed from RabbitMQ
+	if err := importJobHandler.Start(); err != nil {
+		log.Printf("Failed to start processing school imports: %v", err)
+	}
+	// Background recommendation recomputation, processed from RabbitMQ
+	if err := recommender.Start(); err != nil {
+		log.Printf("Failed to start recomputing recommendations: %v", err)
+	}
+
+	// Request body limits. They are registered before all other routes so they run first: the upload routes
+	// buffer bodies up to the size of their files, every other route keeps Fiber's default 4 MB limit.
+	uploadLimit := func(megabytes int) fiber.Handler {
+		return middleware.BodyLimit((megabytes + 1) * 1024 * 1024) // Leave room for multipart overhead
+	}
+	app.Post("/api/v1/attachments", uploadLimit(cfg.AttachmentMaxSizeMB))
+	app.Post("/api/v1/institution/schools/:school_id/claims", uploadLimit(handlers.MaxSchoolClaimDocuments*cfg.AttachmentMaxSizeMB))
+	app.Put("/api/v1/institution/schools/select/:school_id", uploadLimit(handlers.MaxSchoolClaimDocuments*cfg.AttachmentMaxSizeMB))
+	app.Post("/api/v1/institution/school/media", uploadLimit(cfg.MediaMaxSizeMB))
+	app.Post("/api/v1/institution/media", uploadLimit(cfg.MediaMaxSizeMB))
+	app.Post("/api/v1/admin/schools/:id/media", uploadLimit(cfg.MediaMaxSizeMB))
+	app.Post("/api/v1/admin/schools/batch-upload", uploadLimit(cfg.ImportMaxSizeMB))
+	app.Use(middleware.BodyLimit(fiber.DefaultBodyLimit))
 
 	// Public routes
+	app.Get("/media/*", mediaHandler.ServeMedia) // Uploaded images, unless MEDIA_BASE_URL points to a CDN
 	apiV1 := app.Group("/api/v1")
 	apiV1.Post("/register", authHandler.Register)
 	apiV1.Post("/login", authHandler.Login)
 	apiV1.Get("/schools/public", handlers.GetPublicSchools(db)) // Publicly searchable schools
+	apiV1.Get("/schools/compare", handlers.CompareSchools(db)) // Side-by-side comparison of 2 to 5 schools
+	apiV1.Get("/schools/:school_id", handlers.GetPublicSchool(db)) // Redirects the IDs of merged schools
 	apiV1.Get("/jobs", institutionHandler.GetAllJobs) // Publicly searchable jobs
+	apiV1.Get("/unsubscribe", notificationPreferenceHandler.ShowUnsubscribe) // Signed link from emails
+	apiV1.Post("/unsubscribe", notificationPreferenceHandler.Unsubscribe)    // RFC 8058 one-click unsubscribe
 
 	// Auth Middleware
 	authMw := middleware.Protected(cfg.JWTSecret)
 
 	// Admin Routes
 	adminRoutes := apiV1.Group("/admin", authMw, middleware.RoleAuth(models.AdminRole))
-	adminRoutes.Post("/schools/batch-upload", adminHandler.BatchUploadSchools)
+	adminRoutes.Post("/schools/batch-upload", adminHandler.BatchUploadSchools) // Queues an import job
+	adminRoutes.Get("/import-jobs", importJobHandler.GetImportJobs)
+	adminRoutes.Get("/import-jobs/:job_id", importJobHandler.GetImportJob)
+	adminRoutes.Post("/import-jobs/:job_id/cancel", importJobHandler.CancelImportJob)
+	adminRoutes.Get("/import-jobs/:job_id/errors", importJobHandler.DownloadImportJobErrors)
 	adminRoutes.Put("/schools/:id", adminHandler.UpdateSchool)
+	adminRoutes.Put("/schools/:id/attributes", adminHandler.UpdateSchoolAttributes)
+	adminRoutes.Post("/schools/:id/media", mediaHandler.AdminUploadSchoolMedia)
+	adminRoutes.Delete("/schools/:id/media/:media_id", mediaHandler.AdminDeleteSchoolMedia)
 	adminRoutes.Get("/schools", adminHandler.GetSchoolsByCountry) // ?country_code=US
 	adminRoutes.Delete("/schools/:id", adminHandler.DeleteSchool)
+	adminRoutes.Post("/schools/:id/merge", schoolDuplicateHandler.MergeSchools)
+	adminRoutes.Get("/school-duplicates", schoolDuplicateHandler.GetSchoolDuplicates)
+	adminRoutes.Post("/school-duplicates/:pair_id/dismiss", schoolDuplicateHandler.DismissSchoolDuplicate)
+	adminRoutes.Post("/school-duplicates/:pair_id/merge", schoolDuplicateHandler.MergeSchoolDuplicate)
+	adminRoutes.Get("/school-claims", schoolClaimHandler.GetSchoolClaims)
+	adminRoutes.Get("/school-claims/:claim_id/documents/:document_id", schoolClaimHandler.DownloadSchoolClaimDocument)
+	adminRoutes.Put("/school-claims/:claim_id/review", schoolClaimHandler.ReviewSchoolClaim)
 	adminRoutes.Get("/users", adminHandler.GetAllUsers)
+	adminRoutes.Get("/users/email-suppressed", adminHandler.GetEmailSuppressedUsers)
+	adminRoutes.Delete("/users/:id/email-suppression", adminHandler.ClearEmailSuppression)
 	adminRoutes.Put("/users/:id/status", adminHandler.UpdateUserStatus) // New: Update user active status
 	adminRoutes.Put("/users/:id/role", adminHandler.UpdateUserRole)     // New: Update user role
 	adminRoutes.Delete("/users/:id", adminHandler.DeleteUser)           // New: Delete a user
 	adminRoutes.Get("/action-logs", adminHandler.GetActionLogs)
+	adminRoutes.Get("/message-reports", messageReportHandler.GetMessageReports)
+	adminRoutes.Put("/message-reports/:report_id", messageReportHandler.ReviewMessageReport)
 
 	// Institution and Training Center Routes (shared logic)
 	instTcRoutes := apiV1.Group("/institution", authMw, middleware.RoleAuth(models.InstitutionRole, models.TrainingCenterRole))
 	instTcRoutes.Post("/profile", institutionHandler.CreateOrUpdateInstitutionProfile)
 	instTcRoutes.Post("/schools", institutionHandler.CreateSchool) // If school not in admin list
-	instTcRoutes.Put("/schools/select/:school_id", institutionHandler.SelectSchool)
+	instTcRoutes.Post("/schools/:school_id/claims", schoolClaimHandler.SubmitSchoolClaim)
+	instTcRoutes.Put("/schools/select/:school_id", schoolClaimHandler.SubmitSchoolClaim) // Former direct selection, now submits a claim
+	instTcRoutes.Get("/claims", schoolClaimHandler.GetMySchoolClaims)
+	instTcRoutes.Put("/school/attributes", institutionHandler.UpdateSchoolAttributes) // Verified institutions only
+	instTcRoutes.Post("/school/media", mediaHandler.UploadSchoolMedia)                 // Verified institutions only
+	instTcRoutes.Put("/school/media/:media_id", mediaHandler.UpdateSchoolMedia)
+	instTcRoutes.Delete("/school/media/:media_id", mediaHandler.DeleteSchoolMedia)
+	instTcRoutes.Post("/media", mediaHandler.UploadInstitutionMedia)
+	instTcRoutes.Delete("/media/:media_id", mediaHandler.DeleteInstitutionMedia)
+	instTcRoutes.Delete("/claims/:claim_id", schoolClaimHandler.WithdrawSchoolClaim)
 	instTcRoutes.Post("/jobs", institutionHandler.PostJob)
 	instTcRoutes.Put("/jobs/:job_id", institutionHandler.UpdateJob)
 	instTcRoutes.Delete("/jobs/:job_id", institutionHandler.DeleteJob)
 	instTcRoutes.Get("/jobs/:job_id/applicants", institutionHandler.GetJobApplicants)
+	instTcRoutes.Put("/jobs/:job_id/applicants/:application_id/status", institutionHandler.UpdateApplicationStatus)
 	instTcRoutes.Get("/jobs", institutionHandler.GetMyJobs)
 
 	// Educator Routes
@@ -74,6 +196,7 @@ func SetupRoutes(
 	educatorRoutes.Post("/schools/save/:school_id", educatorHandler.SaveSchool)
 	educatorRoutes.Delete("/schools/save/:school_id", educatorHandler.DeleteSavedSchool)
 	educatorRoutes.Get("/schools/saved", educatorHandler.GetSavedSchools)
+	educatorRoutes.Get("/recommendations", recommendationHandler.GetEducatorRecommendations)
 	educatorRoutes.Post("/jobs/:job_id/apply", educatorHandler.ApplyForJob)
 	educatorRoutes.Get("/jobs/applied", educatorHandler.GetAppliedJobs)
 
@@ -84,6 +207,7 @@ func SetupRoutes(
 	parentRoutes.Post("/schools/save/:school_id", parentHandler.SaveSchool)
 	parentRoutes.Delete("/schools/save/:school_id", parentHandler.DeleteSavedSchool)
 	parentRoutes.Get("/schools/saved", parentHandler.GetSavedSchools)
+	parentRoutes.Get("/recommendations", recommendationHandler.GetParentRecommendations)
 	parentRoutes.Post("/messages/send/:recipient_id", parentHandler.SendMessage)
 	parentRoutes.Get("/messages", parentHandler.GetMessages)
 	parentRoutes.Post("/messages/:message_id/read", parentHandler.MarkMessageAsRead)
@@ -140,6 +264,50 @@ func SetupRoutes(
 	adminBlogRoutes.Put("/:post_id", blogHandler.UpdateBlogPost)
 	adminBlogRoutes.Delete("/:post_id", blogHandler.DeleteBlogPost)
 
+	// Saved school and job searches with new-match alerts
+	savedSearchRoutes := apiV1.Group("/searches", authMw, middleware.RoleAuth(models.ParentRole, models.EducatorRole))
+	savedSearchRoutes.Post("/", savedSearchHandler.CreateSavedSearch)
+	savedSearchRoutes.Get("/", savedSearchHandler.GetSavedSearches)
+	savedSearchRoutes.Put("/:search_id", savedSearchHandler.UpdateSavedSearch)
+	savedSearchRoutes.Delete("/:search_id", savedSearchHandler.DeleteSavedSearch)
+	savedSearchRoutes.Get("/:search_id/results", savedSearchHandler.GetSavedSearchResults)
+
+	// Notification Routes
+	notificationRoutes := apiV1.Group("/notifications", authMw)
+	notificationRoutes.Get("/", notificationHandler.GetNotifications)
+	notificationRoutes.Get("/unread-count", notificationHandler.GetUnreadCount)
+	notificationRoutes.Post("/read-all", notificationHandler.MarkAllNotificationsAsRead)
+	notificationRoutes.Post("/:notification_id/read", notificationHandler.MarkNotificationAsRead)
+	notificationRoutes.Get("/preferences", notificationPreferenceHandler.GetPreferences)
+	notificationRoutes.Put("/preferences", notificationPreferenceHandler.UpdatePreferences)
+
+	// Message Routes, for users of any role
+	apiV1.Post("/messages/send/:recipient_id", authMw, messageHandler.SendMessage)
+	apiV1.Get("/messages/search", authMw, messageHandler.SearchMessages)
+	apiV1.Post("/messages/:message_id/report", authMw, messageReportHandler.ReportMessage)
+
+	// Blocking Routes
+	apiV1.Get("/users/blocked", authMw, blockHandler.GetBlockedUsers)
+	apiV1.Post("/users/:user_id/block", authMw, blockHandler.BlockUser)
+	apiV1.Delete("/users/:user_id/block", authMw, blockHandler.UnblockUser)
+
+	// Attachment Routes. Downloads are authorized by the signed URL instead of a bearer token.
+	apiV1.Get("/attachments/:attachment_id/download", attachmentHandler.DownloadAttachment)
+	apiV1.Post("/attachments", authMw, attachmentHandler.UploadAttachment)
+	apiV1.Get("/attachments/:attachment_id/url", authMw, attachmentHandler.GetAttachmentURL)
+	apiV1.Delete("/attachments/:attachment_id", authMw, attachmentHandler.DeleteAttachment)
+
+	// Conversation Routes
+	conversationRoutes := apiV1.Group("/conversations", authMw)
+	conversationRoutes.Get("/", conversationHandler.GetConversations)
+	conversationRoutes.Get("/:conversation_id/messages", conversationHandler.GetConversationMessages)
+	conversationRoutes.Post("/:conversation_id/read", conversationHandler.MarkConversationAsRead)
+	conversationRoutes.Put("/:conversation_id/archive", conversationHandler.ArchiveConversation)
+	conversationRoutes.Put("/:conversation_id/mute", conversationHandler.MuteConversation)
+
+	// Presence Routes
+	apiV1.Get("/presence/:user_id", authMw, websocketHandler.GetPresence)
+
 	// WebSocket Routes
 	if cfg.WebSocketEnabled {
 		// Use the WebSocket middleware to upgrade HTTP connections to WebSocket
@@ -156,6 +324,12 @@ func SetupRoutes(
 	// The default CORS policy might be too open for this.
 	webhookGroup := app.Group("/webhooks") // No broad CORS middleware here by default
 	// Add specific security middleware for webhooks if needed, e.g., middleware.WebhookAuth(cfg.WebhookSecret)
-	webhookGroup.Post("/notify-unread-message", handlers.HandleUnreadMessageNotification(db, emailService))
+	webhookGroup.Post("/notify-unread-message", handlers.HandleUnreadMessageNotification(db, emailService, cfg))
 	webhookGroup.Post("/stripe", subscriptionHandler.HandleStripeWebhook)
+	// Bounce and complaint webhooks suppress addresses, so they are only exposed with a secret to check
+	if cfg.EmailWebhookSecret != "" {
+		webhookGroup.Post("/email/ses", emailWebhookHandler.HandleSES)
+		webhookGroup.Post("/email/sendgrid", emailWebhookHandler.HandleSendGrid)
+		webhookGroup.Post("/email/postmark", emailWebhookHandler.HandlePostmark)
+	}
 }
@@ -0,0 +1,239 @@
+package email
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeliveryEventType is the kind of delivery problem reported by an email provider.
+type DeliveryEventType string
+
+const (
+	HardBounce DeliveryEventType = "hard_bounce" // The address does not exist or permanently rejects mail
+	SoftBounce DeliveryEventType = "soft_bounce" // Temporary failure, e.g. a full mailbox
+	Complaint  DeliveryEventType = "complaint"   // The recipient marked the email as spam
+)
+
+// DeliveryEvent is a bounce or complaint for a single recipient, normalized across providers.
+type DeliveryEvent struct {
+	Provider   string
+	Type       DeliveryEventType
+	Email      string
+	Reason     string
+	MessageID  string
+	OccurredAt time.Time
+}
+
+// SNSEnvelope is the outer message Amazon SNS posts to HTTP subscribers.
+type SNSEnvelope struct {
+	Type             string `json:"Type"` // "SubscriptionConfirmation", "Notification" or "UnsubscribeConfirmation"
+	MessageID        string `json:"MessageId"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	SubscribeURL     string `json:"SubscribeURL"`
+	Token            string `json:"Token"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+}
+
+// sesNotification is the SES bounce/complaint notification carried in an SNS message.
+type sesNotification struct {
+	NotificationType string `json:"notificationType"` // SES notifications
+	EventType        string `json:"eventType"`        // SES event publishing
+	Bounce           *struct {
+		BounceType        string `json:"bounceType"` // "Permanent", "Transient" or "Undetermined"
+		BounceSubType     string `json:"bounceSubType"`
+		Timestamp         string `json:"timestamp"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint *struct {
+		ComplaintFeedbackType string `json:"complaintFeedbackType"`
+		Timestamp             string `json:"timestamp"`
+		ComplainedRecipients  []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+	} `json:"complaint"`
+	Mail struct {
+		MessageID string `json:"messageId"`
+	} `json:"mail"`
+}
+
+// ParseSNSEnvelope decodes the SNS message posted to the SES webhook.
+func ParseSNSEnvelope(body []byte) (*SNSEnvelope, error) {
+	var envelope SNSEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid SNS message: %w", err)
+	}
+	if envelope.Type == "" {
+		return nil, fmt.Errorf("invalid SNS message: missing Type")
+	}
+	return &envelope, nil
+}
+
+// ParseSESNotification extracts bounces and complaints from the Message of an SNS notification.
+// Other SES notifications (e.g. deliveries) yield no events.
+func ParseSESNotification(message string) ([]DeliveryEvent, error) {
+	var notification sesNotification
+	if err := json.Unmarshal([]byte(message), &notification); err != nil {
+		return nil, fmt.Errorf("invalid SES notification: %w", err)
+	}
+
+	notificationType := notification.NotificationType
+	if notificationType == "" {
+		notificationType = notification.EventType
+	}
+
+	var events []DeliveryEvent
+	switch notificationType {
+	case "Bounce":
+		if notification.Bounce == nil {
+			return nil, fmt.Errorf("invalid SES notification: bounce details missing")
+		}
+		eventType := SoftBounce
+		if notification.Bounce.BounceType == "Permanent" {
+			eventType = HardBounce
+		}
+		occurredAt := parseTimestamp(notification.Bounce.Timestamp)
+		for _, recipient := range notification.Bounce.BouncedRecipients {
+			reason := recipient.DiagnosticCode
+			if reason == "" {
+				reason = fmt.Sprintf("%s/%s", notification.Bounce.BounceType, notification.Bounce.BounceSubType)
+			}
+			events = append(events, DeliveryEvent{
+				Provider:   "ses",
+				Type:       eventType,
+				Email:      recipient.EmailAddress,
+				Reason:     reason,
+				MessageID:  notification.Mail.MessageID,
+				OccurredAt: occurredAt,
+			})
+		}
+	case "Complaint":
+		if notification.Complaint == nil {
+			return nil, fmt.Errorf("invalid SES notification: complaint details missing")
+		}
+		occurredAt := parseTimestamp(notification.Complaint.Timestamp)
+		for _, recipient := range notification.Complaint.ComplainedRecipients {
+			events = append(events, DeliveryEvent{
+				Provider:   "ses",
+				Type:       Complaint,
+				Email:      recipient.EmailAddress,
+				Reason:     notification.Complaint.ComplaintFeedbackType,
+				MessageID:  notification.Mail.MessageID,
+				OccurredAt: occurredAt,
+			})
+		}
+	}
+	return events, nil
+}
+
+// sendGridEvent is a single entry of a SendGrid Event Webhook batch.
+type sendGridEvent struct {
+	Email       string `json:"email"`
+	Event       string `json:"event"` // e.g. "bounce", "dropped", "spamreport", "delivered"
+	Type        string `json:"type"`  // For "bounce" events: "bounce" (hard) or "blocked" (soft)
+	Reason      string `json:"reason"`
+	Status      string `json:"status"`
+	Timestamp   int64  `json:"timestamp"`
+	SGMessageID string `json:"sg_message_id"`
+}
+
+// ParseSendGridEvents extracts bounces and complaints from a SendGrid Event Webhook batch.
+func ParseSendGridEvents(body []byte) ([]DeliveryEvent, error) {
+	var batch []sendGridEvent
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, fmt.Errorf("invalid SendGrid event batch: %w", err)
+	}
+
+	var events []DeliveryEvent
+	for _, e := range batch {
+		var eventType DeliveryEventType
+		switch e.Event {
+		case "bounce":
+			eventType = HardBounce
+			if e.Type == "blocked" {
+				eventType = SoftBounce
+			}
+		case "spamreport":
+			eventType = Complaint
+		default:
+			continue
+		}
+		reason := e.Reason
+		if e.Status != "" {
+			reason = strings.TrimSpace(e.Status + " " + reason)
+		}
+		events = append(events, DeliveryEvent{
+			Provider:   "sendgrid",
+			Type:       eventType,
+			Email:      e.Email,
+			Reason:     reason,
+			MessageID:  e.SGMessageID,
+			OccurredAt: time.Unix(e.Timestamp, 0),
+		})
+	}
+	return events, nil
+}
+
+// postmarkWebhook is a Postmark bounce or spam complaint webhook payload.
+type postmarkWebhook struct {
+	RecordType  string `json:"RecordType"` // "Bounce" or "SpamComplaint"
+	Type        string `json:"Type"`       // e.g. "HardBounce", "SoftBounce", "SpamComplaint"
+	Email       string `json:"Email"`
+	Description string `json:"Description"`
+	Details     string `json:"Details"`
+	MessageID   string `json:"MessageID"`
+	BouncedAt   string `json:"BouncedAt"`
+}
+
+// ParsePostmarkWebhook extracts a bounce or complaint from a Postmark webhook payload.
+// Bounce types that do not indicate a delivery problem (e.g. auto-responders) yield no events.
+func ParsePostmarkWebhook(body []byte) ([]DeliveryEvent, error) {
+	var payload postmarkWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid Postmark webhook: %w", err)
+	}
+
+	var eventType DeliveryEventType
+	switch {
+	case payload.RecordType == "SpamComplaint" || payload.Type == "SpamComplaint":
+		eventType = Complaint
+	case payload.RecordType != "Bounce":
+		return nil, nil
+	case payload.Type == "HardBounce" || payload.Type == "BadEmailAddress" || payload.Type == "ManuallyDeactivated":
+		eventType = HardBounce
+	case payload.Type == "SoftBounce" || payload.Type == "Transient" || payload.Type == "DnsError" || payload.Type == "Blocked":
+		eventType = SoftBounce
+	default:
+		return nil, nil
+	}
+
+	reason := payload.Description
+	if payload.Details != "" {
+		reason = strings.TrimSpace(reason + " " + payload.Details)
+	}
+	return []DeliveryEvent{{
+		Provider:   "postmark",
+		Type:       eventType,
+		Email:      payload.Email,
+		Reason:     reason,
+		MessageID:  payload.MessageID,
+		OccurredAt: parseTimestamp(payload.BouncedAt),
+	}}, nil
+}
+
+// parseTimestamp parses an RFC 3339 provider timestamp, falling back to the current time.
+func parseTimestamp(value string) time.Time {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	return time.Now()
+}
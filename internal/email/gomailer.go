@@ -11,6 +11,8 @@ import (
 // EmailService defines the interface for sending emails.
 type EmailService interface {
 	SendEmail(to, subject, htmlBody string) error
+	// SendEmailWithHeaders sends an email with additional headers (e.g., List-Unsubscribe).
+	SendEmailWithHeaders(to, subject, htmlBody string, headers map[string]string) error
 }
 
 // GoMailerService implements EmailService using gomail.
@@ -32,6 +34,11 @@ func NewGoMailerService(host string, port int, username, password, from string)
 
 // SendEmail sends an email.
 func (s *GoMailerService) SendEmail(to, subject, htmlBody string) error {
+	return s.SendEmailWithHeaders(to, subject, htmlBody, nil)
+}
+
+// SendEmailWithHeaders sends an email with additional headers.
+func (s *GoMailerService) SendEmailWithHeaders(to, subject, htmlBody string, headers map[string]string) error {
 	// Dialer and fromAddr are checked in NewGoMailerService implicitly
 	// by returning noopEmailService if not configured.
 
@@ -39,6 +46,9 @@ func (s *GoMailerService) SendEmail(to, subject, htmlBody string) error {
 	m.SetHeader("From", s.fromAddr)
 	m.SetHeader("To", to)
 	m.SetHeader("Subject", subject)
+	for name, value := range headers {
+		m.SetHeader(name, value)
+	}
 	m.SetBody("text/html", htmlBody)
 	// m.AddAlternative("text/plain", "Plain text version of the email...") // Good practice
 
@@ -56,3 +66,7 @@ func (s *noopEmailService) SendEmail(to, subject, htmlBody string) error {
 	log.Printf("Email service is not configured. Would have sent email to %s with subject '%s'", to, subject)
 	return nil // Do not error, just log
 }
+
+func (s *noopEmailService) SendEmailWithHeaders(to, subject, htmlBody string, headers map[string]string) error {
+	return s.SendEmail(to, subject, htmlBody)
+}
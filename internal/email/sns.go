@@ -0,0 +1,157 @@
+package email
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// snsHostPattern matches the hosts SNS signing certificates and subscription URLs are served from
+var snsHostPattern = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// ValidSNSURL reports whether a URL from an SNS message points to an SNS endpoint over HTTPS.
+func ValidSNSURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && parsed.Scheme == "https" && parsed.User == nil && snsHostPattern.MatchString(parsed.Hostname())
+}
+
+// SNSVerifier checks the signatures of SNS messages. Signing certificates are downloaded from SNS
+// and cached by URL.
+type SNSVerifier struct {
+	fetch func(certURL string) ([]byte, error)
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// NewSNSVerifier creates an SNSVerifier downloading certificates over HTTPS.
+func NewSNSVerifier() *SNSVerifier {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return &SNSVerifier{
+		fetch: func(certURL string) ([]byte, error) {
+			resp, err := client.Get(certURL)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("signing certificate returned status %d", resp.StatusCode)
+			}
+			return io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		},
+		certs: make(map[string]*x509.Certificate),
+	}
+}
+
+// Verify checks that the message was signed by SNS with the certificate it refers to.
+func (v *SNSVerifier) Verify(envelope *SNSEnvelope) error {
+	var hash crypto.Hash
+	switch envelope.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("unsupported SNS signature version %q", envelope.SignatureVersion)
+	}
+	if !ValidSNSURL(envelope.SigningCertURL) || !strings.HasSuffix(envelope.SigningCertURL, ".pem") {
+		return fmt.Errorf("unexpected SNS signing certificate URL %q", envelope.SigningCertURL)
+	}
+	signature, err := base64.StdEncoding.DecodeString(envelope.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid SNS signature: %w", err)
+	}
+	cert, err := v.certificate(envelope.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("SNS signing certificate does not hold an RSA key")
+	}
+
+	canonical := []byte(envelope.stringToSign())
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum(canonical)
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256(canonical)
+		digest = sum[:]
+	}
+	if err := rsa.VerifyPKCS1v15(key, hash, digest, signature); err != nil {
+		return errors.New("invalid SNS signature")
+	}
+	return nil
+}
+
+// certificate returns the signing certificate at the URL, downloading it on first use
+func (v *SNSVerifier) certificate(certURL string) (*x509.Certificate, error) {
+	v.mu.Lock()
+	cert, ok := v.certs[certURL]
+	v.mu.Unlock()
+	if ok {
+		return cert, nil
+	}
+
+	data, err := v.fetch(certURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download SNS signing certificate: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid SNS signing certificate")
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SNS signing certificate: %w", err)
+	}
+	if time.Now().After(cert.NotAfter) {
+		return nil, errors.New("SNS signing certificate has expired")
+	}
+
+	v.mu.Lock()
+	v.certs[certURL] = cert
+	v.mu.Unlock()
+	return cert, nil
+}
+
+// stringToSign builds the canonical form of the message SNS signs: the signed fields in alphabetical
+// order, each name and value followed by a newline. Subject is only signed on notifications that have one.
+func (e *SNSEnvelope) stringToSign() string {
+	var fields [][2]string
+	if e.Type == "Notification" {
+		fields = append(fields, [2]string{"Message", e.Message}, [2]string{"MessageId", e.MessageID})
+		if e.Subject != "" {
+			fields = append(fields, [2]string{"Subject", e.Subject})
+		}
+		fields = append(fields, [2]string{"Timestamp", e.Timestamp}, [2]string{"TopicArn", e.TopicArn}, [2]string{"Type", e.Type})
+	} else {
+		fields = append(fields,
+			[2]string{"Message", e.Message},
+			[2]string{"MessageId", e.MessageID},
+			[2]string{"SubscribeURL", e.SubscribeURL},
+			[2]string{"Timestamp", e.Timestamp},
+			[2]string{"Token", e.Token},
+			[2]string{"TopicArn", e.TopicArn},
+			[2]string{"Type", e.Type},
+		)
+	}
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(field[0] + "\n" + field[1] + "\n")
+	}
+	return b.String()
+}
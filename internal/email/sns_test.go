@@ -0,0 +1,125 @@
+package email
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+const testCertURL = "https://sns.eu-west-1.amazonaws.com/SimpleNotificationService-test.pem"
+
+// testSNSVerifier returns a verifier serving a self-signed certificate from testCertURL, and its key
+func testSNSVerifier(t *testing.T) (*SNSVerifier, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	verifier := NewSNSVerifier()
+	verifier.fetch = func(certURL string) ([]byte, error) {
+		if certURL != testCertURL {
+			t.Fatalf("unexpected certificate download from %s", certURL)
+		}
+		return certPEM, nil
+	}
+	return verifier, key
+}
+
+// signSNS signs the envelope like SNS does
+func signSNS(t *testing.T, key *rsa.PrivateKey, envelope *SNSEnvelope) {
+	t.Helper()
+	envelope.SigningCertURL = testCertURL
+	canonical := []byte(envelope.stringToSign())
+	var signature []byte
+	var err error
+	if envelope.SignatureVersion == "1" {
+		sum := sha1.Sum(canonical)
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, sum[:])
+	} else {
+		sum := sha256.Sum256(canonical)
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope.Signature = base64.StdEncoding.EncodeToString(signature)
+}
+
+func TestSNSVerifier(t *testing.T) {
+	verifier, key := testSNSVerifier(t)
+
+	for _, version := range []string{"1", "2"} {
+		notification := &SNSEnvelope{
+			Type:             "Notification",
+			MessageID:        "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324",
+			TopicArn:         "arn:aws:sns:eu-west-1:123456789012:ses-bounces",
+			Message:          `{"notificationType":"Bounce"}`,
+			Timestamp:        "2026-10-18T12:00:00.000Z",
+			SignatureVersion: version,
+		}
+		signSNS(t, key, notification)
+		if err := verifier.Verify(notification); err != nil {
+			t.Fatalf("version %s: valid notification rejected: %v", version, err)
+		}
+
+		tampered := *notification
+		tampered.Message = `{"notificationType":"Complaint"}`
+		if err := verifier.Verify(&tampered); err == nil {
+			t.Fatalf("version %s: tampered notification accepted", version)
+		}
+	}
+
+	confirmation := &SNSEnvelope{
+		Type:             "SubscriptionConfirmation",
+		MessageID:        "165545c9-2a5c-472c-8df2-7ff2be2b3b1b",
+		TopicArn:         "arn:aws:sns:eu-west-1:123456789012:ses-bounces",
+		Message:          "You have chosen to subscribe to the topic.",
+		SubscribeURL:     "https://sns.eu-west-1.amazonaws.com/?Action=ConfirmSubscription&Token=abc",
+		Token:            "abc",
+		Timestamp:        "2026-10-18T12:00:00.000Z",
+		SignatureVersion: "1",
+	}
+	signSNS(t, key, confirmation)
+	if err := verifier.Verify(confirmation); err != nil {
+		t.Fatalf("valid subscription confirmation rejected: %v", err)
+	}
+	redirected := *confirmation
+	redirected.SubscribeURL = "https://sns.eu-west-1.amazonaws.com.attacker.example/confirm"
+	if err := verifier.Verify(&redirected); err == nil {
+		t.Fatal("subscription confirmation with a changed SubscribeURL accepted")
+	}
+
+	for _, certURL := range []string{
+		"http://sns.eu-west-1.amazonaws.com/cert.pem",
+		"https://sns.eu-west-1.amazonaws.com.attacker.example/cert.pem",
+		"https://attacker.example/cert.pem",
+		"https://sns.eu-west-1.amazonaws.com/cert.txt",
+	} {
+		forged := *confirmation
+		forged.SigningCertURL = certURL
+		if err := verifier.Verify(&forged); err == nil {
+			t.Fatalf("certificate URL %s accepted", certURL)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ErrRecipientSuppressed is returned when an email is not sent because the address bounced or complained.
+var ErrRecipientSuppressed = errors.New("recipient address is suppressed")
+
+// SuppressionCheck reports whether sending to the address is suppressed.
+type SuppressionCheck func(address string) (bool, error)
+
+// suppressingEmailService skips sends to suppressed addresses before handing the email to the wrapped service.
+type suppressingEmailService struct {
+	next       EmailService
+	suppressed SuppressionCheck
+}
+
+// NewSuppressingEmailService wraps an EmailService so that emails to suppressed addresses return ErrRecipientSuppressed.
+func NewSuppressingEmailService(next EmailService, suppressed SuppressionCheck) EmailService {
+	return &suppressingEmailService{next: next, suppressed: suppressed}
+}
+
+// SendEmail sends an email unless the recipient is suppressed.
+func (s *suppressingEmailService) SendEmail(to, subject, htmlBody string) error {
+	return s.SendEmailWithHeaders(to, subject, htmlBody, nil)
+}
+
+// SendEmailWithHeaders sends an email with additional headers unless the recipient is suppressed.
+func (s *suppressingEmailService) SendEmailWithHeaders(to, subject, htmlBody string, headers map[string]string) error {
+	suppressed, err := s.suppressed(to)
+	if err != nil {
+		// Fail open: a lookup error should not stop transactional email.
+		log.Printf("Warning: could not check email suppression for %s: %v", to, err)
+	}
+	if suppressed {
+		log.Printf("Not sending '%s' to %s: address is suppressed", subject, to)
+		return fmt.Errorf("could not send email to %s: %w", to, ErrRecipientSuppressed)
+	}
+	return s.next.SendEmailWithHeaders(to, subject, htmlBody, headers)
+}
@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+)
+
+// EarthRadiusKm is the radius used for distances. It matches Postgres earthdistance, so distances computed
+// here agree with radius filters run in the database.
+const EarthRadiusKm = 6378.168
+
+// ErrNoResult is returned when an address cannot be located.
+var ErrNoResult = errors.New("address not found")
+
+// Point is a WGS84 coordinate.
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// Valid reports whether the point is within latitude and longitude ranges.
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+// Address is a postal address to geocode.
+type Address struct {
+	Street      string
+	City        string
+	State       string
+	PostalCode  string
+	CountryCode string
+}
+
+// Empty reports whether the address has nothing to locate beyond the country.
+func (a Address) Empty() bool {
+	return strings.TrimSpace(a.Street+a.City+a.State+a.PostalCode) == ""
+}
+
+// String formats the address on one line.
+func (a Address) String() string {
+	var parts []string
+	for _, part := range []string{a.Street, a.City, a.State, a.PostalCode, a.CountryCode} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Geocoder finds the coordinates of an address.
+type Geocoder interface {
+	// Geocode returns the coordinates of the address, or ErrNoResult if it cannot be found.
+	Geocode(ctx context.Context, address Address) (Point, error)
+}
+
+// Config selects and configures a geocoder.
+type Config struct {
+	Provider   string // "none", "nominatim" or "static"
+	URL        string // Base URL of the Nominatim API
+	UserAgent  string // Sent to Nominatim, which requires an identifying user agent
+	StaticFile string // CSV file of the static geocoder
+}
+
+// New creates the configured geocoder.
+func New(cfg Config) (Geocoder, error) {
+	switch cfg.Provider {
+	case "", "none":
+		return NoopGeocoder{}, nil
+	case "nominatim":
+		return NewNominatimGeocoder(cfg.URL, cfg.UserAgent), nil
+	case "static":
+		return LoadStaticGeocoder(cfg.StaticFile)
+	}
+	return nil, errors.New("unknown geocoder provider: " + cfg.Provider)
+}
+
+// NoopGeocoder never finds an address. It is used when geocoding is disabled.
+type NoopGeocoder struct{}
+
+// Geocode always returns ErrNoResult.
+func (NoopGeocoder) Geocode(context.Context, Address) (Point, error) {
+	return Point{}, ErrNoResult
+}
+
+// Distance returns the great-circle distance between two points in kilometres.
+func Distance(a, b Point) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
@@ -0,0 +1,134 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	degree := EarthRadiusKm * math.Pi / 180
+	cases := []struct {
+		name string
+		a, b Point
+		want float64
+	}{
+		{"same point", Point{Lat: 41.9, Lng: 12.5}, Point{Lat: 41.9, Lng: 12.5}, 0},
+		{"one degree along the equator", Point{Lat: 0, Lng: 0}, Point{Lat: 0, Lng: 1}, degree},
+		{"one degree along a meridian", Point{Lat: 10, Lng: 30}, Point{Lat: 11, Lng: 30}, degree},
+		{"across the antimeridian", Point{Lat: 0, Lng: 179}, Point{Lat: 0, Lng: -179}, 2 * degree},
+		{"equator to pole", Point{Lat: 0, Lng: 45}, Point{Lat: 90, Lng: 0}, 90 * degree},
+		{"antipodes", Point{Lat: 0, Lng: 0}, Point{Lat: 0, Lng: 180}, 180 * degree},
+	}
+	for _, tc := range cases {
+		got := Distance(tc.a, tc.b)
+		if math.Abs(got-tc.want) > 1e-6 {
+			t.Errorf("%s: Distance = %v, want %v", tc.name, got, tc.want)
+		}
+		if reversed := Distance(tc.b, tc.a); math.Abs(got-reversed) > 1e-9 {
+			t.Errorf("%s: Distance = %v but reversed is %v", tc.name, got, reversed)
+		}
+	}
+
+	// Rome to Milan is about 477 km
+	if got := Distance(Point{Lat: 41.9028, Lng: 12.4964}, Point{Lat: 45.4642, Lng: 9.19}); got < 470 || got > 485 {
+		t.Errorf("Distance(Rome, Milan) = %v, want about 477", got)
+	}
+}
+
+func TestPointValid(t *testing.T) {
+	cases := []struct {
+		point Point
+		want  bool
+	}{
+		{Point{Lat: 0, Lng: 0}, true},
+		{Point{Lat: 90, Lng: 180}, true},
+		{Point{Lat: -90, Lng: -180}, true},
+		{Point{Lat: 90.1, Lng: 0}, false},
+		{Point{Lat: 0, Lng: -180.1}, false},
+		{Point{Lat: math.NaN(), Lng: 0}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.point.Valid(); got != tc.want {
+			t.Errorf("%+v.Valid() = %v, want %v", tc.point, got, tc.want)
+		}
+	}
+}
+
+func TestStaticGeocoder(t *testing.T) {
+	postal := Point{Lat: 41.89, Lng: 12.49}
+	cityState := Point{Lat: 41.9, Lng: 12.5}
+	city := Point{Lat: 41.8, Lng: 12.4}
+	springfield := Point{Lat: 39.8, Lng: -89.65}
+	g := NewStaticGeocoder([]StaticEntry{
+		{CountryCode: "IT", PostalCode: "00184", Point: postal},
+		{CountryCode: "IT", State: "Lazio", City: "Rome", Point: cityState},
+		{CountryCode: "IT", City: "Rome", Point: city},
+		{CountryCode: "US", State: "IL", City: "Springfield", Point: springfield},
+	})
+
+	cases := []struct {
+		name    string
+		address Address
+		want    Point
+		wantErr bool
+	}{
+		{"postal code first", Address{City: "Rome", State: "Lazio", PostalCode: "00184", CountryCode: "IT"}, postal, false},
+		{"city and state without a known postal code", Address{City: "Rome", State: "Lazio", PostalCode: "00100", CountryCode: "IT"}, cityState, false},
+		{"city alone", Address{City: "Rome", State: "Roma", CountryCode: "IT"}, city, false},
+		{"case and spacing are ignored", Address{City: "  ROME ", State: "lazio", CountryCode: "it"}, cityState, false},
+		{"only within the country", Address{City: "Rome", CountryCode: "US"}, Point{}, true},
+		{"city and state do not fall back to another state", Address{City: "Springfield", State: "MA", CountryCode: "US"}, Point{}, true},
+		{"empty address", Address{CountryCode: "IT"}, Point{}, true},
+	}
+	for _, tc := range cases {
+		got, err := g.Geocode(context.Background(), tc.address)
+		if tc.wantErr {
+			if !errors.Is(err, ErrNoResult) {
+				t.Errorf("%s: Geocode error = %v, want ErrNoResult", tc.name, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("%s: Geocode = %v, %v, want %v", tc.name, got, err, tc.want)
+		}
+	}
+}
+
+func TestLoadStaticGeocoder(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	g, err := LoadStaticGeocoder(write("places.csv", "country_code,state,city,postal_code,latitude,longitude\nIT,Lazio,Rome,,41.9,12.5\nIT,,,20121,45.47,9.19\n"))
+	if err != nil {
+		t.Fatalf("LoadStaticGeocoder: %v", err)
+	}
+	if got, err := g.Geocode(context.Background(), Address{City: "Rome", State: "Lazio", CountryCode: "IT"}); err != nil || got != (Point{Lat: 41.9, Lng: 12.5}) {
+		t.Errorf("Geocode(Rome) = %v, %v", got, err)
+	}
+	if got, err := g.Geocode(context.Background(), Address{PostalCode: "20121", CountryCode: "IT"}); err != nil || got != (Point{Lat: 45.47, Lng: 9.19}) {
+		t.Errorf("Geocode(20121) = %v, %v", got, err)
+	}
+
+	for name, content := range map[string]string{
+		"invalid.csv": "IT,Lazio,Rome,,41.9,12.5\nIT,Lazio,Latina,,north,east\n",
+		"range.csv":   "IT,Lazio,Rome,,91,12.5\n",
+		"columns.csv": "IT,Lazio,Rome,41.9,12.5\n",
+	} {
+		if _, err := LoadStaticGeocoder(write(name, content)); err == nil {
+			t.Errorf("LoadStaticGeocoder(%s) succeeded, want an error", name)
+		}
+	}
+	if _, err := LoadStaticGeocoder(""); err == nil {
+		t.Error("LoadStaticGeocoder without a file succeeded, want an error")
+	}
+}
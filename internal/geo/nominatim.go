@@ -0,0 +1,122 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// nominatimInterval is the minimum time between requests. The public Nominatim service allows one per second.
+const nominatimInterval = time.Second
+
+// NominatimGeocoder geocodes with the OpenStreetMap Nominatim search API.
+type NominatimGeocoder struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewNominatimGeocoder creates a geocoder for the Nominatim instance at baseURL.
+func NewNominatimGeocoder(baseURL, userAgent string) *NominatimGeocoder {
+	if baseURL == "" {
+		baseURL = "https://nominatim.openstreetmap.org"
+	}
+	return &NominatimGeocoder{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type nominatimResult struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+// Geocode looks up the address with a structured Nominatim query.
+func (g *NominatimGeocoder) Geocode(ctx context.Context, address Address) (Point, error) {
+	if address.Empty() {
+		return Point{}, ErrNoResult
+	}
+	if err := g.wait(ctx); err != nil {
+		return Point{}, err
+	}
+
+	query := url.Values{}
+	query.Set("format", "jsonv2")
+	query.Set("limit", "1")
+	setIfNotEmpty(query, "street", address.Street)
+	setIfNotEmpty(query, "city", address.City)
+	setIfNotEmpty(query, "state", address.State)
+	setIfNotEmpty(query, "postalcode", address.PostalCode)
+	setIfNotEmpty(query, "countrycodes", strings.ToLower(address.CountryCode))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/search?"+query.Encode(), nil)
+	if err != nil {
+		return Point{}, err
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return Point{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Point{}, fmt.Errorf("nominatim returned status %d", resp.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return Point{}, fmt.Errorf("decoding nominatim response: %w", err)
+	}
+	if len(results) == 0 {
+		return Point{}, ErrNoResult
+	}
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid latitude in nominatim response: %w", err)
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid longitude in nominatim response: %w", err)
+	}
+	return Point{Lat: lat, Lng: lng}, nil
+}
+
+// wait blocks until the next request is allowed by the rate limit.
+func (g *NominatimGeocoder) wait(ctx context.Context) error {
+	g.mu.Lock()
+	next := g.last.Add(nominatimInterval)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	g.last = next
+	g.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func setIfNotEmpty(values url.Values, key, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		values.Set(key, value)
+	}
+}
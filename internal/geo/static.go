@@ -0,0 +1,106 @@
+package geo
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// StaticGeocoder resolves addresses from a fixed table without network access. It is meant for tests,
+// development and offline deployments. Entries are matched by postal code, then by city and state, then by city,
+// always within the address's country.
+type StaticGeocoder struct {
+	entries map[string]Point
+}
+
+// StaticEntry is one row of a static geocoder table. Empty fields match any value.
+type StaticEntry struct {
+	CountryCode string
+	State       string
+	City        string
+	PostalCode  string
+	Point       Point
+}
+
+// NewStaticGeocoder creates a static geocoder from entries.
+func NewStaticGeocoder(entries []StaticEntry) *StaticGeocoder {
+	g := &StaticGeocoder{entries: make(map[string]Point, len(entries))}
+	for _, entry := range entries {
+		g.Add(entry)
+	}
+	return g
+}
+
+// LoadStaticGeocoder reads a CSV file with the columns country_code,state,city,postal_code,latitude,longitude.
+// A header row is skipped.
+func LoadStaticGeocoder(path string) (*StaticGeocoder, error) {
+	if path == "" {
+		return nil, fmt.Errorf("static geocoder requires a CSV file")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 6
+	var entries []StaticEntry
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(record[4]), 64)
+		lng, lngErr := strconv.ParseFloat(strings.TrimSpace(record[5]), 64)
+		if latErr != nil || lngErr != nil {
+			if line == 1 {
+				continue // Header
+			}
+			return nil, fmt.Errorf("%s:%d: invalid coordinates", path, line)
+		}
+		entry := StaticEntry{CountryCode: record[0], State: record[1], City: record[2], PostalCode: record[3], Point: Point{Lat: lat, Lng: lng}}
+		if !entry.Point.Valid() {
+			return nil, fmt.Errorf("%s:%d: coordinates out of range", path, line)
+		}
+		entries = append(entries, entry)
+	}
+	return NewStaticGeocoder(entries), nil
+}
+
+// Add adds or replaces an entry.
+func (g *StaticGeocoder) Add(entry StaticEntry) {
+	g.entries[staticKey(entry.CountryCode, entry.State, entry.City, entry.PostalCode)] = entry.Point
+}
+
+// Geocode returns the most specific matching entry.
+func (g *StaticGeocoder) Geocode(_ context.Context, address Address) (Point, error) {
+	candidates := []string{
+		staticKey(address.CountryCode, "", "", address.PostalCode),
+		staticKey(address.CountryCode, address.State, address.City, ""),
+		staticKey(address.CountryCode, "", address.City, ""),
+	}
+	for i, key := range candidates {
+		if (i == 0 && strings.TrimSpace(address.PostalCode) == "") || (i > 0 && strings.TrimSpace(address.City) == "") {
+			continue
+		}
+		if point, ok := g.entries[key]; ok {
+			return point, nil
+		}
+	}
+	return Point{}, ErrNoResult
+}
+
+func staticKey(countryCode, state, city, postalCode string) string {
+	normalize := func(value string) string {
+		return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	}
+	return normalize(countryCode) + "|" + normalize(state) + "|" + normalize(city) + "|" + normalize(postalCode)
+}
@@ -0,0 +1,319 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// exifSegment builds an APP1 segment holding an EXIF IFD with the orientation tag
+func exifSegment(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8) // First IFD
+	order.PutUint16(tiff[8:], 1) // One entry
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3) // SHORT
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// withSegment inserts a segment right after the start of image marker of a JPEG
+func withSegment(jpegData, segment []byte) []byte {
+	data := append([]byte{}, jpegData[:2]...)
+	data = append(data, segment...)
+	return append(data, jpegData[2:]...)
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// numbered returns an image whose pixels all differ, so every pixel can be traced through a transform
+func numbered(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(10*y + x), A: 255})
+		}
+	}
+	return img
+}
+
+// stored returns how a camera stores the upright image under an EXIF orientation
+func stored(upright *image.NRGBA, orientation int) *image.NRGBA {
+	w, h := upright.Bounds().Dx(), upright.Bounds().Dy()
+	outW, outH := w, h
+	if orientation >= 5 {
+		outW, outH = h, w
+	}
+	out := image.NewNRGBA(image.Rect(0, 0, outW, outH))
+	for y := 0; y < outH; y++ {
+		for x := 0; x < outW; x++ {
+			var ux, uy int
+			switch orientation {
+			case 1:
+				ux, uy = x, y
+			case 2:
+				ux, uy = w-1-x, y
+			case 3:
+				ux, uy = w-1-x, h-1-y
+			case 4:
+				ux, uy = x, h-1-y
+			case 5:
+				ux, uy = y, x
+			case 6: // Stored rotated counter-clockwise
+				ux, uy = w-1-y, x
+			case 7:
+				ux, uy = w-1-y, h-1-x
+			case 8: // Stored rotated clockwise
+				ux, uy = y, h-1-x
+			}
+			out.SetNRGBA(x, y, upright.NRGBAAt(ux, uy))
+		}
+	}
+	return out
+}
+
+func TestOrient(t *testing.T) {
+	upright := numbered(3, 2)
+	for orientation := 1; orientation <= 8; orientation++ {
+		got := orient(stored(upright, orientation), orientation)
+		if got.Bounds() != upright.Bounds() {
+			t.Errorf("orientation %d: bounds %v, want %v", orientation, got.Bounds(), upright.Bounds())
+			continue
+		}
+		if !bytes.Equal(got.Pix, upright.Pix) {
+			t.Errorf("orientation %d: pixels were not restored upright", orientation)
+		}
+	}
+	for _, orientation := range []int{0, 9, -1} {
+		if got := orient(upright, orientation); got != upright {
+			t.Errorf("orientation %d changed the image", orientation)
+		}
+	}
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	plain := encodeJPEG(t, numbered(8, 8))
+	for orientation := uint16(1); orientation <= 8; orientation++ {
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			if got := jpegOrientation(withSegment(plain, exifSegment(order, orientation))); got != int(orientation) {
+				t.Errorf("%v orientation %d: got %d", order, orientation, got)
+			}
+		}
+	}
+	if got := jpegOrientation(plain); got != 1 {
+		t.Errorf("JPEG without EXIF: got %d, want 1", got)
+	}
+	// An APP0 segment and fill bytes before the EXIF segment are skipped
+	app0 := []byte{0xFF, 0xE0, 0x00, 0x04, 0x00, 0x00, 0xFF}
+	if got := jpegOrientation(withSegment(plain, append(app0, exifSegment(binary.BigEndian, 6)...))); got != 6 {
+		t.Errorf("EXIF after APP0: got %d, want 6", got)
+	}
+}
+
+func TestJPEGOrientationMalformed(t *testing.T) {
+	plain := encodeJPEG(t, numbered(8, 8))
+	valid := withSegment(plain, exifSegment(binary.LittleEndian, 6))
+	segmentEnd := 2 + len(exifSegment(binary.LittleEndian, 6))
+
+	// Every truncation must be handled; the orientation is only read once the whole segment is present
+	for n := 0; n <= len(valid); n++ {
+		want := 1
+		if n >= segmentEnd {
+			want = 6
+		}
+		if got := jpegOrientation(valid[:n]); got != want {
+			t.Errorf("truncated to %d bytes: got %d, want %d", n, got, want)
+		}
+	}
+
+	corrupt := func(change func(segment []byte)) []byte {
+		segment := exifSegment(binary.LittleEndian, 6)
+		change(segment)
+		return withSegment(plain, segment)
+	}
+	tiff := 4 + 6 // Offset of the TIFF header in the segment
+	cases := map[string][]byte{
+		"empty":                    nil,
+		"not a JPEG":               []byte("\x89PNG\r\n\x1a\n"),
+		"only the start marker":    {0xFF, 0xD8},
+		"segment length below 2":   withSegment(plain, []byte{0xFF, 0xE1, 0x00, 0x01}),
+		"segment past the end":     {0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF, 'E', 'x'},
+		"garbage between segments": withSegment(plain, []byte{0x00, 0x01}),
+		"short EXIF header":        withSegment(plain, []byte{0xFF, 0xE1, 0x00, 0x06, 'E', 'x', 'i', 'f'}),
+		"short TIFF header":        withSegment(plain, []byte{0xFF, 0xE1, 0x00, 0x0C, 'E', 'x', 'i', 'f', 0, 0, 'I', 'I', 42, 0}),
+		"unknown byte order":       corrupt(func(s []byte) { copy(s[tiff:], "XX") }),
+		"IFD offset too small":     corrupt(func(s []byte) { binary.LittleEndian.PutUint32(s[tiff+4:], 4) }),
+		"IFD offset past the end":  corrupt(func(s []byte) { binary.LittleEndian.PutUint32(s[tiff+4:], 0xFFFFFFF0) }),
+		"too many IFD entries": corrupt(func(s []byte) {
+			binary.LittleEndian.PutUint16(s[tiff+8:], 0xFFFF)
+			binary.LittleEndian.PutUint16(s[tiff+10:], 0x0100)
+		}),
+		"no orientation tag":    corrupt(func(s []byte) { binary.LittleEndian.PutUint16(s[tiff+10:], 0x0100) }),
+		"orientation 0":         corrupt(func(s []byte) { binary.LittleEndian.PutUint16(s[tiff+18:], 0) }),
+		"orientation 9":         corrupt(func(s []byte) { binary.LittleEndian.PutUint16(s[tiff+18:], 9) }),
+		"EXIF after image data": append(append([]byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02}, exifSegment(binary.LittleEndian, 6)...), 0xFF, 0xD9),
+	}
+	for name, data := range cases {
+		if got := jpegOrientation(data); got != 1 {
+			t.Errorf("%s: got %d, want 1", name, got)
+		}
+	}
+	for _, tiff := range [][]byte{nil, []byte("II"), []byte("MM\x00\x2a\x00\x00\x00\x08\x00")} {
+		if got := exifOrientation(tiff); got != 1 {
+			t.Errorf("exifOrientation(%q) = %d, want 1", tiff, got)
+		}
+	}
+}
+
+// halves returns a stored image with a red left half and a blue right half
+func halves(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBA{R: 255, A: 255}
+			if x >= width/2 {
+				c = color.NRGBA{B: 255, A: 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDecodeAppliesOrientation(t *testing.T) {
+	plain := encodeJPEG(t, halves(32, 16))
+	red := func(c color.NRGBA) bool { return c.R > 200 && c.B < 60 }
+	cases := []struct {
+		orientation   uint16
+		width, height int
+		redAt         image.Point // Centre of the part that was the red left half
+	}{
+		{1, 32, 16, image.Pt(8, 8)},
+		{2, 32, 16, image.Pt(24, 8)},
+		{3, 32, 16, image.Pt(24, 8)},
+		{4, 32, 16, image.Pt(8, 8)},
+		{5, 16, 32, image.Pt(8, 8)},
+		{6, 16, 32, image.Pt(8, 8)},
+		{7, 16, 32, image.Pt(8, 24)},
+		{8, 16, 32, image.Pt(8, 24)},
+	}
+	for _, tc := range cases {
+		img, err := Decode(withSegment(plain, exifSegment(binary.BigEndian, tc.orientation)))
+		if err != nil {
+			t.Fatalf("orientation %d: %v", tc.orientation, err)
+		}
+		if img.Bounds().Dx() != tc.width || img.Bounds().Dy() != tc.height {
+			t.Errorf("orientation %d: %dx%d, want %dx%d", tc.orientation, img.Bounds().Dx(), img.Bounds().Dy(), tc.width, tc.height)
+			continue
+		}
+		if c := img.NRGBAAt(tc.redAt.X, tc.redAt.Y); !red(c) {
+			t.Errorf("orientation %d: pixel at %v is %v, want red", tc.orientation, tc.redAt, c)
+		}
+	}
+}
+
+// pngWithSize returns a PNG of one pixel whose header claims the given dimensions
+func pngWithSize(t *testing.T, width, height uint32) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	// The IHDR chunk follows the 8 byte signature: length, type, width, height, ..., CRC
+	binary.BigEndian.PutUint32(data[16:], width)
+	binary.BigEndian.PutUint32(data[20:], height)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestDecodeLimits(t *testing.T) {
+	if _, err := Decode(pngWithSize(t, 10_000, 10_000)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Decode of a 10000x10000 image: %v, want ErrTooLarge", err)
+	}
+	if _, err := Decode(pngWithSize(t, MaxPixels+1, 1)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Decode of a %dx1 image: %v, want ErrTooLarge", MaxPixels+1, err)
+	}
+	if _, err := Decode(pngWithSize(t, 1, 1)); err != nil {
+		t.Errorf("Decode of a 1x1 image: %v", err)
+	}
+	for _, data := range [][]byte{nil, []byte("not an image"), []byte("%PDF-1.4")} {
+		if _, err := Decode(data); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Decode(%q): %v, want ErrUnsupported", data, err)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	cases := []struct {
+		width, height, maxSize int
+		wantW, wantH           int
+	}{
+		{400, 200, 100, 100, 50},
+		{200, 400, 100, 50, 100},
+		{300, 300, 100, 100, 100},
+		{1000, 3, 100, 100, 1},
+		{3, 1000, 100, 1, 100},
+		{80, 40, 100, 80, 40},
+		{100, 100, 100, 100, 100},
+	}
+	for _, tc := range cases {
+		img := image.NewNRGBA(image.Rect(0, 0, tc.width, tc.height))
+		got := Fit(img, tc.maxSize)
+		if got.Bounds().Dx() != tc.wantW || got.Bounds().Dy() != tc.wantH {
+			t.Errorf("Fit(%dx%d, %d) = %dx%d, want %dx%d", tc.width, tc.height, tc.maxSize, got.Bounds().Dx(), got.Bounds().Dy(), tc.wantW, tc.wantH)
+		}
+		if tc.width <= tc.maxSize && tc.height <= tc.maxSize && got != img {
+			t.Errorf("Fit(%dx%d, %d) copied an image that already fits", tc.width, tc.height, tc.maxSize)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	opaque := halves(4, 4)
+	data, contentType, ext, err := Encode(opaque)
+	if err != nil || contentType != "image/jpeg" || ext != ".jpg" {
+		t.Fatalf("Encode(opaque) = %s, %s, %v", contentType, ext, err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+		t.Errorf("Encode(opaque) is not a JPEG: %v", err)
+	}
+
+	transparent := halves(4, 4)
+	transparent.SetNRGBA(1, 1, color.NRGBA{R: 255, A: 128})
+	data, contentType, ext, err = Encode(transparent)
+	if err != nil || contentType != "image/png" || ext != ".png" {
+		t.Fatalf("Encode(transparent) = %s, %s, %v", contentType, ext, err)
+	}
+	decoded, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Encode(transparent) is not a PNG: %v", err)
+	}
+	if _, _, _, a := decoded.At(1, 1).RGBA(); a>>8 != 128 {
+		t.Errorf("Encode(transparent) lost the alpha, got %d", a>>8)
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -28,6 +29,63 @@ type SubscriptionStatus string
 // ReviewStatus defines the type for review status
 type ReviewStatus string
 
+// NotificationCategory defines the type of content a notification is about
+type NotificationCategory string
+
+// NotificationChannel defines how a notification is delivered
+type NotificationChannel string
+
+// EmailEventType defines the type for email delivery events reported by providers
+type EmailEventType string
+
+// DigestFrequency defines how often a user receives notification emails
+type DigestFrequency string
+
+// NotificationType defines what happened for an in-app notification
+type NotificationType string
+
+// MessageReportReason defines why a message was reported
+type MessageReportReason string
+
+// MessageReportStatus defines the moderation state of a message report
+type MessageReportStatus string
+
+// ImportJobStatus defines the processing state of a background import
+type ImportJobStatus string
+
+// SchoolDuplicateStatus defines the review state of a pair of possibly duplicate schools
+type SchoolDuplicateStatus string
+
+// SchoolClaimStatus defines the review state of an institution's claim to manage a school
+type SchoolClaimStatus string
+
+// SchoolOwnershipStatus defines whether a school is managed by a verified institution
+type SchoolOwnershipStatus string
+
+// SchoolProgram defines a Montessori program level offered by a school
+type SchoolProgram string
+
+// SchoolSchedule defines the length of the school day offered by a school
+type SchoolSchedule string
+
+// SchoolTuitionBand defines how expensive a school is compared to schools in its area
+type SchoolTuitionBand string
+
+// SchoolAccreditation defines the Montessori organization a school is accredited by
+type SchoolAccreditation string
+
+// MediaKind defines where an image of a school or institution is shown
+type MediaKind string
+
+// SavedSearchKind defines what a saved search looks for
+type SavedSearchKind string
+
+// SavedSearchFrequency defines how often a saved search is run for new-match alerts
+type SavedSearchFrequency string
+
+// RecommendationKind defines what is recommended to a user
+type RecommendationKind string
+
 const (
 	AdminRole          UserRole = "admin"
 	InstitutionRole    UserRole = "institution"
@@ -53,6 +111,155 @@ const (
 	ReviewRejected ReviewStatus = "rejected"
 )
 
+const (
+	ReportReasonSpam          MessageReportReason = "spam"
+	ReportReasonHarassment    MessageReportReason = "harassment"
+	ReportReasonInappropriate MessageReportReason = "inappropriate"
+	ReportReasonScam          MessageReportReason = "scam"
+	ReportReasonOther         MessageReportReason = "other"
+)
+
+const (
+	MessageReportPending   MessageReportStatus = "pending"
+	MessageReportDismissed MessageReportStatus = "dismissed"
+	MessageReportActioned  MessageReportStatus = "actioned"
+)
+
+const (
+	ImportJobQueued     ImportJobStatus = "queued"
+	ImportJobProcessing ImportJobStatus = "processing"
+	ImportJobCompleted  ImportJobStatus = "completed"
+	ImportJobFailed     ImportJobStatus = "failed"
+	ImportJobCancelled  ImportJobStatus = "cancelled"
+)
+
+const (
+	SchoolDuplicatePending   SchoolDuplicateStatus = "pending"
+	SchoolDuplicateMerged    SchoolDuplicateStatus = "merged"
+	SchoolDuplicateDismissed SchoolDuplicateStatus = "dismissed" // Reviewed as different schools
+)
+
+const (
+	SchoolClaimPending   SchoolClaimStatus = "pending"
+	SchoolClaimApproved  SchoolClaimStatus = "approved"
+	SchoolClaimRejected  SchoolClaimStatus = "rejected"
+	SchoolClaimWithdrawn SchoolClaimStatus = "withdrawn" // Withdrawn by the institution before review
+)
+
+const (
+	SchoolOwnershipUnclaimed SchoolOwnershipStatus = "unclaimed"
+	SchoolOwnershipPending   SchoolOwnershipStatus = "pending"  // Claimed by at least one institution, awaiting admin review
+	SchoolOwnershipVerified  SchoolOwnershipStatus = "verified" // Managed by the institution whose claim was approved
+)
+
+const (
+	ProgramToddler    SchoolProgram = "toddler"    // 18 months to 3 years
+	ProgramPrimary    SchoolProgram = "primary"    // 3 to 6 years
+	ProgramElementary SchoolProgram = "elementary" // 6 to 12 years
+	ProgramAdolescent SchoolProgram = "adolescent" // 12 to 18 years
+)
+
+const (
+	ScheduleHalfDay SchoolSchedule = "half_day"
+	ScheduleFullDay SchoolSchedule = "full_day"
+)
+
+const (
+	TuitionFree   SchoolTuitionBand = "free"
+	TuitionLow    SchoolTuitionBand = "low"
+	TuitionMedium SchoolTuitionBand = "medium"
+	TuitionHigh   SchoolTuitionBand = "high"
+)
+
+const (
+	AccreditationAMI   SchoolAccreditation = "ami"   // Association Montessori Internationale
+	AccreditationAMS   SchoolAccreditation = "ams"   // American Montessori Society
+	AccreditationIMC   SchoolAccreditation = "imc"   // International Montessori Council
+	AccreditationMSA   SchoolAccreditation = "msa"   // Montessori Schools Association
+	AccreditationOther SchoolAccreditation = "other" // Another Montessori or national accreditation
+)
+
+const (
+	MediaLogo    MediaKind = "logo"    // One per school or institution
+	MediaCover   MediaKind = "cover"   // One per school or institution
+	MediaGallery MediaKind = "gallery" // Ordered by Position
+)
+
+const (
+	SavedSearchSchools SavedSearchKind = "schools" // Filters of the public school search
+	SavedSearchJobs    SavedSearchKind = "jobs"    // Filters of the job search
+)
+
+const (
+	SavedSearchDaily  SavedSearchFrequency = "daily"
+	SavedSearchWeekly SavedSearchFrequency = "weekly"
+	SavedSearchNever  SavedSearchFrequency = "never" // Saved for running by hand, without alerts
+)
+
+const (
+	RecommendationSchool RecommendationKind = "school" // Recommended to parents
+	RecommendationJob    RecommendationKind = "job"    // Recommended to educators
+)
+
+const (
+	NotificationCategoryMessages        NotificationCategory = "messages"
+	NotificationCategoryJobApplications NotificationCategory = "job_applications"
+	NotificationCategoryEvents          NotificationCategory = "events"
+	NotificationCategoryReviews         NotificationCategory = "reviews"
+	NotificationCategoryNewsletters     NotificationCategory = "newsletters"
+	NotificationCategorySchoolClaims    NotificationCategory = "school_claims"
+	NotificationCategorySavedSearches   NotificationCategory = "saved_searches"
+)
+
+const (
+	NotificationChannelEmail     NotificationChannel = "email"
+	NotificationChannelInApp     NotificationChannel = "in_app"
+	NotificationChannelWebSocket NotificationChannel = "websocket"
+)
+
+const (
+	DigestImmediate DigestFrequency = "immediate" // One email per notification
+	DigestHourly    DigestFrequency = "hourly"
+	DigestDaily     DigestFrequency = "daily"
+	DigestWeekly    DigestFrequency = "weekly"
+)
+
+const (
+	NotificationTypeNewMessage               NotificationType = "new_message"
+	NotificationTypeNewApplication           NotificationType = "new_application"
+	NotificationTypeApplicationStatusChanged NotificationType = "application_status_changed"
+	NotificationTypeReviewModerated          NotificationType = "review_moderated"
+	NotificationTypeNewReview                NotificationType = "new_review"
+	NotificationTypeEventUpdated             NotificationType = "event_updated"
+	NotificationTypeSchoolClaimSubmitted     NotificationType = "school_claim_submitted"
+	NotificationTypeSchoolClaimReviewed      NotificationType = "school_claim_reviewed"
+	NotificationTypeSavedSearchMatches       NotificationType = "saved_search_matches"
+)
+
+const (
+	EmailEventHardBounce EmailEventType = "hard_bounce" // Permanent failure, the address is suppressed immediately
+	EmailEventSoftBounce EmailEventType = "soft_bounce" // Temporary failure, the address is suppressed after repeated bounces
+	EmailEventComplaint  EmailEventType = "complaint"   // The recipient marked the email as spam
+)
+
+// NotificationCategories lists every category a user can set preferences for
+var NotificationCategories = []NotificationCategory{
+	NotificationCategoryMessages,
+	NotificationCategoryJobApplications,
+	NotificationCategoryEvents,
+	NotificationCategoryReviews,
+	NotificationCategoryNewsletters,
+	NotificationCategorySchoolClaims,
+	NotificationCategorySavedSearches,
+}
+
+// NotificationChannels lists every channel a user can set preferences for
+var NotificationChannels = []NotificationChannel{
+	NotificationChannelEmail,
+	NotificationChannelInApp,
+	NotificationChannelWebSocket,
+}
+
 // User represents a user in the system
 // @Description User information
 // @Schema models.User
@@ -65,6 +272,15 @@ type User struct {
 	Role         UserRole `gorm:"type:varchar(20);not null"`
 	IsActive     bool     `gorm:"default:true"`
 	LastLogin    *time.Time
+	// Notification email batching
+	DigestFrequency  DigestFrequency `gorm:"type:varchar(20);not null;default:'immediate'"`
+	LastDigestSentAt *time.Time
+	// Email deliverability, updated from provider bounce and complaint webhooks
+	EmailSuppressed        bool `gorm:"default:false;index"` // No emails are sent to suppressed addresses
+	EmailSuppressedAt      *time.Time
+	EmailSuppressionReason string
+	EmailBounceCount       int `gorm:"default:0"` // Soft bounces since the address was last cleared
+	LastEmailBounceAt      *time.Time
 
 	// Relationships (depending on role)
 	InstitutionProfile *InstitutionProfile `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"` // For Institution/TrainingCenter
@@ -89,6 +305,176 @@ type School struct {
 	UploadedByAdmin bool  `gorm:"default:false"` // True if uploaded by admin batch
 	CreatedByUserID *uint // Pointer to allow NULL if uploaded by admin initially
 	User            *User `gorm:"foreignKey:CreatedByUserID"`
+	Latitude        *float64   `gorm:"index:idx_schools_lat_lng"` // Nil until the address is geocoded
+	Longitude       *float64   `gorm:"index:idx_schools_lat_lng"`
+	GeocodedAt      *time.Time // Last geocoding attempt, set even if the address was not found
+	DuplicatesCheckedAt *time.Time `gorm:"index"` // Last duplicate detection run, cleared when the name, address or website changes
+	OwnershipStatus SchoolOwnershipStatus `gorm:"type:varchar(20);not null;default:'unclaimed';index"` // Shown as a verified badge once a claim is approved
+	// Montessori profile, edited by the verified institution. Lists are stored as JSON arrays.
+	Programs       []SchoolProgram       `gorm:"type:jsonb;serializer:json;default:'[]'"`
+	AgeMinMonths   *int                  // Youngest accepted age in months
+	AgeMaxMonths   *int                  // Oldest accepted age in months
+	Languages      []string              `gorm:"type:jsonb;serializer:json;default:'[]'"` // ISO 639-1 codes of the languages of instruction
+	TuitionBand    SchoolTuitionBand     `gorm:"type:varchar(20);index"`                   // Empty if unknown
+	Schedules      []SchoolSchedule      `gorm:"type:jsonb;serializer:json;default:'[]'"`
+	Accreditations []SchoolAccreditation `gorm:"type:jsonb;serializer:json;default:'[]'"`
+	Media          []Media               `gorm:"foreignKey:SchoolID"` // Logo, cover and gallery; search results include only the logo and cover
+	DistanceKm      *float64   `gorm:"-" json:",omitempty"` // Distance from the searched location, only set by location searches
+}
+
+// SchoolDuplicate is a pair of schools that may be the same school, queued for admin review.
+// SchoolID is always the lower ID of the pair.
+// @Description Possible duplicate school pair
+// @Schema models.SchoolDuplicate
+type SchoolDuplicate struct {
+	GormModel
+	SchoolID          uint                  `gorm:"not null;uniqueIndex:idx_school_duplicate_pair"`
+	School            School                `gorm:"foreignKey:SchoolID"`
+	DuplicateID       uint                  `gorm:"not null;uniqueIndex:idx_school_duplicate_pair;index"`
+	Duplicate         School                `gorm:"foreignKey:DuplicateID"`
+	Score             float64               `gorm:"not null;index"` // Overall similarity, 0 to 1
+	NameSimilarity    float64               // Trigram similarity of the normalized names
+	AddressSimilarity *float64              // Trigram similarity of the normalized addresses, nil if either has none
+	SameWebsite       bool                  // Both websites have the same host
+	Status            SchoolDuplicateStatus `gorm:"type:varchar(20);not null;default:'pending';index"`
+	ReviewedBy        *uint                 // Admin who merged or dismissed the pair
+	ReviewedAt        *time.Time
+}
+
+// SchoolRedirect points the ID of a merged school to the school it was merged into
+// @Description Redirect from a merged school
+// @Schema models.SchoolRedirect
+type SchoolRedirect struct {
+	FromSchoolID uint `gorm:"primaryKey;autoIncrement:false"` // ID of the merged, deleted school
+	ToSchoolID   uint `gorm:"not null;index"`                 // Surviving school
+	MergedBy     uint // Admin who merged the schools
+	CreatedAt    time.Time
+}
+
+// SchoolClaim is an institution's request to manage a school, reviewed by an admin.
+// The institution is mapped to the school only once the claim is approved.
+// @Description School ownership claim
+// @Schema models.SchoolClaim
+type SchoolClaim struct {
+	GormModel
+	SchoolID             uint                  `gorm:"not null;index"`
+	School               School                `gorm:"foreignKey:SchoolID"`
+	InstitutionProfileID uint                  `gorm:"not null;index;uniqueIndex:idx_school_claims_pending_profile,where:status = 'pending' AND deleted_at IS NULL"` // One pending claim per institution
+	InstitutionProfile   InstitutionProfile    `gorm:"foreignKey:InstitutionProfileID"`
+	Status               SchoolClaimStatus     `gorm:"type:varchar(20);not null;default:'pending';index"`
+	Message              string                `gorm:"type:text"` // The institution's note to the reviewer
+	Documents            []SchoolClaimDocument `gorm:"foreignKey:ClaimID"`
+	ReviewedBy           *uint                 // Admin who approved or rejected the claim
+	ReviewedAt           *time.Time
+	ReviewNotes          string `gorm:"type:text"` // Shown to the institution
+}
+
+// SchoolClaimDocument is a verification document uploaded with a school claim
+// @Description School claim verification document
+// @Schema models.SchoolClaimDocument
+type SchoolClaimDocument struct {
+	GormModel
+	ClaimID     uint   `gorm:"not null;index"`
+	FileName    string `gorm:"not null"` // Original file name
+	ContentType string `gorm:"not null"` // Detected MIME type
+	Size        int64  `gorm:"not null"` // Bytes
+	StorageKey  string `gorm:"not null;uniqueIndex"` // Key in the file storage backend
+}
+
+// MediaBaseURL is the prefix of media URLs, set from the MEDIA_BASE_URL configuration
+var MediaBaseURL = "/media"
+
+// Media is an image of a school or an institution, stored resized in several sizes without metadata.
+// Exactly one of SchoolID and InstitutionProfileID is set.
+// @Description School or institution image
+// @Schema models.Media
+type Media struct {
+	GormModel
+	SchoolID             *uint                   `gorm:"index"`
+	InstitutionProfileID *uint                   `gorm:"index"`
+	Kind                 MediaKind               `gorm:"type:varchar(20);not null"`
+	Caption              string
+	Position             int                     `gorm:"default:0"` // Order within the gallery
+	UploadedByUserID     uint                    `gorm:"not null"`
+	Variants             map[string]MediaVariant `gorm:"type:jsonb;serializer:json;not null" json:"-"` // Stored sizes by name
+	URLs                 map[string]MediaURL     `gorm:"-"`                                            // Public URLs of the sizes by name: thumb, medium and large
+}
+
+// MediaVariant is one stored size of an image
+type MediaVariant struct {
+	Key         string // Key in the file storage backend, under "media/"
+	Width       int
+	Height      int
+	ContentType string
+}
+
+// MediaURL is the public URL of one size of an image
+// @Description Image URL and dimensions
+type MediaURL struct {
+	URL    string
+	Width  int
+	Height int
+}
+
+// AfterFind fills in the public URLs of the stored sizes
+func (m *Media) AfterFind(tx *gorm.DB) error {
+	m.setURLs()
+	return nil
+}
+
+// AfterCreate fills in the public URLs of the stored sizes
+func (m *Media) AfterCreate(tx *gorm.DB) error {
+	m.setURLs()
+	return nil
+}
+
+func (m *Media) setURLs() {
+	m.URLs = make(map[string]MediaURL, len(m.Variants))
+	for name, variant := range m.Variants {
+		m.URLs[name] = MediaURL{
+			URL:    MediaBaseURL + "/" + strings.TrimPrefix(variant.Key, "media/"),
+			Width:  variant.Width,
+			Height: variant.Height,
+		}
+	}
+}
+
+// SavedSearch is a named school or job search of a user. Searches with a frequency are run on a schedule and the
+// user is alerted about schools and jobs that newly match.
+// @Description Saved school or job search
+// @Schema models.SavedSearch
+type SavedSearch struct {
+	GormModel
+	UserID        uint                   `gorm:"not null;index"`
+	User          User                   `gorm:"foreignKey:UserID" json:"-"`
+	Name          string                 `gorm:"not null"`
+	Kind          SavedSearchKind        `gorm:"type:varchar(20);not null"`
+	Filters       map[string]interface{} `gorm:"type:jsonb;serializer:json;not null"` // Same filters as the school or job search, e.g. {"programs": ["primary"], "lat": 41.9, "lng": 12.5, "radius_km": 10}
+	Frequency     SavedSearchFrequency   `gorm:"type:varchar(20);not null;default:'daily'"`
+	LastRunAt     *time.Time             `gorm:"index"` // When the search was last run for alerts
+	LastMatchedAt *time.Time             // When the last alert about new matches was sent
+}
+
+// SavedSearchMatch records a school or job a saved search has alerted about, so it is alerted about only once
+// @Description School or job a saved search alerted about
+type SavedSearchMatch struct {
+	ID            uint      `gorm:"primarykey"`
+	SavedSearchID uint      `gorm:"not null;uniqueIndex:idx_saved_search_matches_target"`
+	TargetID      uint      `gorm:"not null;uniqueIndex:idx_saved_search_matches_target"` // School or job ID, depending on the search kind
+	CreatedAt     time.Time
+}
+
+// Recommendation is a school suggested to a parent or a job suggested to an educator. A user's recommendations
+// are replaced as a whole when they are recomputed.
+// @Description School or job recommendation
+type Recommendation struct {
+	ID        uint               `gorm:"primarykey"`
+	UserID    uint               `gorm:"not null;index"`
+	Kind      RecommendationKind `gorm:"type:varchar(20);not null"`
+	TargetID  uint               `gorm:"not null"` // School or job ID, depending on the kind
+	Score     float64            `gorm:"not null"` // From 0 to 1
+	Reasons   []string           `gorm:"type:jsonb;serializer:json;not null"` // Why the target is recommended, strongest first
+	CreatedAt time.Time
 }
 
 // InstitutionProfile for Institution and Training Center users
@@ -102,7 +488,9 @@ type InstitutionProfile struct {
 	SchoolID         *uint  `gorm:"uniqueIndex"` // A school can be mapped to only one institution/training center
 	School           *School
 	VerificationDocs string // Path to verification documents
-	IsVerified       bool   `gorm:"default:false"`
+	IsVerified       bool   `gorm:"default:false"` // Set when an admin approves the institution's school claim, shown as a public badge
+	VerifiedAt       *time.Time
+	Media            []Media `gorm:"foreignKey:InstitutionProfileID"`
 	Jobs             []Job  `gorm:"foreignKey:InstitutionProfileID"`
 }
 
@@ -118,6 +506,7 @@ type EducatorProfile struct {
 	Experience     string
 	SavedSchools   []*School        `gorm:"many2many:educator_saved_schools;"`
 	Applications   []JobApplication `gorm:"foreignKey:EducatorProfileID"`
+	RecommendationsComputedAt *time.Time `gorm:"index"` // Nil until job recommendations are first requested
 }
 
 // ParentProfile for Parent users
@@ -128,6 +517,7 @@ type ParentProfile struct {
 	UserID       uint      `gorm:"uniqueIndex;not null"`
 	User         User      // Eager load user details
 	SavedSchools []*School `gorm:"many2many:parent_saved_schools;"`
+	RecommendationsComputedAt *time.Time `gorm:"index"` // Nil until school recommendations are first requested
 	// Other parent-specific fields
 }
 
@@ -160,7 +550,7 @@ type JobApplication struct {
 	CoverLetter       string `gorm:"type:text"`
 	ResumeURL         string
 	AppliedAt         time.Time       `gorm:"autoCreateTime"`
-	Status            string          `gorm:"default:'pending'"` // e.g., pending, viewed, shortlisted, rejected
+	Status            string          `gorm:"default:'pending'"` // e.g., pending, viewed, shortlisted, rejected, hired
 	Educator          EducatorProfile `gorm:"foreignKey:EducatorProfileID"`
 }
 
@@ -169,14 +559,108 @@ type JobApplication struct {
 // @Schema models.Message
 type Message struct {
 	GormModel
-	SenderID    uint      `gorm:"not null"`
-	RecipientID uint      `gorm:"not null"`
-	Content     string    `gorm:"type:text;not null"`
-	SentAt      time.Time `gorm:"autoCreateTime"`
-	ReadAt      *time.Time
-	IsRead      bool `gorm:"default:false;index"` // Index for faster querying of unread messages
-	Sender      User `gorm:"foreignKey:SenderID"`
-	Recipient   User `gorm:"foreignKey:RecipientID"`
+	ConversationID *uint      `gorm:"index"` // Thread the message belongs to
+	SenderID       uint       `gorm:"not null"`
+	RecipientID    uint       `gorm:"not null"`
+	Content        string     `gorm:"type:text;not null"`
+	SentAt         time.Time  `gorm:"autoCreateTime"`
+	DeliveredAt    *time.Time // Set when a client of the recipient acknowledges the message
+	ReadAt         *time.Time
+	IsRead         bool `gorm:"default:false;index"` // Index for faster querying of unread messages
+	Sender         User `gorm:"foreignKey:SenderID"`
+	Recipient      User `gorm:"foreignKey:RecipientID"`
+	Attachments    []MessageAttachment `gorm:"foreignKey:MessageID"`
+}
+
+// UserBlock records that a user blocked another user. Messages between them are rejected in both directions.
+// @Description User block information
+// @Schema models.UserBlock
+type UserBlock struct {
+	GormModel
+	BlockerID uint `gorm:"not null;uniqueIndex:idx_user_block"`
+	BlockedID uint `gorm:"not null;uniqueIndex:idx_user_block;index"`
+	Blocked   User `gorm:"foreignKey:BlockedID"`
+}
+
+// MessageReport is a moderation case opened by the recipient of an abusive message
+// @Description Message report information
+// @Schema models.MessageReport
+type MessageReport struct {
+	GormModel
+	MessageID      uint                `gorm:"not null;uniqueIndex:idx_message_report_reporter"`
+	Message        Message             `gorm:"foreignKey:MessageID"`
+	ReporterID     uint                `gorm:"not null;uniqueIndex:idx_message_report_reporter"`
+	Reporter       User                `gorm:"foreignKey:ReporterID"`
+	ReportedUserID uint                `gorm:"not null;index"` // Sender of the message
+	ReportedUser   User                `gorm:"foreignKey:ReportedUserID"`
+	Reason         MessageReportReason `gorm:"type:varchar(20);not null"`
+	Details        string              `gorm:"type:text"`
+	MessageContent string              `gorm:"type:text"` // Copy of the message as reported, kept if the message is deleted
+	Status         MessageReportStatus `gorm:"type:varchar(20);not null;default:'pending';index"`
+	ReviewedBy     *uint               // Admin who reviewed the report
+	ReviewedAt     *time.Time
+	ModeratorNotes string `gorm:"type:text"`
+}
+
+// MessageAttachment is a file uploaded by a user and sent with a message
+// @Description Message attachment information
+// @Schema models.MessageAttachment
+type MessageAttachment struct {
+	GormModel
+	MessageID   *uint  `gorm:"index"` // Nil until the attachment is sent with a message
+	UploaderID  uint   `gorm:"not null;index"`
+	FileName    string `gorm:"not null"` // Original file name, shown to the recipient
+	ContentType string `gorm:"not null"` // Detected MIME type
+	Size        int64  `gorm:"not null"` // Bytes
+	StorageKey  string `gorm:"not null;uniqueIndex"` // Key in the file storage backend
+	Checksum    string // Hex-encoded SHA-256 of the content
+}
+
+// ImportJob tracks an uploaded file that is imported in the background
+// @Description Import job information
+// @Schema models.ImportJob
+type ImportJob struct {
+	GormModel
+	CreatedByUserID uint            `gorm:"not null;index"` // Admin who uploaded the file and receives progress updates
+	FileName        string          `gorm:"not null"`       // Original file name
+	Format          string          `gorm:"type:varchar(10);not null"`
+	FileKey         string          // Key of the uploaded file in the file storage backend, cleared once processed
+	ColumnMapping   string          `gorm:"type:text"` // JSON object of field to column header
+	Status          ImportJobStatus `gorm:"type:varchar(20);not null;default:'queued';index"`
+	TotalRows       int
+	ProcessedRows   int
+	CreatedCount    int
+	FailedCount     int
+	ErrorFileKey    string // Key of the CSV report of rejected rows, if any
+	Error           string `gorm:"type:text"` // Why the job failed
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+}
+
+// Conversation groups the messages exchanged between its participants
+// @Description Conversation thread information
+// @Schema models.Conversation
+type Conversation struct {
+	GormModel
+	DirectKey           string `gorm:"uniqueIndex"` // "<lower user ID>:<higher user ID>" for one-to-one conversations
+	LastMessageID       *uint
+	LastMessageAt       *time.Time `gorm:"index"`
+	LastMessagePreview  string     // Truncated content of the last message
+	LastMessageSenderID *uint
+	Participants        []ConversationParticipant `gorm:"foreignKey:ConversationID"`
+}
+
+// ConversationParticipant holds a user's per-conversation state
+// @Description Conversation participant information
+// @Schema models.ConversationParticipant
+type ConversationParticipant struct {
+	GormModel
+	ConversationID uint `gorm:"not null;uniqueIndex:idx_conversation_participant"`
+	UserID         uint `gorm:"not null;uniqueIndex:idx_conversation_participant;index"`
+	User           User `gorm:"foreignKey:UserID"`
+	LastReadAt     *time.Time
+	IsArchived     bool `gorm:"default:false"` // Hidden from the inbox until a new message arrives
+	IsMuted        bool `gorm:"default:false"` // No notifications for new messages
 }
 
 // ActionLog for admin to track user actions
@@ -217,6 +701,7 @@ type Event struct {
 	IsPublished     bool      `gorm:"default:false"`
 	IsFeatured      bool      `gorm:"default:false"`
 	MaxAttendees    int       // Maximum number of attendees, 0 for unlimited
+	ReminderSentAt  *time.Time // When the upcoming-event reminder was sent to interested users
 	// I18n support
 	LocalizedTitles       map[string]string `gorm:"type:jsonb"` // e.g., {"en": "Title", "es": "Título"}
 	LocalizedDescriptions map[string]string `gorm:"type:jsonb"` // e.g., {"en": "Description", "es": "Descripción"}
@@ -280,9 +765,78 @@ type Review struct {
 	ModeratorNotes string `gorm:"type:text"` // Notes from the moderator
 }
 
+// NotificationPreference stores a user's opt-in/opt-out choice for a category on a channel.
+// A missing row means the channel is enabled for that category. Enabled has no column default:
+// GORM would insert the default in place of false and opting out would silently keep the channel enabled.
+// @Description Notification preference information
+// @Schema models.NotificationPreference
+type NotificationPreference struct {
+	GormModel
+	UserID   uint                 `gorm:"not null;uniqueIndex:idx_notification_pref_user_category_channel"`
+	User     User                 `gorm:"foreignKey:UserID"`
+	Category NotificationCategory `gorm:"type:varchar(30);not null;uniqueIndex:idx_notification_pref_user_category_channel"`
+	Channel  NotificationChannel  `gorm:"type:varchar(20);not null;uniqueIndex:idx_notification_pref_user_category_channel"`
+	Enabled  bool                 `gorm:"not null"`
+}
+
+// DigestItem is a notification email waiting to be batched into a user's digest
+// @Description Pending digest item information
+// @Schema models.DigestItem
+type DigestItem struct {
+	GormModel
+	UserID     uint                 `gorm:"not null;index"` // Recipient
+	User       User                 `gorm:"foreignKey:UserID"`
+	Category   NotificationCategory `gorm:"type:varchar(30);not null"`
+	Title      string               `gorm:"not null"`
+	Summary    string               `gorm:"type:text"`
+	SourceType string               // e.g., "Message", "JobApplication", "Event", "Review"
+	SourceID   uint
+	SentAt     *time.Time `gorm:"index"` // Set once the item was included in a digest
+}
+
+// Notification is an in-app notification shown in the user's notification center
+// @Description In-app notification information
+// @Schema models.Notification
+type Notification struct {
+	GormModel
+	UserID   uint                   `gorm:"not null;index:idx_notifications_user_read"` // Recipient
+	User     User                   `gorm:"foreignKey:UserID"`
+	Category NotificationCategory   `gorm:"type:varchar(30);not null"`
+	Type     NotificationType       `gorm:"type:varchar(50);not null"`
+	Title    string                 `gorm:"not null"`
+	Body     string                 `gorm:"type:text"`
+	Payload  map[string]interface{} `gorm:"type:jsonb;serializer:json"` // Type-specific data, e.g. {"message_id": 1, "sender_id": 2}
+	DeepLink string                 // App path to open, e.g. "/messages/2"
+	IsRead   bool                   `gorm:"default:false;index:idx_notifications_user_read"`
+	ReadAt   *time.Time
+}
+
+// EmailEvent is a bounce or complaint reported by the email provider
+// @Description Email delivery event information
+// @Schema models.EmailEvent
+type EmailEvent struct {
+	GormModel
+	Provider          string         `gorm:"type:varchar(20);not null"` // e.g., "ses", "sendgrid", "postmark"
+	Type              EmailEventType `gorm:"type:varchar(20);not null;index"`
+	Email             string         `gorm:"not null;index"`
+	UserID            *uint          `gorm:"index"` // Nil if the address does not belong to a user
+	User              *User          `gorm:"foreignKey:UserID"`
+	Reason            string         `gorm:"type:text"` // Diagnostic code or description from the provider
+	ProviderMessageID string
+	OccurredAt        time.Time
+}
+
+// ScheduledTask tracks the pending tick of a periodic task run by the scheduler. Replicas lock the row
+// before seeding a task, so starting several of them together publishes a single chain of ticks.
+type ScheduledTask struct {
+	Name      string     `gorm:"primaryKey;type:varchar(100)"`
+	NextRunAt *time.Time // When the pending tick is due, nil until the task is first seeded
+	UpdatedAt time.Time
+}
+
 // AutoMigrate runs GORM's auto migration.
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&User{},
 		&School{},
 		&InstitutionProfile{},
@@ -296,5 +850,158 @@ func AutoMigrate(db *gorm.DB) error {
 		&BlogPost{},
 		&Subscription{},
 		&Review{},
+		&NotificationPreference{},
+		&DigestItem{},
+		&EmailEvent{},
+		&Notification{},
+		&Conversation{},
+		&ConversationParticipant{},
+		&MessageAttachment{},
+		&UserBlock{},
+		&MessageReport{},
+		&ImportJob{},
+		&SchoolDuplicate{},
+		&SchoolRedirect{},
+		&SchoolClaim{},
+		&SchoolClaimDocument{},
+		&Media{},
+		&SavedSearch{},
+		&SavedSearchMatch{},
+		&Recommendation{},
+		&ScheduledTask{},
 	)
+	if err != nil {
+		return err
+	}
+	if err := backfillConversations(db); err != nil {
+		return err
+	}
+	if err := backfillSchoolClaims(db); err != nil {
+		return err
+	}
+	return createSearchIndexes(db)
+}
+
+// MessageSearchConfig is the text search configuration of the message content index. Messages are written
+// in many languages, so words are matched without language-specific stemming.
+const MessageSearchConfig = "simple"
+
+// SchoolSearchDocument is the weighted text search document of a school: name first, then city and state,
+// then address. It is the expression of the school search index.
+const SchoolSearchDocument = "(setweight(to_tsvector('simple', coalesce(schools.name, '')), 'A') || " +
+	"setweight(to_tsvector('simple', coalesce(schools.city, '') || ' ' || coalesce(schools.state, '')), 'B') || " +
+	"setweight(to_tsvector('simple', coalesce(schools.address, '')), 'C'))"
+
+// createSearchIndexes creates the full-text, trigram, location and attribute indexes GORM cannot declare with struct tags.
+// Queries must use the same expressions for Postgres to use the indexes.
+func createSearchIndexes(db *gorm.DB) error {
+	statements := []string{
+		`CREATE INDEX IF NOT EXISTS idx_messages_content_search ON messages USING GIN (to_tsvector('` + MessageSearchConfig + `', content))`,
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+		`CREATE INDEX IF NOT EXISTS idx_schools_search ON schools USING GIN (` + SchoolSearchDocument + `)`,
+		`CREATE INDEX IF NOT EXISTS idx_schools_name_trgm ON schools USING GIN (name gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_schools_city_trgm ON schools USING GIN (city gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_schools_state_lower ON schools (LOWER(state))`,
+		`CREATE EXTENSION IF NOT EXISTS cube`,
+		`CREATE EXTENSION IF NOT EXISTS earthdistance`,
+		`CREATE INDEX IF NOT EXISTS idx_schools_location ON schools USING GIST (ll_to_earth(latitude, longitude))`,
+		`CREATE INDEX IF NOT EXISTS idx_schools_programs ON schools USING GIN (programs)`,
+		`CREATE INDEX IF NOT EXISTS idx_schools_languages ON schools USING GIN (languages)`,
+		`CREATE INDEX IF NOT EXISTS idx_schools_schedules ON schools USING GIN (schedules)`,
+		`CREATE INDEX IF NOT EXISTS idx_schools_accreditations ON schools USING GIN (accreditations)`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SchoolOwnershipStatusSQL computes the ownership status of the school in the current `schools` row from its
+// claims: verified while the institution of an approved claim is mapped to it, pending while claims await review.
+const SchoolOwnershipStatusSQL = `CASE
+	WHEN EXISTS (SELECT 1 FROM school_claims c JOIN institution_profiles p ON p.id = c.institution_profile_id
+		WHERE c.school_id = schools.id AND p.school_id = schools.id AND c.status = 'approved'
+		AND c.deleted_at IS NULL AND p.deleted_at IS NULL) THEN 'verified'
+	WHEN EXISTS (SELECT 1 FROM school_claims c WHERE c.school_id = schools.id AND c.status = 'pending'
+		AND c.deleted_at IS NULL) THEN 'pending'
+	ELSE 'unclaimed' END`
+
+// backfillSchoolClaims records a claim for every institution that selected a school before claims were reviewed:
+// approved if the institution was already verified, otherwise pending so an admin reviews the mapping.
+func backfillSchoolClaims(db *gorm.DB) error {
+	var pending int64
+	if err := db.Model(&InstitutionProfile{}).
+		Where("school_id IS NOT NULL").
+		Where("NOT EXISTS (SELECT 1 FROM school_claims c WHERE c.institution_profile_id = institution_profiles.id AND c.school_id = institution_profiles.school_id)").
+		Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending == 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`INSERT INTO school_claims (created_at, updated_at, school_id, institution_profile_id, status, message)
+			SELECT NOW(), NOW(), p.school_id, p.id, CASE WHEN p.is_verified THEN 'approved' ELSE 'pending' END,
+				'Selected before school claims were reviewed'
+			FROM institution_profiles p
+			WHERE p.school_id IS NOT NULL AND p.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM school_claims c WHERE c.institution_profile_id = p.id AND c.school_id = p.school_id)`,
+			`UPDATE schools SET ownership_status = ` + SchoolOwnershipStatusSQL + `
+			WHERE id IN (SELECT school_id FROM school_claims)`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// backfillConversations groups messages stored before conversations existed into one-to-one conversations.
+func backfillConversations(db *gorm.DB) error {
+	var pending int64
+	if err := db.Model(&Message{}).Where("conversation_id IS NULL").Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending == 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`INSERT INTO conversations (created_at, updated_at, direct_key)
+			SELECT MIN(sent_at), NOW(), LEAST(sender_id, recipient_id)::text || ':' || GREATEST(sender_id, recipient_id)::text
+			FROM messages WHERE conversation_id IS NULL AND deleted_at IS NULL
+			GROUP BY LEAST(sender_id, recipient_id), GREATEST(sender_id, recipient_id)
+			ON CONFLICT (direct_key) DO NOTHING`,
+			`UPDATE messages m SET conversation_id = c.id FROM conversations c
+			WHERE m.conversation_id IS NULL
+			AND c.direct_key = LEAST(m.sender_id, m.recipient_id)::text || ':' || GREATEST(m.sender_id, m.recipient_id)::text`,
+			`INSERT INTO conversation_participants (created_at, updated_at, conversation_id, user_id)
+			SELECT NOW(), NOW(), conversation_id, user_id FROM (
+				SELECT conversation_id, sender_id AS user_id FROM messages WHERE conversation_id IS NOT NULL
+				UNION SELECT conversation_id, recipient_id FROM messages WHERE conversation_id IS NOT NULL
+			) p
+			ON CONFLICT (conversation_id, user_id) DO NOTHING`,
+			`UPDATE conversations c SET last_message_id = m.id, last_message_at = m.sent_at,
+				last_message_preview = LEFT(m.content, 200), last_message_sender_id = m.sender_id
+			FROM (
+				SELECT DISTINCT ON (conversation_id) id, conversation_id, sent_at, content, sender_id
+				FROM messages WHERE conversation_id IS NOT NULL AND deleted_at IS NULL
+				ORDER BY conversation_id, id DESC
+			) m
+			WHERE c.id = m.conversation_id AND (c.last_message_id IS NULL OR c.last_message_id < m.id)`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
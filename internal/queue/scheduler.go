@@ -0,0 +1,130 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mwc_backend/internal/models"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	SchedulerDelayExchange  = "scheduler.delay.exchange"  // Exchange ticks are published to with a TTL
+	SchedulerActualExchange = "scheduler.actual.exchange" // DLX where ticks go once their TTL expires
+)
+
+// ScheduledTaskFunc is the work performed on every tick of a scheduled task.
+type ScheduledTaskFunc func(ctx context.Context) error
+
+// scheduledTick is the payload of a tick message.
+type scheduledTick struct {
+	Task        string    `json:"task"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// Scheduler runs named periodic tasks on top of RabbitMQ delayed messages.
+// Each task gets its own delay queue (per-message TTL only expires at the head of a queue,
+// so tasks with different intervals cannot share one). When a tick is consumed the task runs
+// and the next tick is published, so only one node runs a given tick. The due time of the pending
+// tick is kept in the database, where replicas lock it before seeding a task.
+type Scheduler struct {
+	mq MessageQueueService
+	db *gorm.DB
+}
+
+// NewScheduler creates a new Scheduler.
+func NewScheduler(mq MessageQueueService, db *gorm.DB) *Scheduler {
+	return &Scheduler{mq: mq, db: db}
+}
+
+// Register declares the topology for a task, starts consuming its ticks and seeds the first tick
+// if none is pending. It is a no-op when RabbitMQ is not initialized.
+func (s *Scheduler) Register(name string, interval time.Duration, run ScheduledTaskFunc) error {
+	if s.mq == nil || !s.mq.IsInitialized() {
+		log.Printf("RabbitMQ service not initialized, scheduled task '%s' will not run.", name)
+		return nil
+	}
+
+	delayQueue := fmt.Sprintf("q.scheduler.%s.delay", name)
+	processingQueue := fmt.Sprintf("q.scheduler.%s", name)
+	routingKey := fmt.Sprintf("scheduler.%s", name)
+
+	if err := s.mq.DeclareDelayedMessageExchangeAndQueue(SchedulerDelayExchange, delayQueue, SchedulerActualExchange, routingKey); err != nil {
+		return fmt.Errorf("failed to declare delay topology for task '%s': %w", name, err)
+	}
+	pending, err := s.mq.DeclareQueue(processingQueue, true, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to declare processing queue for task '%s': %w", name, err)
+	}
+	if err := s.mq.BindQueue(processingQueue, routingKey, SchedulerActualExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind processing queue for task '%s': %w", name, err)
+	}
+
+	// publishNext publishes the next tick and records when it is due
+	publishNext := func(ctx context.Context, db *gorm.DB) error {
+		next := time.Now().Add(interval)
+		body, err := json.Marshal(scheduledTick{Task: name, ScheduledAt: next})
+		if err != nil {
+			return err
+		}
+		if err := s.mq.Publish(ctx, SchedulerDelayExchange, delayQueue, body, int32(interval/time.Millisecond)); err != nil {
+			return err
+		}
+		return db.Model(&models.ScheduledTask{}).Where("name = ?", name).Update("next_run_at", next).Error
+	}
+
+	err = s.mq.Consume(processingQueue, "scheduler-"+name, func(delivery amqp.Delivery) error {
+		ctx := context.Background()
+		// Schedule the next tick first so a failing run does not stop the chain.
+		if err := publishNext(ctx, s.db); err != nil {
+			log.Printf("Scheduler: failed to schedule next tick for task '%s': %v", name, err)
+		}
+		start := time.Now()
+		if err := run(ctx); err != nil {
+			return fmt.Errorf("scheduled task '%s' failed: %w", name, err)
+		}
+		log.Printf("Scheduler: task '%s' completed in %s", name, time.Since(start))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to consume ticks for task '%s': %w", name, err)
+	}
+
+	// Seed the chain only if no tick is pending, so restarts and extra replicas do not multiply ticks.
+	// The task row is locked while checking, so replicas starting together seed it once.
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ScheduledTask{Name: name}).Error; err != nil {
+			return err
+		}
+		var task models.ScheduledTask
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", name).First(&task).Error; err != nil {
+			return err
+		}
+		// A tick is pending unless the last one is overdue by a whole interval, e.g. after RabbitMQ lost it
+		if task.NextRunAt != nil && time.Since(*task.NextRunAt) < interval {
+			return nil
+		}
+		delayed, err := s.mq.DeclareQueue(delayQueue, true, false, false, false, amqp.Table{
+			"x-dead-letter-exchange":    SchedulerActualExchange,
+			"x-dead-letter-routing-key": routingKey,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to inspect delay queue: %w", err)
+		}
+		if delayed.Messages > 0 || pending.Messages > 0 {
+			return nil
+		}
+		return publishNext(context.Background(), tx)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to seed first tick for task '%s': %w", name, err)
+	}
+
+	log.Printf("Scheduler: registered task '%s' every %s", name, interval)
+	return nil
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ErrInfected is returned by a Scanner when the file contains malware.
+var ErrInfected = errors.New("file is infected")
+
+// Scanner checks an uploaded file before it is stored.
+type Scanner interface {
+	// Scan returns nil for a clean file, an error wrapping ErrInfected for malware, or another error if scanning failed.
+	Scan(ctx context.Context, name string, r io.Reader) error
+}
+
+// NoopScanner accepts every file. It is used when no virus scanner is configured.
+type NoopScanner struct{}
+
+// Scan accepts the file without inspecting it.
+func (NoopScanner) Scan(ctx context.Context, name string, r io.Reader) error {
+	return nil
+}
+
+// ClamdScanner scans files with a clamd daemon using the INSTREAM command.
+type ClamdScanner struct {
+	address string // host:port of clamd
+	timeout time.Duration
+}
+
+// NewClamdScanner creates a scanner for the clamd daemon listening on address.
+func NewClamdScanner(address string) *ClamdScanner {
+	return &ClamdScanner{address: address, timeout: 60 * time.Second}
+}
+
+// Scan streams the file to clamd in chunks and interprets its verdict.
+func (s *ClamdScanner) Scan(ctx context.Context, name string, r io.Reader) error {
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(s.timeout))
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+	chunk := make([]byte, 32*1024)
+	size := make([]byte, 4)
+	for {
+		n, readErr := r.Read(chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return err
+			}
+			if _, err := conn.Write(chunk[:n]); err != nil {
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	// A zero-length chunk ends the stream
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return err
+	}
+
+	reply, err := io.ReadAll(conn)
+	if err != nil {
+		return fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	verdict := strings.TrimSpace(string(bytes.TrimRight(reply, "\x00")))
+	switch {
+	case strings.HasSuffix(verdict, "OK"):
+		return nil
+	case strings.HasSuffix(verdict, "FOUND"):
+		return fmt.Errorf("%w: %s", ErrInfected, strings.TrimSuffix(strings.TrimPrefix(verdict, "stream: "), " FOUND"))
+	}
+	return fmt.Errorf("clamd scan of %s failed: %s", name, verdict)
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when no object is stored under the key.
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores files under opaque keys such as "attachments/2024/05/<uuid>.pdf".
+type Storage interface {
+	// Put stores size bytes read from r under key.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Config selects and configures a storage backend.
+type Config struct {
+	Backend   string // "local" or "s3"
+	LocalPath string // Root directory of the local backend
+	S3        S3Config
+}
+
+// New creates the configured storage backend.
+func New(ctx context.Context, cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return NewLocalStorage(cfg.LocalPath)
+	case "s3":
+		return NewS3Storage(ctx, cfg.S3)
+	}
+	return nil, errors.New("unknown storage backend: " + cfg.Backend)
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTrip stores, reads and deletes an object, checking the behaviour every backend shares
+func roundTrip(t *testing.T, s Storage, key string) {
+	t.Helper()
+	ctx := context.Background()
+	data := []byte("%PDF-1.4 attachment body")
+
+	if err := s.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/pdf"); err != nil {
+		t.Fatalf("Put(%s): %v", key, err)
+	}
+	r, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", key, err)
+	}
+	got, err := io.ReadAll(r)
+	r.Close()
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("Get(%s) = %q, %v, want %q", key, got, err, data)
+	}
+
+	// Putting again replaces the object
+	replaced := []byte("replaced")
+	if err := s.Put(ctx, key, bytes.NewReader(replaced), int64(len(replaced)), "text/plain"); err != nil {
+		t.Fatalf("Put(%s) again: %v", key, err)
+	}
+	if r, err = s.Get(ctx, key); err != nil {
+		t.Fatalf("Get(%s) after replacing: %v", key, err)
+	}
+	got, _ = io.ReadAll(r)
+	r.Close()
+	if !bytes.Equal(got, replaced) {
+		t.Errorf("Get(%s) after replacing = %q, want %q", key, got, replaced)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete(%s): %v", key, err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(%s) after Delete: %v, want ErrNotFound", key, err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("Delete(%s) of a missing object: %v", key, err)
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	roundTrip(t, s, "attachments/2024/05/report.pdf")
+
+	if _, err := s.Get(context.Background(), "attachments/missing.pdf"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a missing key: %v, want ErrNotFound", err)
+	}
+	if _, err := NewLocalStorage(""); err == nil {
+		t.Error("NewLocalStorage without a path succeeded, want an error")
+	}
+}
+
+func TestLocalStorageStaysInRoot(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "storage")
+	s, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	ctx := context.Background()
+	outside := filepath.Join(parent, "outside.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"../outside.txt", "/../outside.txt", "a/../../outside.txt", "a/../b.txt", "..\\outside.txt", "../../../../etc/passwd",
+		// Keys that resolve to the root itself
+		"", ".", "/", "./."} {
+		if err := s.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("Put(%q) succeeded, want an error", key)
+		}
+		if _, err := s.Get(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%q) = %v, want an invalid key error", key, err)
+		}
+		if err := s.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) succeeded, want an error", key)
+		}
+	}
+	if content, err := os.ReadFile(outside); err != nil || string(content) != "secret" {
+		t.Errorf("a file outside the root was changed: %q, %v", content, err)
+	}
+	if entries, _ := os.ReadDir(parent); len(entries) != 2 {
+		t.Errorf("files were written next to the root: %v", entries)
+	}
+}
+
+func TestLocalStoragePutChecksSize(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	ctx := context.Background()
+	if err := s.Put(ctx, "short.txt", strings.NewReader("abc"), 10, "text/plain"); err == nil {
+		t.Error("Put with fewer bytes than the size succeeded, want an error")
+	}
+	// A failed Put leaves no object and no temporary file behind
+	if _, err := s.Get(ctx, "short.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after a failed Put: %v, want ErrNotFound", err)
+	}
+	if entries, _ := os.ReadDir(s.root); len(entries) != 0 {
+		t.Errorf("a failed Put left files behind: %v", entries)
+	}
+	// A negative size accepts any length
+	if err := s.Put(ctx, "unknown.txt", strings.NewReader("abc"), -1, "text/plain"); err != nil {
+		t.Errorf("Put with an unknown size: %v", err)
+	}
+}
+
+// TestS3Storage runs against the S3-compatible service in TEST_S3_ENDPOINT, such as a local MinIO
+func TestS3Storage(t *testing.T) {
+	endpoint := os.Getenv("TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("TEST_S3_ENDPOINT is not set")
+	}
+	env := func(name, fallback string) string {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+		return fallback
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	s, err := NewS3Storage(ctx, S3Config{
+		Endpoint:  endpoint,
+		AccessKey: env("TEST_S3_ACCESS_KEY", "minioadmin"),
+		SecretKey: env("TEST_S3_SECRET_KEY", "minioadmin"),
+		Bucket:    env("TEST_S3_BUCKET", "mwc-test"),
+		Region:    os.Getenv("TEST_S3_REGION"),
+		UseSSL:    os.Getenv("TEST_S3_USE_SSL") == "true",
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+	roundTrip(t, s, fmt.Sprintf("test/%d/report.pdf", time.Now().UnixNano()))
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/MarceloPetrucio/go-scalar-api-reference"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -15,6 +16,7 @@ import (
 	"mwc_backend/internal/email"
 	"mwc_backend/internal/models"
 	"mwc_backend/internal/queue"
+	"mwc_backend/internal/storage"
 	"mwc_backend/internal/store"
 	"os"
 )
@@ -81,6 +83,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	models.MediaBaseURL = cfg.MediaBaseURL
 
 	// Initialize Database connection
 	db, err := store.NewConnection(cfg.DatabaseURL)
@@ -115,8 +118,32 @@ func main() {
 	emailService := email.NewGoMailerService(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.EmailFrom)
 	log.Println("Email service initialized.")
 
+	// Initialize file storage for message attachments. Attachments are disabled if it is unavailable.
+	fileStorage, err := storage.New(context.Background(), storage.Config{
+		Backend:   cfg.StorageBackend,
+		LocalPath: cfg.StorageLocalPath,
+		S3: storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			Bucket:    cfg.S3Bucket,
+			Region:    cfg.S3Region,
+			UseSSL:    cfg.S3UseSSL,
+		},
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to initialize %s file storage: %v. Message attachments will be unavailable.", cfg.StorageBackend, err)
+		fileStorage = nil
+	} else {
+		log.Printf("File storage initialized (%s).", cfg.StorageBackend)
+	}
+
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
+		// Bodies above the default 4 MB BodyLimit are streamed instead of rejected. Only the upload routes
+		// accept them, up to their own limit; every other route rejects them (see api.SetupRoutes).
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 		// Global error handler
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
@@ -158,7 +185,7 @@ func main() {
 	})
 
 	// Setup API routes
-	api.SetupRoutes(app, db, rabbitMQService, emailService, cfg)
+	api.SetupRoutes(app, db, rabbitMQService, emailService, fileStorage, cfg)
 
 	// Setup static route for Swagger JSON files
 	app.Static("/docs", "./docs")